@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ranjithkumar/sentinelai/internal/agent"
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+	"github.com/ranjithkumar/sentinelai/pkg/config"
+	"github.com/ranjithkumar/sentinelai/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func main() {
+	cfg, err := config.LoadAgent()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	zlog, err := logger.New(cfg.Env)
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer func() {
+		_ = zlog.Sync()
+	}()
+
+	var roots *x509.CertPool
+	if cfg.TLSCAFile != "" {
+		if roots, err = monitor.LoadRootCAs(cfg.TLSCAFile); err != nil {
+			zlog.Fatal("Failed to load TLS CA file", zap.Error(err), zap.String("path", cfg.TLSCAFile))
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	client := agent.NewClient(cfg.ServerURL, cfg.Token)
+	runner := agent.NewRunner(client, monitor.NewProber(zlog, roots), zlog, cfg.Name, cfg.Location, cfg.Concurrency, time.Duration(cfg.PollInterval)*time.Second)
+	runner.Run(ctx)
+
+	zlog.Info("Agent stopped cleanly")
+}
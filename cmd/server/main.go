@@ -8,8 +8,11 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/ranjithkumar/sentinelai/internal/cluster"
+	"github.com/ranjithkumar/sentinelai/internal/escalation"
 	"github.com/ranjithkumar/sentinelai/internal/llm"
 	"github.com/ranjithkumar/sentinelai/internal/monitor"
+	"github.com/ranjithkumar/sentinelai/internal/notify"
 	"github.com/ranjithkumar/sentinelai/internal/server"
 	"github.com/ranjithkumar/sentinelai/pkg/config"
 	"github.com/ranjithkumar/sentinelai/pkg/logger"
@@ -40,11 +43,51 @@ func main() {
 
 	llmProvider := llm.NewOllamaProvider(cfg.OllamaURL, cfg.LLMModel)
 
-	workerPool := monitor.NewWorkerPool(10, container.MonitorRepo, zlog, llmProvider)
+	workerPool := monitor.NewWorkerPool(cfg.WorkerPoolSize, container.MonitorRepo, zlog, llmProvider)
+	workerPool.SetFlapPolicy(time.Duration(cfg.FlapWindow)*time.Minute, cfg.FlapThreshold)
+	workerPool.SetMaintenance(container.MaintenanceSvc)
+	workerPool.SetRemote(container.RemoteDispatcher)
+	workerPool.SetLease(cfg.NodeID, time.Duration(cfg.LeaseTTL)*time.Second)
+	if cfg.TLSCAFile != "" {
+		roots, err := monitor.LoadRootCAs(cfg.TLSCAFile)
+		if err != nil {
+			zlog.Fatal("Failed to load TLS CA file", zap.Error(err), zap.String("path", cfg.TLSCAFile))
+		}
+		workerPool.SetRootCAs(roots)
+	}
+	notifyDispatcher := notify.NewDispatcher(container.NotifySvc, zlog)
+	notifyDispatcher.Start(engineCtx)
+	container.NotifySvc.SetDispatcher(notifyDispatcher)
+
+	workerPool.AddObserver(container.IncidentSvc)
+	workerPool.AddObserver(container.NotifySvc)
 	workerPool.Start(engineCtx)
+	container.MonitorSvc.SetPingQueue(workerPool)
+	container.WorkerPool = workerPool
+
+	// Replicas sharing a database elect one leader to run the scheduler and escalations; a new
+	// leader takes over the leases of nodes that went down mid-check
+	elector := cluster.NewElector(container.ClusterRepo, zlog, cfg.NodeID, time.Duration(cfg.LeaderTTL)*time.Second)
 
 	scheduler := monitor.NewScheduler(container.MonitorRepo, workerPool, zlog, cfg.SchedulerInterval)
+	scheduler.SetMaintenance(container.MaintenanceSvc)
+	scheduler.SetLease(cfg.NodeID, time.Duration(cfg.LeaseTTL)*time.Second)
+	scheduler.SetLeadership(elector)
+	elector.OnElected(scheduler.ReclaimLeasesOf)
+	elector.Start(engineCtx)
 	scheduler.Start(engineCtx)
+	container.Scheduler = scheduler
+	container.Elector = elector
+
+	escalationRunner := escalation.NewRunner(container.EscalationRepo, container.IncidentRepo, container.NotifyRepo, container.NotifySvc, zlog, time.Duration(cfg.EscalationTick)*time.Second)
+	escalationRunner.SetLeadership(elector)
+	escalationRunner.Start(engineCtx)
+
+	if cfg.ResultRetention > 0 {
+		pruner := monitor.NewPruner(container.MonitorRepo, zlog, time.Duration(cfg.ResultRetention)*24*time.Hour)
+		pruner.SetLeadership(elector)
+		pruner.Start(engineCtx)
+	}
 
 	srv := server.New(cfg, zlog, container)
 
@@ -60,6 +103,8 @@ func main() {
 
 	zlog.Info("Shutdown signal received")
 	engineCancel()
+	elector.Leave()
+	scheduler.ReleaseLeases()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
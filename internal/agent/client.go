@@ -0,0 +1,107 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+)
+
+// Client calls the server's agent endpoints on behalf of a probe agent. It registers with the
+// shared agent token and authenticates later calls with the credential the server issued.
+type Client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+
+	mu         sync.RWMutex
+	credential string
+}
+
+// NewClient creates a client for the server at baseURL, e.g. http://localhost:8080
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/") + "/api/v1/agents",
+		token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Register announces the agent and its location and keeps the credential issued for it
+func (c *Client) Register(ctx context.Context, req RegisterReq) error {
+	var res RegisterResponse
+	if err := c.do(ctx, "/register", c.token, req, &res); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.credential = res.Credential
+	c.mu.Unlock()
+	return nil
+}
+
+func (c *Client) agentCredential() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.credential
+}
+
+// Jobs pulls up to limit jobs for the agent's location
+func (c *Client) Jobs(ctx context.Context, id string, limit int) ([]monitor.RemoteJob, error) {
+	var jobs []monitor.RemoteJob
+	if err := c.do(ctx, fmt.Sprintf("/%s/jobs?limit=%d", id, limit), c.agentCredential(), nil, &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// Report pushes the result of a job
+func (c *Client) Report(ctx context.Context, id string, req ReportReq) error {
+	return c.do(ctx, "/"+id+"/results", c.agentCredential(), req, nil)
+}
+
+// do posts body with the bearer token and decodes the data of the response envelope into out. A
+// 404 is returned as ErrAgentNotFound and a 401 on an agent call as ErrInvalidCredential; either
+// means the agent must register again.
+func (c *Client) do(ctx context.Context, path, bearer string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+bearer)
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var envelope struct {
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("decoding response: HTTP %d: %w", res.StatusCode, err)
+	}
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return ErrAgentNotFound
+	case res.StatusCode == http.StatusUnauthorized && path != "/register":
+		return ErrInvalidCredential
+	case res.StatusCode != http.StatusOK:
+		return fmt.Errorf("HTTP %d: %s", res.StatusCode, envelope.Message)
+	case out != nil:
+		return json.Unmarshal(envelope.Data, out)
+	}
+	return nil
+}
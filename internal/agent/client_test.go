@@ -0,0 +1,66 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+)
+
+// newTestServer serves the agent endpoints the way the server router mounts them
+func newTestServer(t *testing.T, token string) (*httptest.Server, *inMemoryRepository) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	svc, repo := newTestService()
+	h := NewHandler(svc)
+
+	router := gin.New()
+	group := router.Group("/api/v1/agents")
+	group.POST("/register", TokenMiddleware(token), h.Register)
+	group.POST("/:id/jobs", h.Jobs)
+	group.POST("/:id/results", h.Results)
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return srv, repo
+}
+
+func TestClientUsesIssuedCredential(t *testing.T) {
+	ctx := context.Background()
+	srv, repo := newTestServer(t, "shared")
+
+	if err := NewClient(srv.URL, "wrong").Register(ctx, RegisterReq{Name: "eu-1", Location: "eu"}); err == nil {
+		t.Fatal("register with the wrong shared token succeeded")
+	}
+
+	c := NewClient(srv.URL, "shared")
+	if err := c.Register(ctx, RegisterReq{Name: "eu-1", Location: "eu"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if _, err := c.Jobs(ctx, "eu-1", 1); err != nil {
+		t.Fatalf("jobs with the issued credential: %v", err)
+	}
+
+	// The shared token alone no longer reaches an agent's jobs
+	intruder := NewClient(srv.URL, "shared")
+	intruder.credential = "shared"
+	if _, err := intruder.Jobs(ctx, "eu-1", 1); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("jobs with the shared token: err = %v, want ErrInvalidCredential", err)
+	}
+	if err := intruder.Report(ctx, "eu-1", ReportReq{JobID: "j", Result: &monitor.CheckResult{MonitorID: "m"}}); !errors.Is(err, ErrInvalidCredential) {
+		t.Errorf("report with the shared token: err = %v, want ErrInvalidCredential", err)
+	}
+
+	// A second process cannot take the name over while the first one is pulling
+	if err := intruder.Register(ctx, RegisterReq{Name: "eu-1", Location: "eu"}); err == nil {
+		t.Error("registering a live agent's name succeeded")
+	}
+
+	delete(repo.agents, "eu-1")
+	if _, err := c.Jobs(ctx, "eu-1", 1); !errors.Is(err, ErrAgentNotFound) {
+		t.Errorf("jobs after the server forgot the agent: err = %v, want ErrAgentNotFound", err)
+	}
+}
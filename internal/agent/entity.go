@@ -0,0 +1,22 @@
+package agent
+
+import "time"
+
+// aliveWindow is how recently an agent must have pulled for jobs to be listed as alive
+const aliveWindow = time.Minute
+
+// Agent is a probe process that runs checks from a location on behalf of the server. Agents are
+// identified by the name they register with, so a restarted agent keeps its identity, and
+// authenticate with the credential issued at their latest registration.
+type Agent struct {
+	ID             string    `json:"id"`
+	Location       string    `json:"location"`
+	CredentialHash string    `json:"-"`
+	RegisteredAt   time.Time `json:"registered_at"`
+	LastSeenAt     time.Time `json:"last_seen_at"`
+}
+
+// Alive reports whether the agent pulled for jobs recently
+func (a *Agent) Alive(now time.Time) bool {
+	return now.Sub(a.LastSeenAt) < aliveWindow
+}
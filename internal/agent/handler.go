@@ -0,0 +1,154 @@
+package agent
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+)
+
+// maxPull caps the jobs handed out per pull
+const maxPull = 50
+
+// Handler serves the endpoints agents register, pull jobs and push results through, and the
+// administrator listing of agents
+type Handler struct {
+	svc Service
+}
+
+// NewHandler generates a dependency-resolved Handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// AgentResponse is the DTO used to shape the API response
+type AgentResponse struct {
+	ID           string    `json:"id"`
+	Location     string    `json:"location"`
+	RegisteredAt time.Time `json:"registered_at"`
+	LastSeenAt   time.Time `json:"last_seen_at"`
+	Alive        bool      `json:"alive"`
+}
+
+// RegisterResponse adds the credential the agent must send with its later calls; it is shown
+// only once
+type RegisterResponse struct {
+	AgentResponse
+	Credential string `json:"credential"`
+}
+
+func mapToResponse(a *Agent, now time.Time) AgentResponse {
+	return AgentResponse{
+		ID:           a.ID,
+		Location:     a.Location,
+		RegisteredAt: a.RegisteredAt,
+		LastSeenAt:   a.LastSeenAt,
+		Alive:        a.Alive(now),
+	}
+}
+
+// TokenMiddleware admits requests bearing the shared agent token, which enrols agents; with no
+// token configured agents cannot register
+func TokenMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := bearerToken(c)
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"success": false, "message": "invalid agent token", "data": nil})
+			return
+		}
+		c.Next()
+	}
+}
+
+func bearerToken(c *gin.Context) string {
+	return strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+}
+
+// Register records an agent and its location and issues its credential
+func (h *Handler) Register(c *gin.Context) {
+	var req RegisterReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid request data", "data": nil})
+		return
+	}
+
+	a, credential, err := h.svc.Register(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err, "failed to register agent")
+		return
+	}
+
+	response := RegisterResponse{AgentResponse: mapToResponse(a, time.Now()), Credential: credential}
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "agent registered", "data": response})
+}
+
+// Jobs hands the agent the checks waiting at its location, up to the limit query parameter
+func (h *Handler) Jobs(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > maxPull {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "limit must be between 1 and 50", "data": nil})
+		return
+	}
+
+	jobs, err := h.svc.Pull(c.Request.Context(), c.Param("id"), bearerToken(c), limit)
+	if err != nil {
+		respondError(c, err, "failed to pull jobs")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "jobs retrieved", "data": jobs})
+}
+
+// Results accepts the result of a job the agent was assigned
+func (h *Handler) Results(c *gin.Context) {
+	var req ReportReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid request data", "data": nil})
+		return
+	}
+
+	if err := h.svc.Report(c.Request.Context(), c.Param("id"), bearerToken(c), req); err != nil {
+		respondError(c, err, "failed to record result")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "result recorded", "data": nil})
+}
+
+// List shows every registered agent and whether it is still pulling for jobs
+func (h *Handler) List(c *gin.Context) {
+	agents, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "failed to list agents")
+		return
+	}
+
+	now := time.Now()
+	response := make([]AgentResponse, 0, len(agents))
+	for _, a := range agents {
+		response = append(response, mapToResponse(a, now))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "agents retrieved", "data": response})
+}
+
+// respondError maps service errors onto HTTP status codes, falling back to a 500 with the given message
+func respondError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrAgentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "agent not registered", "data": nil})
+	case errors.Is(err, ErrInvalidCredential):
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": err.Error(), "data": nil})
+	case errors.Is(err, ErrAgentExists):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "message": err.Error(), "data": nil})
+	case errors.Is(err, monitor.ErrUnknownJob):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "message": err.Error(), "data": nil})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": fallback, "data": nil})
+	}
+}
@@ -0,0 +1,111 @@
+package agent
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a postgres agent repository on an established connection pool
+func NewPostgresRepository(db *sql.DB) (Repository, error) {
+	if err := initSchema(db); err != nil {
+		return nil, err
+	}
+
+	return &postgresRepository{db: db}, nil
+}
+
+func initSchema(db *sql.DB) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS agents (
+			id TEXT PRIMARY KEY,
+			location TEXT NOT NULL,
+			registered_at TIMESTAMP NOT NULL,
+			last_seen_at TIMESTAMP NOT NULL
+		)`,
+		// Agents registered before credentials were issued have none and must register again
+		`ALTER TABLE agents ADD COLUMN IF NOT EXISTS credential_hash TEXT NOT NULL DEFAULT ''`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Register relies on the conditional upsert being atomic, so two processes racing to register
+// the same name cannot both receive a valid credential
+func (r *postgresRepository) Register(ctx context.Context, a *Agent, staleBefore time.Time) error {
+	query := `
+	INSERT INTO agents (id, location, credential_hash, registered_at, last_seen_at) VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (id) DO UPDATE SET location = EXCLUDED.location, credential_hash = EXCLUDED.credential_hash,
+		registered_at = EXCLUDED.registered_at, last_seen_at = EXCLUDED.last_seen_at
+	WHERE agents.credential_hash = '' OR agents.last_seen_at < $6
+	`
+	res, err := r.db.ExecContext(ctx, query, a.ID, a.Location, a.CredentialHash, a.RegisteredAt, a.LastSeenAt, staleBefore)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrAgentExists
+	}
+	return nil
+}
+
+func (r *postgresRepository) GetByID(ctx context.Context, id string) (*Agent, error) {
+	agents, err := r.query(ctx, `SELECT id, location, credential_hash, registered_at, last_seen_at FROM agents WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(agents) == 0 {
+		return nil, ErrAgentNotFound
+	}
+	return agents[0], nil
+}
+
+func (r *postgresRepository) List(ctx context.Context) ([]*Agent, error) {
+	return r.query(ctx, `SELECT id, location, credential_hash, registered_at, last_seen_at FROM agents ORDER BY location, id`)
+}
+
+func (r *postgresRepository) query(ctx context.Context, query string, args ...interface{}) ([]*Agent, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*Agent
+	for rows.Next() {
+		var a Agent
+		if err := rows.Scan(&a.ID, &a.Location, &a.CredentialHash, &a.RegisteredAt, &a.LastSeenAt); err != nil {
+			return nil, err
+		}
+		result = append(result, &a)
+	}
+	return result, rows.Err()
+}
+
+func (r *postgresRepository) Touch(ctx context.Context, id string, at time.Time) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE agents SET last_seen_at = $1 WHERE id = $2`, at, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrAgentNotFound
+	}
+	return nil
+}
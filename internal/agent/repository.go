@@ -0,0 +1,90 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Repository defines data access for probe agents
+type Repository interface {
+	// Register creates an agent or takes over the registration of one with the same ID that was
+	// last seen before staleBefore or never had a credential. It returns ErrAgentExists when the
+	// ID belongs to an agent still in use.
+	Register(ctx context.Context, a *Agent, staleBefore time.Time) error
+	GetByID(ctx context.Context, id string) (*Agent, error)
+	List(ctx context.Context) ([]*Agent, error)
+	// Touch records that an agent was seen at the given time
+	Touch(ctx context.Context, id string, at time.Time) error
+}
+
+var ErrAgentNotFound = errors.New("agent not found")
+var ErrAgentExists = errors.New("an agent with this name is already running")
+
+type inMemoryRepository struct {
+	mu     sync.RWMutex
+	agents map[string]*Agent
+}
+
+// NewRepository creates a new in-memory agent repository
+func NewRepository() Repository {
+	return &inMemoryRepository{
+		agents: make(map[string]*Agent),
+	}
+}
+
+func (r *inMemoryRepository) Register(ctx context.Context, a *Agent, staleBefore time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.agents[a.ID]; ok && existing.CredentialHash != "" && !existing.LastSeenAt.Before(staleBefore) {
+		return ErrAgentExists
+	}
+	clone := *a
+	r.agents[a.ID] = &clone
+	return nil
+}
+
+func (r *inMemoryRepository) GetByID(ctx context.Context, id string) (*Agent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a, exists := r.agents[id]
+	if !exists {
+		return nil, ErrAgentNotFound
+	}
+	clone := *a
+	return &clone, nil
+}
+
+func (r *inMemoryRepository) List(ctx context.Context) ([]*Agent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*Agent
+	for _, a := range r.agents {
+		clone := *a
+		result = append(result, &clone)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Location != result[j].Location {
+			return result[i].Location < result[j].Location
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result, nil
+}
+
+func (r *inMemoryRepository) Touch(ctx context.Context, id string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, exists := r.agents[id]
+	if !exists {
+		return ErrAgentNotFound
+	}
+	a.LastSeenAt = at
+	return nil
+}
@@ -0,0 +1,128 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+	"go.uber.org/zap"
+)
+
+// Runner is the loop of a probe agent: it registers, pulls jobs while it has free capacity, runs
+// them with the regular check logic and pushes the results back
+type Runner struct {
+	client      *Client
+	prober      *monitor.Prober
+	logger      *zap.Logger
+	name        string
+	location    string
+	concurrency int
+	interval    time.Duration
+}
+
+// NewRunner creates the loop for an agent named name at location, running up to concurrency
+// checks at once and pulling every interval
+func NewRunner(client *Client, prober *monitor.Prober, logger *zap.Logger, name, location string, concurrency int, interval time.Duration) *Runner {
+	return &Runner{
+		client:      client,
+		prober:      prober,
+		logger:      logger,
+		name:        name,
+		location:    location,
+		concurrency: concurrency,
+		interval:    interval,
+	}
+}
+
+// Run pulls and runs jobs until ctx is cancelled, then waits for checks in flight
+func (r *Runner) Run(ctx context.Context) {
+	r.logger.Info("Starting probe agent", zap.String("name", r.name), zap.String("location", r.location), zap.Int("concurrency", r.concurrency))
+	slots := make(chan struct{}, r.concurrency)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	registered := false
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		if !registered {
+			registered = r.register(ctx)
+		}
+		if registered {
+			// A server that lost its registry, e.g. after a restart, or no longer accepts the
+			// credential is registered with again
+			if err := r.pull(ctx, slots, &wg); errors.Is(err, ErrAgentNotFound) || errors.Is(err, ErrInvalidCredential) {
+				registered = false
+			} else if err != nil && ctx.Err() == nil {
+				r.logger.Warn("Failed to pull jobs", zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("Stopping probe agent")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) register(ctx context.Context) bool {
+	if err := r.client.Register(ctx, RegisterReq{Name: r.name, Location: r.location}); err != nil {
+		if ctx.Err() == nil {
+			r.logger.Warn("Failed to register with server", zap.Error(err))
+		}
+		return false
+	}
+	r.logger.Info("Registered with server", zap.String("name", r.name), zap.String("location", r.location))
+	return true
+}
+
+// pull asks for as many jobs as there are free slots and starts each in its own goroutine
+func (r *Runner) pull(ctx context.Context, slots chan struct{}, wg *sync.WaitGroup) error {
+	free := cap(slots) - len(slots)
+	if free == 0 {
+		return nil
+	}
+	jobs, err := r.client.Jobs(ctx, r.name, min(free, maxPull))
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(job monitor.RemoteJob) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			r.run(ctx, job)
+		}(job)
+	}
+	return nil
+}
+
+func (r *Runner) run(ctx context.Context, job monitor.RemoteJob) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.logger.Error("Job panic recovered", zap.Any("panic", rec), zap.String("monitor_id", job.Monitor.ID))
+		}
+	}()
+
+	result := r.prober.Probe(ctx, job.Monitor)
+	if result == nil {
+		return
+	}
+	if err := r.client.Report(ctx, r.name, ReportReq{JobID: job.ID, Result: result}); err != nil {
+		r.logger.Warn("Failed to report result", zap.Error(err), zap.String("monitor_id", job.Monitor.ID))
+		return
+	}
+	r.logger.Info("Health check executed",
+		zap.String("monitor_id", job.Monitor.ID),
+		zap.String("url", job.Monitor.URL),
+		zap.Int("status", result.StatusCode),
+		zap.Duration("latency", result.ResponseTime),
+		zap.String("outcome", string(result.Outcome())),
+	)
+}
@@ -0,0 +1,112 @@
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+)
+
+// RegisterReq defines the payload an agent registers with; the name identifies the agent
+type RegisterReq struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Location string `json:"location" binding:"required,max=50"`
+}
+
+// ReportReq defines the payload an agent pushes the result of an assigned job with
+type ReportReq struct {
+	JobID  string               `json:"job_id" binding:"required"`
+	Result *monitor.CheckResult `json:"result" binding:"required"`
+}
+
+// ErrInvalidCredential is returned when an agent calls with a credential other than the one issued
+// at its latest registration
+var ErrInvalidCredential = errors.New("invalid agent credential")
+
+// Service manages probe agents and relays jobs and results between them and the dispatcher
+type Service interface {
+	// Register records the agent and returns the credential it must present from then on. A name
+	// in use by an agent that is still alive cannot be registered again.
+	Register(ctx context.Context, req RegisterReq) (*Agent, string, error)
+	// Pull hands a registered agent up to limit jobs for its location
+	Pull(ctx context.Context, id, credential string, limit int) ([]monitor.RemoteJob, error)
+	Report(ctx context.Context, id, credential string, req ReportReq) error
+	List(ctx context.Context) ([]*Agent, error)
+}
+
+type serviceImpl struct {
+	repo       Repository
+	dispatcher *monitor.RemoteDispatcher
+}
+
+// NewService creates a new agent service feeding agents from the dispatcher
+func NewService(repo Repository, dispatcher *monitor.RemoteDispatcher) Service {
+	return &serviceImpl{repo: repo, dispatcher: dispatcher}
+}
+
+func (s *serviceImpl) Register(ctx context.Context, req RegisterReq) (*Agent, string, error) {
+	credential, err := generateCredential()
+	if err != nil {
+		return nil, "", err
+	}
+	now := time.Now()
+	a := &Agent{ID: req.Name, Location: req.Location, CredentialHash: hashCredential(credential), RegisteredAt: now, LastSeenAt: now}
+	if err := s.repo.Register(ctx, a, now.Add(-aliveWindow)); err != nil {
+		return nil, "", err
+	}
+	return a, credential, nil
+}
+
+func (s *serviceImpl) Pull(ctx context.Context, id, credential string, limit int) ([]monitor.RemoteJob, error) {
+	a, err := s.authenticate(ctx, id, credential)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Touch(ctx, id, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.dispatcher.Assign(ctx, a.ID, a.Location, limit)
+}
+
+func (s *serviceImpl) Report(ctx context.Context, id, credential string, req ReportReq) error {
+	a, err := s.authenticate(ctx, id, credential)
+	if err != nil {
+		return err
+	}
+	return s.dispatcher.Report(ctx, a.ID, a.Location, req.JobID, req.Result)
+}
+
+// authenticate loads the agent and checks credential against the one issued at its registration
+func (s *serviceImpl) authenticate(ctx context.Context, id, credential string) (*Agent, error) {
+	a, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a.CredentialHash == "" || subtle.ConstantTimeCompare([]byte(hashCredential(credential)), []byte(a.CredentialHash)) != 1 {
+		return nil, ErrInvalidCredential
+	}
+	return a, nil
+}
+
+func (s *serviceImpl) List(ctx context.Context) ([]*Agent, error) {
+	return s.repo.List(ctx)
+}
+
+// generateCredential returns a random token; only its hash is stored
+func generateCredential() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashCredential(credential string) string {
+	sum := sha256.Sum256([]byte(credential))
+	return hex.EncodeToString(sum[:])
+}
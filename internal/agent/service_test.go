@@ -0,0 +1,108 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+)
+
+func newTestService() (Service, *inMemoryRepository) {
+	repo := NewRepository().(*inMemoryRepository)
+	return NewService(repo, monitor.NewRemoteDispatcher(monitor.NewRoundRepository())), repo
+}
+
+func TestRegisterIssuesCredential(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newTestService()
+
+	a, credential, err := svc.Register(ctx, RegisterReq{Name: "eu-1", Location: "eu"})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if credential == "" || a.CredentialHash == "" || a.CredentialHash == credential {
+		t.Fatalf("credential %q stored as %q, want a credential kept only as its hash", credential, a.CredentialHash)
+	}
+	if stored := repo.agents["eu-1"]; stored.CredentialHash != hashCredential(credential) {
+		t.Errorf("stored hash %q does not match the issued credential", stored.CredentialHash)
+	}
+
+	tests := []struct {
+		name       string
+		id         string
+		credential string
+		wantErr    error
+	}{
+		{name: "issued credential", id: "eu-1", credential: credential},
+		{name: "wrong credential", id: "eu-1", credential: "guess", wantErr: ErrInvalidCredential},
+		{name: "no credential", id: "eu-1", credential: "", wantErr: ErrInvalidCredential},
+		{name: "another agent's id", id: "us-1", credential: credential, wantErr: ErrAgentNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := svc.Pull(ctx, tt.id, tt.credential, 1); !errors.Is(err, tt.wantErr) {
+				t.Errorf("pull: err = %v, want %v", err, tt.wantErr)
+			}
+			// An authenticated report reaches the dispatcher, which knows no such job
+			wantReport := tt.wantErr
+			if wantReport == nil {
+				wantReport = monitor.ErrUnknownJob
+			}
+			req := ReportReq{JobID: "j", Result: &monitor.CheckResult{MonitorID: "m"}}
+			if err := svc.Report(ctx, tt.id, tt.credential, req); !errors.Is(err, wantReport) {
+				t.Errorf("report: err = %v, want %v", err, wantReport)
+			}
+		})
+	}
+}
+
+func TestRegisterTakeover(t *testing.T) {
+	tests := []struct {
+		name     string
+		lastSeen time.Duration
+		legacy   bool
+		wantErr  error
+	}{
+		{name: "alive agent keeps its name", lastSeen: 10 * time.Second, wantErr: ErrAgentExists},
+		{name: "stale agent is replaced", lastSeen: 2 * aliveWindow},
+		{name: "agent without a credential is replaced", lastSeen: 10 * time.Second, legacy: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			svc, repo := newTestService()
+			_, first, err := svc.Register(ctx, RegisterReq{Name: "eu-1", Location: "eu"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			repo.agents["eu-1"].LastSeenAt = time.Now().Add(-tt.lastSeen)
+			if tt.legacy {
+				repo.agents["eu-1"].CredentialHash = ""
+			}
+
+			_, second, err := svc.Register(ctx, RegisterReq{Name: "eu-1", Location: "us"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("second register: err = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if _, err := svc.Pull(ctx, "eu-1", first, 1); err != nil {
+					t.Errorf("first credential after a refused takeover: %v", err)
+				}
+				if got := repo.agents["eu-1"].Location; got != "eu" {
+					t.Errorf("location = %q after a refused takeover, want eu", got)
+				}
+				return
+			}
+			if _, err := svc.Pull(ctx, "eu-1", first, 1); !errors.Is(err, ErrInvalidCredential) {
+				t.Errorf("replaced credential: err = %v, want ErrInvalidCredential", err)
+			}
+			if _, err := svc.Pull(ctx, "eu-1", second, 1); err != nil {
+				t.Errorf("new credential: %v", err)
+			}
+		})
+	}
+}
@@ -2,6 +2,12 @@ package auth
 
 import "time"
 
+// Roles carried by users and their tokens
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 // User represents the user entity
 type User struct {
 	ID           string    `json:"id"`
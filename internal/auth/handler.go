@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -63,3 +64,27 @@ func (h *Handler) Login(c *gin.Context) {
 		"data":    gin.H{"token": token},
 	})
 }
+
+// Grant changes a user's role; mounted for admins and behind the bootstrap token
+func (h *Handler) Grant(c *gin.Context) {
+	var req GrantReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid request data", "data": nil})
+		return
+	}
+
+	user, err := h.svc.Grant(c.Request.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidRole):
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error(), "data": nil})
+		case errors.Is(err, ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "user not found", "data": nil})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "failed to grant role", "data": nil})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "role granted", "data": user})
+}
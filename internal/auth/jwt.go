@@ -10,14 +10,16 @@ import (
 // Claims represents JWT claims
 type Claims struct {
 	UserID string `json:"user_id"`
+	Role   string `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
 
 // GenerateToken creates a new JWT token
-func GenerateToken(userID, secret string, expirationHours int) (string, error) {
+func GenerateToken(userID, role, secret string, expirationHours int) (string, error) {
 	exp := time.Now().Add(time.Duration(expirationHours) * time.Hour)
 	claims := &Claims{
 		UserID: userID,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(exp),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
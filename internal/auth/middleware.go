@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"crypto/subtle"
+	"errors"
 	"net/http"
 	"strings"
 
@@ -29,6 +31,39 @@ func Middleware(secret string) gin.HandlerFunc {
 		}
 
 		c.Set("userID", claims.UserID)
+		c.Set("role", claims.Role)
+		c.Next()
+	}
+}
+
+// RequireRole rejects requests from users who do not hold role; use after Middleware. The role
+// is read from the repository rather than the token, so that a demoted admin loses access
+// without waiting for their token to expire.
+func RequireRole(repo Repository, role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := repo.GetUserByID(c.Request.Context(), c.GetString("userID"))
+		if err != nil && !errors.Is(err, ErrUserNotFound) {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"success": false, "message": "failed to verify permissions", "data": nil})
+			return
+		}
+		if err != nil || user.Role != role {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"success": false, "message": "insufficient permissions", "data": nil})
+			return
+		}
+		c.Set("role", user.Role)
+		c.Next()
+	}
+}
+
+// BootstrapMiddleware admits requests bearing the operator's admin token, so that the first
+// admin can be appointed before any exists; with no token configured it rejects everything
+func BootstrapMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"success": false, "message": "invalid admin token", "data": nil})
+			return
+		}
 		c.Next()
 	}
 }
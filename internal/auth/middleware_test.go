@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireRoleReadsStoredRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	repo := NewRepository()
+	if err := repo.CreateUser(ctx, &User{ID: "u1", Email: "ops@example.com", Role: RoleAdmin}); err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	// The token still claims admin after the demotion below
+	router.GET("/admin", func(c *gin.Context) {
+		c.Set("userID", c.GetHeader("X-User"))
+		c.Set("role", RoleAdmin)
+	}, RequireRole(repo, RoleAdmin), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	request := func(userID string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+		req.Header.Set("X-User", userID)
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := request("u1"); code != http.StatusOK {
+		t.Fatalf("admin: status = %d, want 200", code)
+	}
+	if _, err := repo.SetRole(ctx, "ops@example.com", RoleUser); err != nil {
+		t.Fatal(err)
+	}
+	if code := request("u1"); code != http.StatusForbidden {
+		t.Errorf("demoted admin: status = %d, want 403", code)
+	}
+	if code := request("unknown"); code != http.StatusForbidden {
+		t.Errorf("unknown user: status = %d, want 403", code)
+	}
+}
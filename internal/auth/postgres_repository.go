@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a postgres user repository on an established connection pool
+func NewPostgresRepository(db *sql.DB) (Repository, error) {
+	if err := initSchema(db); err != nil {
+		return nil, err
+	}
+
+	return &postgresRepository{db: db}, nil
+}
+
+func initSchema(db *sql.DB) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS users (
+			id TEXT PRIMARY KEY,
+			email TEXT NOT NULL UNIQUE,
+			password_hash TEXT NOT NULL,
+			role TEXT NOT NULL DEFAULT 'user',
+			created_at TIMESTAMP NOT NULL
+		)`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const userColumns = `id, email, password_hash, role, created_at`
+
+// CreateUser relies on the unique email constraint, so concurrent registrations of one address
+// cannot both succeed
+func (r *postgresRepository) CreateUser(ctx context.Context, user *User) error {
+	query := `
+	INSERT INTO users (` + userColumns + `) VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (email) DO NOTHING
+	`
+	res, err := r.db.ExecContext(ctx, query, user.ID, user.Email, user.PasswordHash, user.Role, user.CreatedAt)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrUserExists
+	}
+	return nil
+}
+
+func (r *postgresRepository) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	return r.queryUser(ctx, `SELECT `+userColumns+` FROM users WHERE email = $1`, email)
+}
+
+func (r *postgresRepository) GetUserByID(ctx context.Context, id string) (*User, error) {
+	return r.queryUser(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id)
+}
+
+func (r *postgresRepository) SetRole(ctx context.Context, email, role string) (*User, error) {
+	return r.queryUser(ctx, `UPDATE users SET role = $1 WHERE email = $2 RETURNING `+userColumns, role, email)
+}
+
+func (r *postgresRepository) queryUser(ctx context.Context, query string, args ...interface{}) (*User, error) {
+	var u User
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&u.ID, &u.Email, &u.PasswordHash, &u.Role, &u.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
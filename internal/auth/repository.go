@@ -10,6 +10,8 @@ import (
 type Repository interface {
 	CreateUser(ctx context.Context, user *User) error
 	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	GetUserByID(ctx context.Context, id string) (*User, error)
+	SetRole(ctx context.Context, email, role string) (*User, error)
 }
 
 var ErrUserNotFound = errors.New("user not found")
@@ -51,3 +53,27 @@ func (r *inMemoryRepository) GetUserByEmail(ctx context.Context, email string) (
 	}
 	return nil, ErrUserNotFound
 }
+
+func (r *inMemoryRepository) GetUserByID(ctx context.Context, id string) (*User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return u, nil
+}
+
+func (r *inMemoryRepository) SetRole(ctx context.Context, email, role string) (*User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.users {
+		if u.Email == email {
+			u.Role = role
+			return u, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
@@ -2,7 +2,10 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"strings"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -20,21 +23,39 @@ type LoginReq struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// GrantReq defines the payload used to change a user's role
+type GrantReq struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required"`
+}
+
+// ErrInvalidRole is returned when granting a role that does not exist
+var ErrInvalidRole = errors.New("role must be user or admin")
+
 // Service defines auth business logic
 type Service interface {
 	Register(ctx context.Context, req RegisterReq) (*User, error)
 	Login(ctx context.Context, req LoginReq, secret string, expHours int) (string, error)
+	// Grant sets the role of a registered user. Admin routes check the stored role and see the
+	// change at once; the role carried in the user's token follows on their next login.
+	Grant(ctx context.Context, req GrantReq) (*User, error)
 }
 
 type serviceImpl struct {
 	repo Repository
 }
 
-// NewService creates a new auth service
+// NewService creates a new auth service. Users always register with the user role; admins are
+// appointed through Grant.
 func NewService(repo Repository) Service {
 	return &serviceImpl{repo: repo}
 }
 
+// normalizeEmail makes addresses differing only in case or surrounding space the same account
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
 func (s *serviceImpl) Register(ctx context.Context, req RegisterReq) (*User, error) {
 	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -43,9 +64,9 @@ func (s *serviceImpl) Register(ctx context.Context, req RegisterReq) (*User, err
 
 	user := &User{
 		ID:           generateID(),
-		Email:        req.Email,
+		Email:        normalizeEmail(req.Email),
 		PasswordHash: string(hash),
-		Role:         "user",
+		Role:         RoleUser,
 		CreatedAt:    time.Now(),
 	}
 
@@ -56,7 +77,7 @@ func (s *serviceImpl) Register(ctx context.Context, req RegisterReq) (*User, err
 }
 
 func (s *serviceImpl) Login(ctx context.Context, req LoginReq, secret string, expHours int) (string, error) {
-	user, err := s.repo.GetUserByEmail(ctx, req.Email)
+	user, err := s.repo.GetUserByEmail(ctx, normalizeEmail(req.Email))
 	if err != nil {
 		return "", errors.New("invalid credentials")
 	}
@@ -65,9 +86,18 @@ func (s *serviceImpl) Login(ctx context.Context, req LoginReq, secret string, ex
 		return "", errors.New("invalid credentials")
 	}
 
-	return GenerateToken(user.ID, secret, expHours)
+	return GenerateToken(user.ID, user.Role, secret, expHours)
+}
+
+func (s *serviceImpl) Grant(ctx context.Context, req GrantReq) (*User, error) {
+	if req.Role != RoleUser && req.Role != RoleAdmin {
+		return nil, ErrInvalidRole
+	}
+	return s.repo.SetRole(ctx, normalizeEmail(req.Email), req.Role)
 }
 
 func generateID() string {
-	return time.Now().Format("20060102150405")
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return time.Now().Format("20060102") + "-" + hex.EncodeToString(b)
 }
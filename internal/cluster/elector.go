@@ -0,0 +1,204 @@
+package cluster
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// nodeRetention is how long a node that stopped reporting in stays listed before being pruned
+const nodeRetention = 24 * time.Hour
+
+// A leader stops acting a quarter of the TTL before its lease expires, leaving room for clock
+// drift and for work started just before the deadline; renewals, every third of the TTL and
+// bounded by it, land well before then
+const leaseMarginDivisor = 4
+
+// Elector keeps this node's membership fresh and competes for the leadership lease, so that in a
+// multi-instance deployment a single node runs the scheduler. The lease is renewed three times
+// per TTL; when the leader dies another node takes over once its lease expires.
+type Elector struct {
+	repo   Repository
+	logger *zap.Logger
+	node   Node
+	ttl    time.Duration
+	leader atomic.Bool
+	// leadUntil is when, in UnixNano, this node stops considering itself leader unless a renewal
+	// succeeds first
+	leadUntil atomic.Int64
+	mu        sync.Mutex
+	onElected []func(ctx context.Context, departed []string)
+}
+
+// NewElector creates an elector for the node with the given ID
+func NewElector(repo Repository, logger *zap.Logger, nodeID string, ttl time.Duration) *Elector {
+	hostname, _ := os.Hostname()
+	return &Elector{
+		repo:   repo,
+		logger: logger,
+		node:   Node{ID: nodeID, Hostname: hostname, StartedAt: time.Now()},
+		ttl:    ttl,
+	}
+}
+
+// OnElected registers a callback run each time this node becomes leader, given the IDs of nodes
+// that stopped reporting in, whose in-flight work may need taking over; call before Start
+func (e *Elector) OnElected(fn func(ctx context.Context, departed []string)) {
+	e.onElected = append(e.onElected, fn)
+}
+
+// NodeID returns the ID this node takes part in the cluster with
+func (e *Elector) NodeID() string {
+	return e.node.ID
+}
+
+// IsLeader reports whether this node currently holds the leadership lease. A leader that fails to
+// renew stops considering itself leader a margin before its lease expires, before any other node
+// can take over.
+func (e *Elector) IsLeader() bool {
+	return e.leader.Load() && time.Now().UnixNano() < e.leadUntil.Load()
+}
+
+// TTL returns how long a leadership lease or node heartbeat stays valid
+func (e *Elector) TTL() time.Duration {
+	return e.ttl
+}
+
+// Start runs the first election round before returning, so the node knows whether it leads by
+// the time the scheduler starts, then keeps competing until ctx is cancelled
+func (e *Elector) Start(ctx context.Context) {
+	e.logger.Info("Joining cluster", zap.String("node_id", e.node.ID), zap.Duration("ttl", e.ttl))
+	e.round(ctx)
+	ticker := time.NewTicker(e.ttl / 3)
+
+	go func() {
+		defer ticker.Stop()
+		defer func() {
+			if r := recover(); r != nil {
+				e.logger.Error("Elector panic recovered", zap.Any("panic", r))
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				e.round(ctx)
+			}
+		}
+	}()
+}
+
+// round reports the node in and takes or renews the leadership lease. A node that cannot reach
+// the repository within a third of the TTL steps down, since it can no longer tell whether its
+// lease is still its own.
+func (e *Elector) round(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	// A round racing shutdown must not rejoin after Leave
+	if ctx.Err() != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, e.ttl/3)
+	defer cancel()
+
+	// The lease runs for the TTL from when the repository granted it, which is no earlier than
+	// now; timing it from here keeps this node's view on the safe side
+	now := time.Now()
+	e.node.LastSeenAt = now
+	if err := e.repo.Heartbeat(ctx, &e.node); err != nil {
+		e.logger.Error("Failed to record node heartbeat", zap.Error(err))
+	}
+
+	lease, err := e.repo.Acquire(ctx, e.node.ID, e.ttl)
+	if err != nil {
+		e.logger.Error("Failed to acquire leadership", zap.Error(err))
+		e.setLeader(ctx, false, "")
+		return
+	}
+	var leaderID string
+	if lease != nil {
+		leaderID = lease.NodeID
+	}
+	if leaderID == e.node.ID {
+		e.leadUntil.Store(now.Add(e.ttl - e.ttl/leaseMarginDivisor).UnixNano())
+	}
+	e.setLeader(ctx, leaderID == e.node.ID, leaderID)
+
+	if e.IsLeader() {
+		if err := e.repo.Prune(ctx, now.Add(-nodeRetention)); err != nil {
+			e.logger.Warn("Failed to prune departed nodes", zap.Error(err))
+		}
+	}
+}
+
+func (e *Elector) setLeader(ctx context.Context, leader bool, leaderID string) {
+	if e.leader.Swap(leader) == leader {
+		return
+	}
+	if !leader {
+		e.logger.Warn("Lost cluster leadership", zap.String("node_id", e.node.ID), zap.String("leader", leaderID))
+		return
+	}
+
+	e.logger.Info("Elected cluster leader", zap.String("node_id", e.node.ID))
+	departed, err := e.departed(ctx)
+	if err != nil {
+		e.logger.Warn("Failed to list departed nodes", zap.Error(err))
+	}
+	for _, fn := range e.onElected {
+		fn(ctx, departed)
+	}
+}
+
+// departed returns the IDs of other nodes that stopped reporting in
+func (e *Elector) departed(ctx context.Context) ([]string, error) {
+	nodes, err := e.repo.Nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var ids []string
+	for _, n := range nodes {
+		if n.ID != e.node.ID && !n.Alive(now, e.ttl) {
+			ids = append(ids, n.ID)
+		}
+	}
+	return ids, nil
+}
+
+// Leave resigns leadership and removes the node from the membership, so that another node takes
+// over without waiting for the lease to expire; call on shutdown once ctx passed to Start is done
+func (e *Elector) Leave() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	e.leader.Store(false)
+	if err := e.repo.Resign(ctx, e.node.ID); err != nil {
+		e.logger.Warn("Failed to resign leadership", zap.Error(err))
+	}
+	if err := e.repo.Leave(ctx, e.node.ID); err != nil {
+		e.logger.Warn("Failed to leave cluster", zap.Error(err))
+	}
+	e.logger.Info("Left cluster", zap.String("node_id", e.node.ID))
+}
+
+// Members returns the known nodes and the current leadership lease
+func (e *Elector) Members(ctx context.Context) ([]*Node, *Leadership, error) {
+	nodes, err := e.repo.Nodes(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	lease, err := e.repo.Leader(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return nodes, lease, nil
+}
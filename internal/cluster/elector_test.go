@@ -0,0 +1,108 @@
+package cluster
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// waitFor polls cond until it holds or the timeout passes
+func waitFor(t *testing.T, timeout time.Duration, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return cond()
+}
+
+func TestElectorFailover(t *testing.T) {
+	const ttl = 150 * time.Millisecond
+	repo := NewRepository()
+
+	leaderCtx, stopLeader := context.WithCancel(context.Background())
+	defer stopLeader()
+	leader := NewElector(repo, zap.NewNop(), "node-a", ttl)
+	leader.Start(leaderCtx)
+	if !leader.IsLeader() {
+		t.Fatal("first node did not take the free lease")
+	}
+
+	var mu sync.Mutex
+	var elected [][]string
+	followerCtx, stopFollower := context.WithCancel(context.Background())
+	defer stopFollower()
+	follower := NewElector(repo, zap.NewNop(), "node-b", ttl)
+	follower.OnElected(func(ctx context.Context, departed []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		elected = append(elected, departed)
+	})
+	follower.Start(followerCtx)
+
+	// While the leader renews, the follower never takes over
+	time.Sleep(2 * ttl)
+	if follower.IsLeader() || !leader.IsLeader() {
+		t.Fatalf("leader = %v, follower = %v while the leader renews", leader.IsLeader(), follower.IsLeader())
+	}
+
+	// The leader stops renewing without resigning, as when its process hangs
+	stoppedAt := time.Now()
+	stopLeader()
+
+	if !waitFor(t, ttl, func() bool { return !leader.IsLeader() }) {
+		t.Fatal("stale leader still considers itself leader after its lease ran out")
+	}
+	if !waitFor(t, 3*ttl, follower.IsLeader) {
+		t.Fatal("follower did not take over")
+	}
+	if since := time.Since(stoppedAt); since < ttl*2/3 {
+		t.Errorf("follower took over %s after the last renewal could have happened, before the lease expired", since)
+	}
+	if leader.IsLeader() {
+		t.Error("both nodes consider themselves leader")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if want := [][]string{{"node-a"}}; !reflect.DeepEqual(elected, want) {
+		t.Errorf("OnElected got departed %v, want %v", elected, want)
+	}
+}
+
+// blockingRepository stalls Acquire until its context ends, as a database that stopped answering
+type blockingRepository struct {
+	Repository
+}
+
+func (r blockingRepository) Acquire(ctx context.Context, nodeID string, ttl time.Duration) (*Leadership, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestElectorRoundTimeout(t *testing.T) {
+	const ttl = 90 * time.Millisecond
+	repo := NewRepository()
+	e := NewElector(repo, zap.NewNop(), "node-a", ttl)
+	e.round(context.Background())
+	if !e.IsLeader() {
+		t.Fatal("node did not take the free lease")
+	}
+
+	e.repo = blockingRepository{Repository: repo}
+	start := time.Now()
+	e.round(context.Background())
+	if elapsed := time.Since(start); elapsed > ttl/2 {
+		t.Errorf("round took %s, want it bounded by a third of the TTL", elapsed)
+	}
+	if e.IsLeader() {
+		t.Error("node still leads after failing to renew")
+	}
+}
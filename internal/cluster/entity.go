@@ -0,0 +1,23 @@
+package cluster
+
+import "time"
+
+// Node is a server instance taking part in the cluster. Nodes report in on every election round;
+// one that stops doing so for longer than the leadership TTL is considered gone.
+type Node struct {
+	ID         string    `json:"id"`
+	Hostname   string    `json:"hostname"`
+	StartedAt  time.Time `json:"started_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// Alive reports whether the node has reported in within ttl of now
+func (n *Node) Alive(now time.Time, ttl time.Duration) bool {
+	return now.Sub(n.LastSeenAt) < ttl
+}
+
+// Leadership is the lease that makes a node the cluster leader until it expires unrenewed
+type Leadership struct {
+	NodeID    string    `json:"node_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,66 @@
+package cluster
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves cluster membership to administrators
+type Handler struct {
+	elector *Elector
+}
+
+// NewHandler generates a dependency-resolved Handler
+func NewHandler(elector *Elector) *Handler {
+	return &Handler{elector: elector}
+}
+
+// NodeResponse is the DTO used to shape a cluster member
+type NodeResponse struct {
+	ID         string    `json:"id"`
+	Hostname   string    `json:"hostname"`
+	StartedAt  time.Time `json:"started_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	Alive      bool      `json:"alive"`
+	IsLeader   bool      `json:"is_leader"`
+	IsSelf     bool      `json:"is_self"`
+}
+
+// MembershipResponse is the DTO used to shape the cluster, with the leader's lease expiry
+type MembershipResponse struct {
+	Leader          string         `json:"leader"`
+	LeaderExpiresAt *time.Time     `json:"leader_expires_at"`
+	Nodes           []NodeResponse `json:"nodes"`
+}
+
+// Nodes lists the nodes of the cluster and which one currently leads
+func (h *Handler) Nodes(c *gin.Context) {
+	nodes, lease, err := h.elector.Members(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "failed to list cluster nodes", "data": nil})
+		return
+	}
+
+	now := time.Now()
+	response := MembershipResponse{Nodes: make([]NodeResponse, 0, len(nodes))}
+	// An expired lease means the leader is gone and no node has taken over yet
+	if lease != nil && now.Before(lease.ExpiresAt) {
+		response.Leader = lease.NodeID
+		response.LeaderExpiresAt = &lease.ExpiresAt
+	}
+	for _, n := range nodes {
+		response.Nodes = append(response.Nodes, NodeResponse{
+			ID:         n.ID,
+			Hostname:   n.Hostname,
+			StartedAt:  n.StartedAt,
+			LastSeenAt: n.LastSeenAt,
+			Alive:      n.Alive(now, h.elector.TTL()),
+			IsLeader:   n.ID == response.Leader,
+			IsSelf:     n.ID == h.elector.NodeID(),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "cluster nodes retrieved", "data": response})
+}
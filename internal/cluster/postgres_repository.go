@@ -0,0 +1,117 @@
+package cluster
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a postgres cluster repository on an established connection pool
+func NewPostgresRepository(db *sql.DB) (Repository, error) {
+	if err := initSchema(db); err != nil {
+		return nil, err
+	}
+
+	return &postgresRepository{db: db}, nil
+}
+
+func initSchema(db *sql.DB) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS cluster_nodes (
+			id TEXT PRIMARY KEY,
+			hostname TEXT NOT NULL DEFAULT '',
+			started_at TIMESTAMP NOT NULL,
+			last_seen_at TIMESTAMP NOT NULL
+		)`,
+		// A single row keyed by name holds the leadership lease; a lease row rather than an
+		// advisory lock survives connection pool churn and lets other nodes see who leads
+		`CREATE TABLE IF NOT EXISTS cluster_leader (
+			name TEXT PRIMARY KEY,
+			node_id TEXT NOT NULL,
+			expires_at TIMESTAMP NOT NULL
+		)`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// leaderName keys the scheduler's leadership lease row
+const leaderName = "scheduler"
+
+func (r *postgresRepository) Heartbeat(ctx context.Context, n *Node) error {
+	query := `
+	INSERT INTO cluster_nodes (id, hostname, started_at, last_seen_at) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (id) DO UPDATE SET hostname = EXCLUDED.hostname, started_at = EXCLUDED.started_at, last_seen_at = EXCLUDED.last_seen_at
+	`
+	_, err := r.db.ExecContext(ctx, query, n.ID, n.Hostname, n.StartedAt, n.LastSeenAt)
+	return err
+}
+
+// Acquire relies on the conditional upsert being atomic, so racing nodes cannot both win, and
+// on the database clock, so that a node whose clock runs late cannot take over a live lease
+func (r *postgresRepository) Acquire(ctx context.Context, nodeID string, ttl time.Duration) (*Leadership, error) {
+	query := `
+	INSERT INTO cluster_leader (name, node_id, expires_at) VALUES ($1, $2, LOCALTIMESTAMP + $3 * INTERVAL '1 microsecond')
+	ON CONFLICT (name) DO UPDATE SET node_id = EXCLUDED.node_id, expires_at = EXCLUDED.expires_at
+	WHERE cluster_leader.node_id = EXCLUDED.node_id OR cluster_leader.expires_at <= LOCALTIMESTAMP
+	`
+	if _, err := r.db.ExecContext(ctx, query, leaderName, nodeID, ttl.Microseconds()); err != nil {
+		return nil, err
+	}
+	return r.Leader(ctx)
+}
+
+func (r *postgresRepository) Resign(ctx context.Context, nodeID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM cluster_leader WHERE name = $1 AND node_id = $2`, leaderName, nodeID)
+	return err
+}
+
+func (r *postgresRepository) Leader(ctx context.Context) (*Leadership, error) {
+	var l Leadership
+	err := r.db.QueryRowContext(ctx, `SELECT node_id, expires_at FROM cluster_leader WHERE name = $1`, leaderName).Scan(&l.NodeID, &l.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *postgresRepository) Nodes(ctx context.Context) ([]*Node, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, hostname, started_at, last_seen_at FROM cluster_nodes ORDER BY started_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*Node
+	for rows.Next() {
+		var n Node
+		if err := rows.Scan(&n.ID, &n.Hostname, &n.StartedAt, &n.LastSeenAt); err != nil {
+			return nil, err
+		}
+		result = append(result, &n)
+	}
+	return result, rows.Err()
+}
+
+func (r *postgresRepository) Leave(ctx context.Context, nodeID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM cluster_nodes WHERE id = $1`, nodeID)
+	return err
+}
+
+func (r *postgresRepository) Prune(ctx context.Context, before time.Time) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM cluster_nodes WHERE last_seen_at < $1`, before)
+	return err
+}
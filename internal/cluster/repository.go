@@ -0,0 +1,117 @@
+package cluster
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Repository defines data access for cluster membership and the leadership lease
+type Repository interface {
+	// Heartbeat records that a node is alive, registering it on first use
+	Heartbeat(ctx context.Context, n *Node) error
+	// Acquire takes or renews the leadership lease for nodeID for ttl, succeeding only when the
+	// lease is free, expired or already held by nodeID. Expiry is judged by the repository's
+	// clock, so that nodes with skewed clocks agree. It returns the lease as it stands
+	// afterwards, whoever holds it.
+	Acquire(ctx context.Context, nodeID string, ttl time.Duration) (*Leadership, error)
+	// Resign gives up the leadership lease if nodeID holds it
+	Resign(ctx context.Context, nodeID string) error
+	// Leader returns the current leadership lease, nil when none was ever taken
+	Leader(ctx context.Context) (*Leadership, error)
+	Nodes(ctx context.Context) ([]*Node, error)
+	// Leave removes a node from the membership, e.g. on shutdown
+	Leave(ctx context.Context, nodeID string) error
+	// Prune removes nodes last seen before the given time
+	Prune(ctx context.Context, before time.Time) error
+}
+
+type inMemoryRepository struct {
+	mu     sync.RWMutex
+	nodes  map[string]*Node
+	leader *Leadership
+}
+
+// NewRepository creates a new in-memory cluster repository, which can only coordinate the nodes
+// of a single process
+func NewRepository() Repository {
+	return &inMemoryRepository{
+		nodes: make(map[string]*Node),
+	}
+}
+
+func (r *inMemoryRepository) Heartbeat(ctx context.Context, n *Node) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clone := *n
+	r.nodes[n.ID] = &clone
+	return nil
+}
+
+func (r *inMemoryRepository) Acquire(ctx context.Context, nodeID string, ttl time.Duration) (*Leadership, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if r.leader == nil || r.leader.NodeID == nodeID || !now.Before(r.leader.ExpiresAt) {
+		r.leader = &Leadership{NodeID: nodeID, ExpiresAt: now.Add(ttl)}
+	}
+	clone := *r.leader
+	return &clone, nil
+}
+
+func (r *inMemoryRepository) Resign(ctx context.Context, nodeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.leader != nil && r.leader.NodeID == nodeID {
+		r.leader = nil
+	}
+	return nil
+}
+
+func (r *inMemoryRepository) Leader(ctx context.Context) (*Leadership, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.leader == nil {
+		return nil, nil
+	}
+	clone := *r.leader
+	return &clone, nil
+}
+
+func (r *inMemoryRepository) Nodes(ctx context.Context) ([]*Node, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*Node
+	for _, n := range r.nodes {
+		clone := *n
+		result = append(result, &clone)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].StartedAt.Before(result[j].StartedAt) })
+	return result, nil
+}
+
+func (r *inMemoryRepository) Leave(ctx context.Context, nodeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.nodes, nodeID)
+	return nil
+}
+
+func (r *inMemoryRepository) Prune(ctx context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, n := range r.nodes {
+		if n.LastSeenAt.Before(before) {
+			delete(r.nodes, id)
+		}
+	}
+	return nil
+}
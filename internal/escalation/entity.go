@@ -0,0 +1,49 @@
+package escalation
+
+import "time"
+
+// Policy is an ordered list of notification steps run while an incident stays unacknowledged
+type Policy struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	Steps     []Step    `json:"steps"`
+	Repeat    int       `json:"repeat"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Step notifies a set of channels, then waits before the next step (or the next repetition)
+type Step struct {
+	ChannelIDs []string      `json:"channel_ids"`
+	Wait       time.Duration `json:"wait"`
+}
+
+// Run is the durable timer driving a policy for one incident
+type Run struct {
+	IncidentID string    `json:"incident_id"`
+	PolicyID   string    `json:"policy_id"`
+	Step       int       `json:"step"`
+	Iteration  int       `json:"iteration"`
+	NextAt     time.Time `json:"next_at"`
+	Done       bool      `json:"done"`
+	// Attempts counts the failed deliveries of the current step
+	Attempts int `json:"attempts"`
+}
+
+// advance moves the run past its current step. It returns false once every step has been
+// executed Repeat additional times, after which the run is finished.
+func (r *Run) advance(p *Policy, now time.Time) bool {
+	wait := p.Steps[r.Step].Wait
+	r.Attempts = 0
+	r.Step++
+	if r.Step >= len(p.Steps) {
+		r.Step = 0
+		r.Iteration++
+		if r.Iteration > p.Repeat {
+			r.Done = true
+			return false
+		}
+	}
+	r.NextAt = now.Add(wait)
+	return true
+}
@@ -0,0 +1,181 @@
+package escalation
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+	"github.com/ranjithkumar/sentinelai/internal/notify"
+)
+
+// PolicyResponse is the DTO used to shape the API response, with waits in minutes
+type PolicyResponse struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Steps     []StepResponse `json:"steps"`
+	Repeat    int            `json:"repeat"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// StepResponse is the DTO used to shape a single escalation step
+type StepResponse struct {
+	ChannelIDs []string `json:"channel_ids"`
+	Wait       int64    `json:"wait"`
+}
+
+func mapToResponse(p *Policy) PolicyResponse {
+	res := PolicyResponse{
+		ID:        p.ID,
+		Name:      p.Name,
+		Repeat:    p.Repeat,
+		CreatedAt: p.CreatedAt,
+	}
+	for _, step := range p.Steps {
+		res.Steps = append(res.Steps, StepResponse{ChannelIDs: step.ChannelIDs, Wait: int64(step.Wait / time.Minute)})
+	}
+	return res
+}
+
+// Handler processes HTTP escalation policy actions
+type Handler struct {
+	svc Service
+}
+
+// NewHandler generates a dependency-resolved Handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// Create handles registering a new escalation policy
+func (h *Handler) Create(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	var req CreatePolicyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid request data", "data": nil})
+		return
+	}
+
+	p, err := h.svc.CreatePolicy(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		respondError(c, err, "failed to create escalation policy")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "message": "escalation policy created successfully", "data": mapToResponse(p)})
+}
+
+// List handles returning the caller's escalation policies
+func (h *Handler) List(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	policies, err := h.svc.ListPolicies(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "failed to list escalation policies", "data": nil})
+		return
+	}
+
+	responseData := make([]PolicyResponse, 0, len(policies))
+	for _, p := range policies {
+		responseData = append(responseData, mapToResponse(p))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "escalation policies retrieved", "data": responseData})
+}
+
+// Get handles fetching a single escalation policy
+func (h *Handler) Get(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	p, err := h.svc.GetPolicy(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to get escalation policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "escalation policy retrieved", "data": mapToResponse(p)})
+}
+
+// Delete handles removing an escalation policy and detaching it from monitors
+func (h *Handler) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	if err := h.svc.DeletePolicy(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		respondError(c, err, "failed to delete escalation policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "escalation policy deleted successfully", "data": nil})
+}
+
+// SetForMonitor handles attaching a policy to a monitor, replacing any existing one
+func (h *Handler) SetForMonitor(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	var req SetPolicyReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid request data", "data": nil})
+		return
+	}
+
+	if err := h.svc.SetMonitorPolicy(c.Request.Context(), userID.(string), c.Param("id"), req.PolicyID); err != nil {
+		respondError(c, err, "failed to attach escalation policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "escalation policy attached to monitor", "data": nil})
+}
+
+// ClearForMonitor handles detaching the escalation policy of a monitor
+func (h *Handler) ClearForMonitor(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	if err := h.svc.ClearMonitorPolicy(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		respondError(c, err, "failed to detach escalation policy")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "escalation policy detached from monitor", "data": nil})
+}
+
+// respondError maps service errors onto HTTP status codes, falling back to a 500 with the given message
+func respondError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrPolicyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "escalation policy not found", "data": nil})
+	case errors.Is(err, notify.ErrChannelNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "channel not found", "data": nil})
+	case errors.Is(err, monitor.ErrMonitorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "monitor not found", "data": nil})
+	case errors.Is(err, ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "message": "forbidden", "data": nil})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": fallback, "data": nil})
+	}
+}
@@ -0,0 +1,199 @@
+package escalation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a postgres escalation repository on an established connection pool
+func NewPostgresRepository(db *sql.DB) (Repository, error) {
+	if err := initSchema(db); err != nil {
+		return nil, err
+	}
+
+	return &postgresRepository{db: db}, nil
+}
+
+func initSchema(db *sql.DB) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS escalation_policies (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			steps JSONB NOT NULL,
+			repeat INT NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS monitor_escalation_policies (
+			monitor_id TEXT PRIMARY KEY REFERENCES monitors(id) ON DELETE CASCADE,
+			policy_id TEXT NOT NULL REFERENCES escalation_policies(id) ON DELETE CASCADE
+		)`,
+		`CREATE TABLE IF NOT EXISTS escalation_runs (
+			incident_id TEXT PRIMARY KEY REFERENCES incidents(id) ON DELETE CASCADE,
+			policy_id TEXT NOT NULL REFERENCES escalation_policies(id) ON DELETE CASCADE,
+			step INT NOT NULL,
+			iteration INT NOT NULL,
+			next_at TIMESTAMP NOT NULL,
+			done BOOLEAN NOT NULL DEFAULT FALSE
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_escalation_runs_due ON escalation_runs (next_at) WHERE NOT done`,
+		`ALTER TABLE escalation_runs ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *postgresRepository) CreatePolicy(ctx context.Context, p *Policy) error {
+	steps, err := json.Marshal(p.Steps)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO escalation_policies (id, user_id, name, steps, repeat, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err = r.db.ExecContext(ctx, query, p.ID, p.UserID, p.Name, steps, p.Repeat, p.CreatedAt)
+	return err
+}
+
+func (r *postgresRepository) GetPolicy(ctx context.Context, id string) (*Policy, error) {
+	return r.queryPolicy(ctx, `SELECT id, user_id, name, steps, repeat, created_at FROM escalation_policies WHERE id = $1`, id)
+}
+
+func (r *postgresRepository) PolicyForMonitor(ctx context.Context, monitorID string) (*Policy, error) {
+	query := `
+	SELECT p.id, p.user_id, p.name, p.steps, p.repeat, p.created_at
+	FROM escalation_policies p
+	JOIN monitor_escalation_policies mp ON mp.policy_id = p.id
+	WHERE mp.monitor_id = $1
+	`
+	return r.queryPolicy(ctx, query, monitorID)
+}
+
+func (r *postgresRepository) ListPolicies(ctx context.Context, userID string) ([]*Policy, error) {
+	query := `SELECT id, user_id, name, steps, repeat, created_at FROM escalation_policies WHERE user_id = $1 ORDER BY created_at`
+	return r.queryPolicies(ctx, query, userID)
+}
+
+func (r *postgresRepository) queryPolicy(ctx context.Context, query string, args ...interface{}) (*Policy, error) {
+	policies, err := r.queryPolicies(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, ErrPolicyNotFound
+	}
+	return policies[0], nil
+}
+
+func (r *postgresRepository) queryPolicies(ctx context.Context, query string, args ...interface{}) ([]*Policy, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*Policy
+	for rows.Next() {
+		var p Policy
+		var steps []byte
+		if err := rows.Scan(&p.ID, &p.UserID, &p.Name, &steps, &p.Repeat, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(steps, &p.Steps); err != nil {
+			return nil, err
+		}
+		result = append(result, &p)
+	}
+	return result, rows.Err()
+}
+
+func (r *postgresRepository) DeletePolicy(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM escalation_policies WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+func (r *postgresRepository) SetMonitorPolicy(ctx context.Context, monitorID, policyID string) error {
+	query := `
+	INSERT INTO monitor_escalation_policies (monitor_id, policy_id) VALUES ($1, $2)
+	ON CONFLICT (monitor_id) DO UPDATE SET policy_id = EXCLUDED.policy_id
+	`
+	_, err := r.db.ExecContext(ctx, query, monitorID, policyID)
+	return err
+}
+
+func (r *postgresRepository) ClearMonitorPolicy(ctx context.Context, monitorID string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM monitor_escalation_policies WHERE monitor_id = $1`, monitorID)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+func (r *postgresRepository) CreateRun(ctx context.Context, run *Run) error {
+	query := `
+	INSERT INTO escalation_runs (incident_id, policy_id, step, iteration, next_at, done, attempts)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (incident_id) DO NOTHING
+	`
+	_, err := r.db.ExecContext(ctx, query, run.IncidentID, run.PolicyID, run.Step, run.Iteration, run.NextAt, run.Done, run.Attempts)
+	return err
+}
+
+func (r *postgresRepository) DueRuns(ctx context.Context, now time.Time, limit int) ([]*Run, error) {
+	query := `
+	SELECT incident_id, policy_id, step, iteration, next_at, done, attempts
+	FROM escalation_runs WHERE NOT done AND next_at <= $1
+	ORDER BY next_at LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*Run
+	for rows.Next() {
+		var run Run
+		if err := rows.Scan(&run.IncidentID, &run.PolicyID, &run.Step, &run.Iteration, &run.NextAt, &run.Done, &run.Attempts); err != nil {
+			return nil, err
+		}
+		result = append(result, &run)
+	}
+	return result, rows.Err()
+}
+
+func (r *postgresRepository) UpdateRun(ctx context.Context, run *Run) error {
+	query := `UPDATE escalation_runs SET step = $1, iteration = $2, next_at = $3, done = $4, attempts = $5 WHERE incident_id = $6`
+	_, err := r.db.ExecContext(ctx, query, run.Step, run.Iteration, run.NextAt, run.Done, run.Attempts, run.IncidentID)
+	return err
+}
+
+func (r *postgresRepository) FinishRuns(ctx context.Context, incidentID string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE escalation_runs SET done = TRUE WHERE incident_id = $1`, incidentID)
+	return err
+}
+
+// expectAffected maps a statement that touched no rows onto ErrPolicyNotFound
+func expectAffected(res sql.Result) error {
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrPolicyNotFound
+	}
+	return nil
+}
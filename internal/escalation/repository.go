@@ -0,0 +1,189 @@
+package escalation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Repository defines data access for escalation policies, their monitor attachments and runs
+type Repository interface {
+	CreatePolicy(ctx context.Context, p *Policy) error
+	GetPolicy(ctx context.Context, id string) (*Policy, error)
+	ListPolicies(ctx context.Context, userID string) ([]*Policy, error)
+	DeletePolicy(ctx context.Context, id string) error
+	SetMonitorPolicy(ctx context.Context, monitorID, policyID string) error
+	ClearMonitorPolicy(ctx context.Context, monitorID string) error
+	// PolicyForMonitor returns ErrPolicyNotFound when the monitor has no policy attached
+	PolicyForMonitor(ctx context.Context, monitorID string) (*Policy, error)
+
+	CreateRun(ctx context.Context, run *Run) error
+	// DueRuns returns unfinished runs whose next step is due at or before now
+	DueRuns(ctx context.Context, now time.Time, limit int) ([]*Run, error)
+	UpdateRun(ctx context.Context, run *Run) error
+	// FinishRuns marks every run of an incident as done
+	FinishRuns(ctx context.Context, incidentID string) error
+}
+
+var ErrPolicyNotFound = errors.New("escalation policy not found")
+
+type inMemoryRepository struct {
+	mu       sync.RWMutex
+	policies map[string]*Policy
+	attached map[string]string // monitor ID -> policy ID
+	runs     map[string]*Run   // incident ID -> run
+}
+
+// NewRepository creates a new in-memory escalation repository
+func NewRepository() Repository {
+	return &inMemoryRepository{
+		policies: make(map[string]*Policy),
+		attached: make(map[string]string),
+		runs:     make(map[string]*Run),
+	}
+}
+
+func clonePolicy(p *Policy) *Policy {
+	clone := *p
+	clone.Steps = make([]Step, len(p.Steps))
+	for i, step := range p.Steps {
+		clone.Steps[i] = Step{ChannelIDs: append([]string(nil), step.ChannelIDs...), Wait: step.Wait}
+	}
+	return &clone
+}
+
+func (r *inMemoryRepository) CreatePolicy(ctx context.Context, p *Policy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.policies[p.ID]; exists {
+		return errors.New("escalation policy already exists")
+	}
+	r.policies[p.ID] = clonePolicy(p)
+	return nil
+}
+
+func (r *inMemoryRepository) GetPolicy(ctx context.Context, id string) (*Policy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, exists := r.policies[id]
+	if !exists {
+		return nil, ErrPolicyNotFound
+	}
+	return clonePolicy(p), nil
+}
+
+func (r *inMemoryRepository) ListPolicies(ctx context.Context, userID string) ([]*Policy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*Policy
+	for _, p := range r.policies {
+		if p.UserID == userID {
+			result = append(result, clonePolicy(p))
+		}
+	}
+	return result, nil
+}
+
+func (r *inMemoryRepository) DeletePolicy(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.policies[id]; !exists {
+		return ErrPolicyNotFound
+	}
+	delete(r.policies, id)
+	for monitorID, policyID := range r.attached {
+		if policyID == id {
+			delete(r.attached, monitorID)
+		}
+	}
+	return nil
+}
+
+func (r *inMemoryRepository) SetMonitorPolicy(ctx context.Context, monitorID, policyID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.policies[policyID]; !exists {
+		return ErrPolicyNotFound
+	}
+	r.attached[monitorID] = policyID
+	return nil
+}
+
+func (r *inMemoryRepository) ClearMonitorPolicy(ctx context.Context, monitorID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.attached[monitorID]; !exists {
+		return ErrPolicyNotFound
+	}
+	delete(r.attached, monitorID)
+	return nil
+}
+
+func (r *inMemoryRepository) PolicyForMonitor(ctx context.Context, monitorID string) (*Policy, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, exists := r.policies[r.attached[monitorID]]
+	if !exists {
+		return nil, ErrPolicyNotFound
+	}
+	return clonePolicy(p), nil
+}
+
+func (r *inMemoryRepository) CreateRun(ctx context.Context, run *Run) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.runs[run.IncidentID]; exists {
+		return nil
+	}
+	clone := *run
+	r.runs[run.IncidentID] = &clone
+	return nil
+}
+
+func (r *inMemoryRepository) DueRuns(ctx context.Context, now time.Time, limit int) ([]*Run, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*Run
+	for _, run := range r.runs {
+		if !run.Done && !run.NextAt.After(now) {
+			clone := *run
+			result = append(result, &clone)
+			if len(result) == limit {
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (r *inMemoryRepository) UpdateRun(ctx context.Context, run *Run) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.runs[run.IncidentID]; !exists {
+		return errors.New("escalation run not found")
+	}
+	clone := *run
+	r.runs[run.IncidentID] = &clone
+	return nil
+}
+
+func (r *inMemoryRepository) FinishRuns(ctx context.Context, incidentID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if run, exists := r.runs[incidentID]; exists {
+		run.Done = true
+	}
+	return nil
+}
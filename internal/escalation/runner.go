@@ -0,0 +1,171 @@
+package escalation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ranjithkumar/sentinelai/internal/incident"
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+	"github.com/ranjithkumar/sentinelai/internal/notify"
+	"go.uber.org/zap"
+)
+
+// dueBatchSize caps how many escalation runs are processed per tick
+const dueBatchSize = 100
+
+// A step whose delivery fails is retried after stepRetryDelay, up to maxStepAttempts deliveries,
+// before the run moves on so that later steps are not held up by a broken channel
+const (
+	stepRetryDelay  = time.Minute
+	maxStepAttempts = 3
+)
+
+// Runner periodically fires due escalation steps. Runs are persisted, so escalations in flight
+// resume after a restart.
+type Runner struct {
+	repo      Repository
+	incidents incident.Repository
+	channels  notify.Repository
+	sender    notify.Service
+	logger    *zap.Logger
+	interval  time.Duration
+	leader    monitor.LeaderChecker
+}
+
+// NewRunner creates a new escalation runner
+func NewRunner(repo Repository, incidents incident.Repository, channels notify.Repository, sender notify.Service, logger *zap.Logger, interval time.Duration) *Runner {
+	return &Runner{
+		repo:      repo,
+		incidents: incidents,
+		channels:  channels,
+		sender:    sender,
+		logger:    logger,
+		interval:  interval,
+	}
+}
+
+// SetLeadership makes the runner fire steps only while this node leads the cluster, so that
+// replicas do not notify twice; call before Start
+func (r *Runner) SetLeadership(leader monitor.LeaderChecker) {
+	r.leader = leader
+}
+
+// Start begins ticking processing routines that fire due escalation steps
+func (r *Runner) Start(ctx context.Context) {
+	r.logger.Info("Starting escalation runner", zap.Duration("interval", r.interval))
+	ticker := time.NewTicker(r.interval)
+
+	go func() {
+		defer ticker.Stop()
+		defer func() {
+			if rec := recover(); rec != nil {
+				r.logger.Error("Escalation runner panic recovered", zap.Any("panic", rec))
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				r.logger.Info("Stopping escalation runner")
+				return
+			case <-ticker.C:
+				if r.leader != nil && !r.leader.IsLeader() {
+					continue
+				}
+				r.fireDueRuns(ctx)
+			}
+		}
+	}()
+}
+
+func (r *Runner) fireDueRuns(ctx context.Context) {
+	now := time.Now()
+	runs, err := r.repo.DueRuns(ctx, now, dueBatchSize)
+	if err != nil {
+		r.logger.Error("Failed to load due escalation runs", zap.Error(err))
+		return
+	}
+
+	for _, run := range runs {
+		if err := r.fire(ctx, run, now); err != nil {
+			r.logger.Warn("Escalation step failed", zap.Error(err), zap.String("incident_id", run.IncidentID))
+		}
+	}
+}
+
+// fire executes the current step of a run and schedules the next one. Runs whose incident is no
+// longer open, or whose policy was deleted, are finished without notifying; other lookup errors
+// leave the run due so that the next tick retries it.
+func (r *Runner) fire(ctx context.Context, run *Run, now time.Time) error {
+	inc, err := r.incidents.GetByID(ctx, run.IncidentID)
+	if errors.Is(err, incident.ErrIncidentNotFound) || (err == nil && inc.Status != incident.StatusOpen) {
+		run.Done = true
+		return r.repo.UpdateRun(ctx, run)
+	}
+	if err != nil {
+		return err
+	}
+
+	p, err := r.repo.GetPolicy(ctx, run.PolicyID)
+	if errors.Is(err, ErrPolicyNotFound) || (err == nil && run.Step >= len(p.Steps)) {
+		run.Done = true
+		return r.repo.UpdateRun(ctx, run)
+	}
+	if err != nil {
+		return err
+	}
+
+	var channels []*notify.Channel
+	for _, channelID := range p.Steps[run.Step].ChannelIDs {
+		ch, err := r.channels.GetByID(ctx, channelID)
+		if err != nil {
+			r.logger.Warn("Escalation channel unavailable", zap.Error(err), zap.String("channel_id", channelID))
+			continue
+		}
+		channels = append(channels, ch)
+	}
+
+	sendErr := r.sender.Send(ctx, channels, escalationMessage(inc, run.Step+1, now))
+	if sendErr != nil {
+		run.Attempts++
+		if run.Attempts < maxStepAttempts {
+			r.logger.Warn("Escalation step delivery failed, retrying",
+				zap.String("incident_id", inc.ID),
+				zap.Int("step", run.Step+1),
+				zap.Int("attempts", run.Attempts),
+			)
+			run.NextAt = now.Add(stepRetryDelay)
+			return errors.Join(sendErr, r.repo.UpdateRun(ctx, run))
+		}
+	}
+	r.logger.Info("Escalation step fired",
+		zap.String("incident_id", inc.ID),
+		zap.Int("step", run.Step+1),
+		zap.Int("iteration", run.Iteration),
+		zap.Int("channels", len(channels)),
+		zap.Bool("delivered", sendErr == nil),
+	)
+
+	run.advance(p, now)
+	return errors.Join(sendErr, r.repo.UpdateRun(ctx, run))
+}
+
+func escalationMessage(inc *incident.Incident, step int, now time.Time) notify.Message {
+	msg := notify.Message{
+		Event:          notify.EventEscalated,
+		MonitorID:      inc.MonitorID,
+		URL:            inc.URL,
+		Health:         "down",
+		ErrorClass:     inc.Cause,
+		Timestamp:      now,
+		IncidentID:     inc.ID,
+		EscalationStep: step,
+	}
+	for i := len(inc.Timeline) - 1; i >= 0; i-- {
+		if inc.Timeline[i].Type == incident.EntryExplanation {
+			msg.Explanation = inc.Timeline[i].Message
+			break
+		}
+	}
+	return msg
+}
@@ -0,0 +1,194 @@
+package escalation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ranjithkumar/sentinelai/internal/incident"
+	"github.com/ranjithkumar/sentinelai/internal/notify"
+	"go.uber.org/zap"
+)
+
+// fakeSender records the escalation messages sent and fails while err is set
+type fakeSender struct {
+	notify.Service
+	err  error
+	sent []int
+}
+
+func (s *fakeSender) Send(ctx context.Context, channels []*notify.Channel, msg notify.Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, msg.EscalationStep)
+	return nil
+}
+
+// flakyIncidents fails incident lookups while err is set
+type flakyIncidents struct {
+	incident.Repository
+	err error
+}
+
+func (r *flakyIncidents) GetByID(ctx context.Context, id string) (*incident.Incident, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.Repository.GetByID(ctx, id)
+}
+
+type runnerFixture struct {
+	runner    *Runner
+	repo      *inMemoryRepository
+	incidents *flakyIncidents
+	sender    *fakeSender
+	start     time.Time
+}
+
+// newRunnerFixture sets up an open incident escalated through a two-step policy that runs once
+func newRunnerFixture(t *testing.T) *runnerFixture {
+	t.Helper()
+	ctx := context.Background()
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	incidents := &flakyIncidents{Repository: incident.NewRepository()}
+	if err := incidents.Create(ctx, &incident.Incident{ID: "i", MonitorID: "m", Status: incident.StatusOpen, StartedAt: start}); err != nil {
+		t.Fatal(err)
+	}
+	channels := notify.NewRepository()
+	if err := channels.Create(ctx, &notify.Channel{ID: "c", Type: notify.ChannelWebhook}); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewRepository().(*inMemoryRepository)
+	policy := &Policy{ID: "p", Steps: []Step{
+		{ChannelIDs: []string{"c"}, Wait: 5 * time.Minute},
+		{ChannelIDs: []string{"c"}, Wait: 10 * time.Minute},
+	}}
+	if err := repo.CreatePolicy(ctx, policy); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateRun(ctx, &Run{IncidentID: "i", PolicyID: "p", NextAt: start}); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := &fakeSender{}
+	return &runnerFixture{
+		runner:    NewRunner(repo, incidents, channels, sender, zap.NewNop(), time.Minute),
+		repo:      repo,
+		incidents: incidents,
+		sender:    sender,
+		start:     start,
+	}
+}
+
+// tick fires the runs due at the given offset from the start
+func (f *runnerFixture) tick(t *testing.T, offset time.Duration) {
+	t.Helper()
+	ctx := context.Background()
+	now := f.start.Add(offset)
+	runs, err := f.repo.DueRuns(ctx, now, dueBatchSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, run := range runs {
+		_ = f.runner.fire(ctx, run, now)
+	}
+}
+
+func (f *runnerFixture) run() Run {
+	return *f.repo.runs["i"]
+}
+
+func TestRunnerStepTiming(t *testing.T) {
+	f := newRunnerFixture(t)
+
+	steps := []struct {
+		offset time.Duration
+		sent   int
+		done   bool
+	}{
+		{offset: 0, sent: 1},
+		{offset: 4 * time.Minute, sent: 1},
+		// The last step of the last repetition finishes the run
+		{offset: 5 * time.Minute, sent: 2, done: true},
+		{offset: 15 * time.Minute, sent: 2, done: true},
+	}
+	for _, step := range steps {
+		f.tick(t, step.offset)
+		if len(f.sender.sent) != step.sent || f.run().Done != step.done {
+			t.Fatalf("at %s: sent %v, done = %v; want %d sent, done = %v", step.offset, f.sender.sent, f.run().Done, step.sent, step.done)
+		}
+	}
+	if f.sender.sent[0] != 1 || f.sender.sent[1] != 2 {
+		t.Errorf("sent steps %v, want [1 2]", f.sender.sent)
+	}
+}
+
+func TestRunnerRetriesFailedStep(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		step     int
+		nextAt   time.Duration
+	}{
+		{name: "delivered on retry", failures: 1, step: 1, nextAt: stepRetryDelay + 5*time.Minute},
+		{name: "moves on after the last attempt", failures: maxStepAttempts, step: 1, nextAt: (maxStepAttempts-1)*stepRetryDelay + 5*time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRunnerFixture(t)
+			f.sender.err = errors.New("channel down")
+
+			var offset time.Duration
+			for i := 0; i < tt.failures; i++ {
+				f.tick(t, offset)
+				if run := f.run(); i < maxStepAttempts-1 && (run.Step != 0 || !run.NextAt.Equal(f.start.Add(offset+stepRetryDelay))) {
+					t.Fatalf("after failure %d: step %d next at %s, want the step retried after %s", i+1, run.Step, run.NextAt, stepRetryDelay)
+				}
+				offset += stepRetryDelay
+			}
+			if tt.failures < maxStepAttempts {
+				f.sender.err = nil
+				f.tick(t, offset)
+			}
+
+			run := f.run()
+			if run.Step != tt.step || run.Attempts != 0 || !run.NextAt.Equal(f.start.Add(tt.nextAt)) {
+				t.Errorf("step %d, attempts %d, next at %s; want step %d, no attempts, next at %s",
+					run.Step, run.Attempts, run.NextAt, tt.step, f.start.Add(tt.nextAt))
+			}
+		})
+	}
+}
+
+func TestRunnerStopsOnResolution(t *testing.T) {
+	ctx := context.Background()
+	f := newRunnerFixture(t)
+	f.tick(t, 0)
+
+	if err := f.incidents.UpdateStatus(ctx, &incident.Incident{ID: "i", Status: incident.StatusResolved}); err != nil {
+		t.Fatal(err)
+	}
+	f.tick(t, 5*time.Minute)
+	if len(f.sender.sent) != 1 || !f.run().Done {
+		t.Errorf("sent %v, done = %v after resolution; want no further step and the run done", f.sender.sent, f.run().Done)
+	}
+}
+
+func TestRunnerKeepsRunOnLookupError(t *testing.T) {
+	f := newRunnerFixture(t)
+	f.incidents.err = errors.New("connection refused")
+	f.tick(t, 0)
+	if run := f.run(); run.Done || run.Step != 0 || len(f.sender.sent) != 0 {
+		t.Fatalf("run = %+v, sent %v after a lookup error; want it left due", run, f.sender.sent)
+	}
+
+	f.incidents.err = nil
+	f.tick(t, time.Minute)
+	if len(f.sender.sent) != 1 {
+		t.Errorf("sent %v once the lookup recovered, want the first step", f.sender.sent)
+	}
+}
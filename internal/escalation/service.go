@@ -0,0 +1,165 @@
+package escalation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/ranjithkumar/sentinelai/internal/incident"
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+	"github.com/ranjithkumar/sentinelai/internal/notify"
+)
+
+// ErrForbidden is returned when a user references a policy, channel or monitor they do not own
+var ErrForbidden = errors.New("policy, channel or monitor belongs to another user")
+
+// CreatePolicyReq defines the payload for creating an escalation policy
+type CreatePolicyReq struct {
+	Name   string    `json:"name" binding:"required,max=100"`
+	Steps  []StepReq `json:"steps" binding:"required,min=1,max=10,dive"`
+	Repeat int       `json:"repeat" binding:"omitempty,min=0,max=10"`
+}
+
+// StepReq defines a single escalation step
+type StepReq struct {
+	ChannelIDs []string `json:"channel_ids" binding:"required,min=1"`
+	Wait       int      `json:"wait" binding:"omitempty,min=0,max=1440"` // in minutes
+}
+
+// SetPolicyReq defines the payload for attaching a policy to a monitor
+type SetPolicyReq struct {
+	PolicyID string `json:"policy_id" binding:"required"`
+}
+
+// Service manages escalation policies and starts or stops escalation runs as incidents change
+type Service interface {
+	incident.Listener
+	CreatePolicy(ctx context.Context, userID string, req CreatePolicyReq) (*Policy, error)
+	ListPolicies(ctx context.Context, userID string) ([]*Policy, error)
+	GetPolicy(ctx context.Context, userID, id string) (*Policy, error)
+	DeletePolicy(ctx context.Context, userID, id string) error
+	SetMonitorPolicy(ctx context.Context, userID, monitorID, policyID string) error
+	ClearMonitorPolicy(ctx context.Context, userID, monitorID string) error
+}
+
+type serviceImpl struct {
+	repo     Repository
+	monitors monitor.Repository
+	channels notify.Repository
+}
+
+// NewService creates a new escalation service
+func NewService(repo Repository, monitors monitor.Repository, channels notify.Repository) Service {
+	return &serviceImpl{repo: repo, monitors: monitors, channels: channels}
+}
+
+// IncidentChanged starts the monitor's policy when an incident opens and stops every run of the
+// incident once it is acknowledged or resolved
+func (s *serviceImpl) IncidentChanged(ctx context.Context, inc *incident.Incident) error {
+	if inc.Status != incident.StatusOpen {
+		return s.repo.FinishRuns(ctx, inc.ID)
+	}
+
+	p, err := s.repo.PolicyForMonitor(ctx, inc.MonitorID)
+	if errors.Is(err, ErrPolicyNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.repo.CreateRun(ctx, &Run{
+		IncidentID: inc.ID,
+		PolicyID:   p.ID,
+		NextAt:     time.Now(),
+	})
+}
+
+func (s *serviceImpl) CreatePolicy(ctx context.Context, userID string, req CreatePolicyReq) (*Policy, error) {
+	p := &Policy{
+		ID:        generateID(),
+		UserID:    userID,
+		Name:      req.Name,
+		Repeat:    req.Repeat,
+		CreatedAt: time.Now(),
+	}
+
+	for _, step := range req.Steps {
+		for _, channelID := range step.ChannelIDs {
+			ch, err := s.channels.GetByID(ctx, channelID)
+			if err != nil {
+				return nil, err
+			}
+			if ch.UserID != userID {
+				return nil, ErrForbidden
+			}
+		}
+		p.Steps = append(p.Steps, Step{
+			ChannelIDs: step.ChannelIDs,
+			Wait:       time.Duration(step.Wait) * time.Minute,
+		})
+	}
+
+	if err := s.repo.CreatePolicy(ctx, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (s *serviceImpl) ListPolicies(ctx context.Context, userID string) ([]*Policy, error) {
+	return s.repo.ListPolicies(ctx, userID)
+}
+
+func (s *serviceImpl) GetPolicy(ctx context.Context, userID, id string) (*Policy, error) {
+	p, err := s.repo.GetPolicy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.UserID != userID {
+		return nil, ErrForbidden
+	}
+	return p, nil
+}
+
+func (s *serviceImpl) DeletePolicy(ctx context.Context, userID, id string) error {
+	if _, err := s.GetPolicy(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.DeletePolicy(ctx, id)
+}
+
+func (s *serviceImpl) SetMonitorPolicy(ctx context.Context, userID, monitorID, policyID string) error {
+	if err := s.ownedMonitor(ctx, userID, monitorID); err != nil {
+		return err
+	}
+	if _, err := s.GetPolicy(ctx, userID, policyID); err != nil {
+		return err
+	}
+	return s.repo.SetMonitorPolicy(ctx, monitorID, policyID)
+}
+
+func (s *serviceImpl) ClearMonitorPolicy(ctx context.Context, userID, monitorID string) error {
+	if err := s.ownedMonitor(ctx, userID, monitorID); err != nil {
+		return err
+	}
+	return s.repo.ClearMonitorPolicy(ctx, monitorID)
+}
+
+func (s *serviceImpl) ownedMonitor(ctx context.Context, userID, monitorID string) error {
+	m, err := s.monitors.GetByID(ctx, monitorID)
+	if err != nil {
+		return err
+	}
+	if m.UserID != userID {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func generateID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return time.Now().Format("20060102") + "-" + hex.EncodeToString(b)
+}
@@ -0,0 +1,52 @@
+package incident
+
+import "time"
+
+// Status is the lifecycle state of an incident
+type Status string
+
+const (
+	StatusOpen         Status = "open"
+	StatusAcknowledged Status = "acknowledged"
+	StatusResolved     Status = "resolved"
+)
+
+// EntryType classifies an event on an incident timeline
+type EntryType string
+
+const (
+	EntryFailure      EntryType = "failure"
+	EntryExplanation  EntryType = "explanation"
+	EntryAcknowledged EntryType = "acknowledged"
+	EntryResolved     EntryType = "resolved"
+	EntryRecovered    EntryType = "recovered"
+	EntryComment      EntryType = "comment"
+	EntryFlapping     EntryType = "flapping"
+)
+
+// Incident represents a single outage of a monitor, from first failure to resolution
+type Incident struct {
+	ID             string          `json:"id"`
+	MonitorID      string          `json:"monitor_id"`
+	UserID         string          `json:"user_id"`
+	URL            string          `json:"url"`
+	Status         Status          `json:"status"`
+	Cause          string          `json:"cause"`
+	StartedAt      time.Time       `json:"started_at"`
+	AcknowledgedAt time.Time       `json:"acknowledged_at"`
+	AcknowledgedBy string          `json:"acknowledged_by"`
+	ResolvedAt     time.Time       `json:"resolved_at"`
+	ResolvedBy     string          `json:"resolved_by"`
+	Timeline       []TimelineEntry `json:"timeline"`
+}
+
+// TimelineEntry is a single event recorded against an incident
+type TimelineEntry struct {
+	ID         int64     `json:"id"`
+	IncidentID string    `json:"incident_id"`
+	Type       EntryType `json:"type"`
+	Message    string    `json:"message"`
+	StatusCode int       `json:"status_code"`
+	Author     string    `json:"author"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,206 @@
+package incident
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IncidentResponse is the DTO used to shape the API response
+type IncidentResponse struct {
+	ID             string          `json:"id"`
+	MonitorID      string          `json:"monitor_id"`
+	URL            string          `json:"url"`
+	Status         Status          `json:"status"`
+	Cause          string          `json:"cause,omitempty"`
+	StartedAt      time.Time       `json:"started_at"`
+	Duration       int64           `json:"duration"`
+	AcknowledgedAt *time.Time      `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy string          `json:"acknowledged_by,omitempty"`
+	ResolvedAt     *time.Time      `json:"resolved_at,omitempty"`
+	ResolvedBy     string          `json:"resolved_by,omitempty"`
+	Timeline       []EntryResponse `json:"timeline,omitempty"`
+}
+
+// EntryResponse is the DTO used to shape a single timeline event
+type EntryResponse struct {
+	ID         int64     `json:"id"`
+	Type       EntryType `json:"type"`
+	Message    string    `json:"message"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Author     string    `json:"author,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func mapToResponse(inc *Incident) IncidentResponse {
+	end := time.Now()
+	if !inc.ResolvedAt.IsZero() {
+		end = inc.ResolvedAt
+	}
+
+	res := IncidentResponse{
+		ID:             inc.ID,
+		MonitorID:      inc.MonitorID,
+		URL:            inc.URL,
+		Status:         inc.Status,
+		Cause:          inc.Cause,
+		StartedAt:      inc.StartedAt,
+		Duration:       end.Sub(inc.StartedAt).Milliseconds(),
+		AcknowledgedAt: optionalTime(inc.AcknowledgedAt),
+		AcknowledgedBy: inc.AcknowledgedBy,
+		ResolvedAt:     optionalTime(inc.ResolvedAt),
+		ResolvedBy:     inc.ResolvedBy,
+	}
+	for _, e := range inc.Timeline {
+		res.Timeline = append(res.Timeline, EntryResponse{
+			ID:         e.ID,
+			Type:       e.Type,
+			Message:    e.Message,
+			StatusCode: e.StatusCode,
+			Author:     e.Author,
+			CreatedAt:  e.CreatedAt,
+		})
+	}
+	return res
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// Handler processes HTTP incident actions
+type Handler struct {
+	svc Service
+}
+
+// NewHandler generates a dependency-resolved Handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// List handles returning the caller's incidents, optionally filtered by ?status=
+func (h *Handler) List(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	status := Status(c.Query("status"))
+	switch status {
+	case "", StatusOpen, StatusAcknowledged, StatusResolved:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid status filter", "data": nil})
+		return
+	}
+
+	incidents, err := h.svc.List(c.Request.Context(), userID.(string), status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "failed to list incidents", "data": nil})
+		return
+	}
+
+	responseData := make([]IncidentResponse, 0, len(incidents))
+	for _, inc := range incidents {
+		responseData = append(responseData, mapToResponse(inc))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "incidents retrieved",
+		"data":    responseData,
+	})
+}
+
+// Get handles fetching a single incident together with its timeline
+func (h *Handler) Get(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	inc, err := h.svc.Get(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to get incident")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "incident retrieved", "data": mapToResponse(inc)})
+}
+
+// Acknowledge handles marking an open incident as being looked at by the caller
+func (h *Handler) Acknowledge(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	inc, err := h.svc.Acknowledge(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to acknowledge incident")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "incident acknowledged", "data": mapToResponse(inc)})
+}
+
+// Resolve handles manually closing an incident
+func (h *Handler) Resolve(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	inc, err := h.svc.Resolve(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to resolve incident")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "incident resolved", "data": mapToResponse(inc)})
+}
+
+// Comment handles appending a user comment to an incident timeline
+func (h *Handler) Comment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	var req CommentReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid request data", "data": nil})
+		return
+	}
+
+	inc, err := h.svc.Comment(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		respondError(c, err, "failed to add comment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "message": "comment added", "data": mapToResponse(inc)})
+}
+
+// respondError maps service errors onto HTTP status codes, falling back to a 500 with the given message
+func respondError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrIncidentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "incident not found", "data": nil})
+	case errors.Is(err, ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "message": "forbidden", "data": nil})
+	case errors.Is(err, ErrInvalidTransition):
+		c.JSON(http.StatusConflict, gin.H{"success": false, "message": err.Error(), "data": nil})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": fallback, "data": nil})
+	}
+}
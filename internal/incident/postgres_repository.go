@@ -0,0 +1,171 @@
+package incident
+
+import (
+	"context"
+	"database/sql"
+)
+
+const incidentColumns = `id, monitor_id, user_id, url, status, cause, started_at, acknowledged_at, acknowledged_by, resolved_at, resolved_by`
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a postgres incident repository on an established connection pool
+func NewPostgresRepository(db *sql.DB) (Repository, error) {
+	if err := initSchema(db); err != nil {
+		return nil, err
+	}
+
+	return &postgresRepository{db: db}, nil
+}
+
+func initSchema(db *sql.DB) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS incidents (
+			id TEXT PRIMARY KEY,
+			monitor_id TEXT NOT NULL,
+			user_id TEXT NOT NULL,
+			url TEXT,
+			status TEXT NOT NULL,
+			cause TEXT,
+			started_at TIMESTAMP NOT NULL,
+			acknowledged_at TIMESTAMP,
+			acknowledged_by TEXT,
+			resolved_at TIMESTAMP,
+			resolved_by TEXT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_incidents_monitor_status ON incidents (monitor_id, status)`,
+		`CREATE INDEX IF NOT EXISTS idx_incidents_user_started ON incidents (user_id, started_at DESC)`,
+		`CREATE TABLE IF NOT EXISTS incident_events (
+			id BIGSERIAL PRIMARY KEY,
+			incident_id TEXT NOT NULL REFERENCES incidents(id) ON DELETE CASCADE,
+			type TEXT NOT NULL,
+			message TEXT,
+			status_code INT,
+			author TEXT,
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_incident_events_incident ON incident_events (incident_id, id)`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *postgresRepository) Create(ctx context.Context, inc *Incident) error {
+	query := `
+	INSERT INTO incidents (` + incidentColumns + `)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		inc.ID, inc.MonitorID, inc.UserID, inc.URL, inc.Status, inc.Cause, inc.StartedAt,
+		inc.AcknowledgedAt, inc.AcknowledgedBy, inc.ResolvedAt, inc.ResolvedBy,
+	)
+	return err
+}
+
+func (r *postgresRepository) GetByID(ctx context.Context, id string) (*Incident, error) {
+	incidents, err := r.queryIncidents(ctx, `SELECT `+incidentColumns+` FROM incidents WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(incidents) == 0 {
+		return nil, ErrIncidentNotFound
+	}
+	inc := incidents[0]
+
+	rows, err := r.db.QueryContext(ctx, `
+	SELECT id, incident_id, type, message, status_code, author, created_at
+	FROM incident_events WHERE incident_id = $1 ORDER BY id`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e TimelineEntry
+		if err := rows.Scan(&e.ID, &e.IncidentID, &e.Type, &e.Message, &e.StatusCode, &e.Author, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		inc.Timeline = append(inc.Timeline, e)
+	}
+	return inc, rows.Err()
+}
+
+func (r *postgresRepository) GetActiveByMonitor(ctx context.Context, monitorID string) (*Incident, error) {
+	query := `SELECT ` + incidentColumns + ` FROM incidents WHERE monitor_id = $1 AND status <> $2 ORDER BY started_at DESC LIMIT 1`
+	incidents, err := r.queryIncidents(ctx, query, monitorID, StatusResolved)
+	if err != nil {
+		return nil, err
+	}
+	if len(incidents) == 0 {
+		return nil, ErrIncidentNotFound
+	}
+	return incidents[0], nil
+}
+
+func (r *postgresRepository) List(ctx context.Context, userID string, status Status) ([]*Incident, error) {
+	if status == "" {
+		return r.queryIncidents(ctx, `SELECT `+incidentColumns+` FROM incidents WHERE user_id = $1 ORDER BY started_at DESC`, userID)
+	}
+	query := `SELECT ` + incidentColumns + ` FROM incidents WHERE user_id = $1 AND status = $2 ORDER BY started_at DESC`
+	return r.queryIncidents(ctx, query, userID, status)
+}
+
+func (r *postgresRepository) queryIncidents(ctx context.Context, query string, args ...interface{}) ([]*Incident, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*Incident
+	for rows.Next() {
+		var inc Incident
+		if err := rows.Scan(
+			&inc.ID, &inc.MonitorID, &inc.UserID, &inc.URL, &inc.Status, &inc.Cause, &inc.StartedAt,
+			&inc.AcknowledgedAt, &inc.AcknowledgedBy, &inc.ResolvedAt, &inc.ResolvedBy,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, &inc)
+	}
+	return result, rows.Err()
+}
+
+func (r *postgresRepository) UpdateStatus(ctx context.Context, inc *Incident) error {
+	query := `
+	UPDATE incidents
+	SET status = $1, acknowledged_at = $2, acknowledged_by = $3, resolved_at = $4, resolved_by = $5
+	WHERE id = $6
+	`
+	res, err := r.db.ExecContext(ctx, query, inc.Status, inc.AcknowledgedAt, inc.AcknowledgedBy, inc.ResolvedAt, inc.ResolvedBy, inc.ID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrIncidentNotFound
+	}
+	return nil
+}
+
+func (r *postgresRepository) AddEntry(ctx context.Context, entry *TimelineEntry) error {
+	query := `
+	INSERT INTO incident_events (incident_id, type, message, status_code, author, created_at)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id
+	`
+	return r.db.QueryRowContext(ctx, query,
+		entry.IncidentID, entry.Type, entry.Message, entry.StatusCode, entry.Author, entry.CreatedAt,
+	).Scan(&entry.ID)
+}
@@ -0,0 +1,128 @@
+package incident
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Repository defines data access for incidents and their timelines
+type Repository interface {
+	Create(ctx context.Context, inc *Incident) error
+	// GetByID returns the incident including its full timeline
+	GetByID(ctx context.Context, id string) (*Incident, error)
+	// GetActiveByMonitor returns the unresolved incident of a monitor, if any
+	GetActiveByMonitor(ctx context.Context, monitorID string) (*Incident, error)
+	// List returns a user's incidents newest first, without timelines; an empty status matches all
+	List(ctx context.Context, userID string, status Status) ([]*Incident, error)
+	UpdateStatus(ctx context.Context, inc *Incident) error
+	AddEntry(ctx context.Context, entry *TimelineEntry) error
+}
+
+var ErrIncidentNotFound = errors.New("incident not found")
+
+type inMemoryRepository struct {
+	mu        sync.RWMutex
+	incidents map[string]*Incident
+	order     []string
+	entryID   int64
+}
+
+// NewRepository creates a new in-memory incident repository
+func NewRepository() Repository {
+	return &inMemoryRepository{
+		incidents: make(map[string]*Incident),
+	}
+}
+
+func cloneIncident(inc *Incident, withTimeline bool) *Incident {
+	clone := *inc
+	clone.Timeline = nil
+	if withTimeline {
+		clone.Timeline = append([]TimelineEntry(nil), inc.Timeline...)
+	}
+	return &clone
+}
+
+func (r *inMemoryRepository) Create(ctx context.Context, inc *Incident) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.incidents[inc.ID]; exists {
+		return errors.New("incident already exists")
+	}
+
+	r.incidents[inc.ID] = cloneIncident(inc, false)
+	r.order = append(r.order, inc.ID)
+	return nil
+}
+
+func (r *inMemoryRepository) GetByID(ctx context.Context, id string) (*Incident, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	inc, exists := r.incidents[id]
+	if !exists {
+		return nil, ErrIncidentNotFound
+	}
+	return cloneIncident(inc, true), nil
+}
+
+func (r *inMemoryRepository) GetActiveByMonitor(ctx context.Context, monitorID string) (*Incident, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.order) - 1; i >= 0; i-- {
+		inc := r.incidents[r.order[i]]
+		if inc.MonitorID == monitorID && inc.Status != StatusResolved {
+			return cloneIncident(inc, false), nil
+		}
+	}
+	return nil, ErrIncidentNotFound
+}
+
+func (r *inMemoryRepository) List(ctx context.Context, userID string, status Status) ([]*Incident, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*Incident
+	for i := len(r.order) - 1; i >= 0; i-- {
+		inc := r.incidents[r.order[i]]
+		if inc.UserID == userID && (status == "" || inc.Status == status) {
+			result = append(result, cloneIncident(inc, false))
+		}
+	}
+	return result, nil
+}
+
+func (r *inMemoryRepository) UpdateStatus(ctx context.Context, inc *Incident) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.incidents[inc.ID]
+	if !exists {
+		return ErrIncidentNotFound
+	}
+
+	existing.Status = inc.Status
+	existing.AcknowledgedAt = inc.AcknowledgedAt
+	existing.AcknowledgedBy = inc.AcknowledgedBy
+	existing.ResolvedAt = inc.ResolvedAt
+	existing.ResolvedBy = inc.ResolvedBy
+	return nil
+}
+
+func (r *inMemoryRepository) AddEntry(ctx context.Context, entry *TimelineEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	inc, exists := r.incidents[entry.IncidentID]
+	if !exists {
+		return ErrIncidentNotFound
+	}
+
+	r.entryID++
+	entry.ID = r.entryID
+	inc.Timeline = append(inc.Timeline, *entry)
+	return nil
+}
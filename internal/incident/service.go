@@ -0,0 +1,239 @@
+package incident
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+)
+
+var (
+	// ErrForbidden is returned when a user acts on an incident of a monitor they do not own
+	ErrForbidden = errors.New("incident belongs to another user")
+	// ErrInvalidTransition is returned when an incident cannot move into the requested state
+	ErrInvalidTransition = errors.New("incident cannot transition to the requested state")
+)
+
+// CommentReq defines the payload for adding a comment to an incident timeline
+type CommentReq struct {
+	Message string `json:"message" binding:"required,max=2000"`
+}
+
+// Listener is told whenever an incident is opened, acknowledged or resolved
+type Listener interface {
+	IncidentChanged(ctx context.Context, inc *Incident) error
+}
+
+// Service defines incident lifecycle logic. It observes monitor checks to open,
+// extend and auto-resolve incidents, and exposes manual actions for monitor owners.
+type Service interface {
+	monitor.Observer
+	List(ctx context.Context, userID string, status Status) ([]*Incident, error)
+	Get(ctx context.Context, userID, id string) (*Incident, error)
+	Acknowledge(ctx context.Context, userID, id string) (*Incident, error)
+	Resolve(ctx context.Context, userID, id string) (*Incident, error)
+	Comment(ctx context.Context, userID, id string, req CommentReq) (*Incident, error)
+	// AddListener registers a listener for incident state changes; call before checks start
+	AddListener(l Listener)
+}
+
+type serviceImpl struct {
+	repo      Repository
+	listeners []Listener
+}
+
+// NewService creates a new incident service
+func NewService(repo Repository) Service {
+	return &serviceImpl{repo: repo}
+}
+
+// OnCheck opens an incident on the confirmed down transition of a monitor, appends later
+// failures and their AI explanations to its timeline, and resolves it once recovery is confirmed
+func (s *serviceImpl) OnCheck(ctx context.Context, event monitor.CheckEvent) error {
+	result := event.Result
+
+	active, err := s.repo.GetActiveByMonitor(ctx, event.Monitor.ID)
+	if err != nil && !errors.Is(err, ErrIncidentNotFound) {
+		return err
+	}
+
+	if active != nil && event.Flap != monitor.FlapNone {
+		message := "monitor started flapping; state changes are suppressed"
+		if event.Flap == monitor.FlapStopped {
+			message = "monitor stopped flapping"
+		}
+		if err := s.addEntry(ctx, active.ID, EntryFlapping, message, result.StatusCode, "", result.CheckedAt); err != nil {
+			return err
+		}
+	}
+
+	if result.Outcome().Available() {
+		if active == nil || !(event.Transition && event.Monitor.Health.Available()) {
+			return nil
+		}
+		active.Status = StatusResolved
+		active.ResolvedAt = result.CheckedAt
+		if err := s.repo.UpdateStatus(ctx, active); err != nil {
+			return err
+		}
+		if err := s.addEntry(ctx, active.ID, EntryRecovered, "monitor recovered", result.StatusCode, "", result.CheckedAt); err != nil {
+			return err
+		}
+		return s.notifyListeners(ctx, active)
+	}
+
+	opened := false
+	if active == nil {
+		if !(event.Transition && event.Monitor.Health == monitor.HealthDown) {
+			return nil
+		}
+		active = &Incident{
+			ID:        generateID(),
+			MonitorID: event.Monitor.ID,
+			UserID:    event.Monitor.UserID,
+			URL:       event.Monitor.URL,
+			Status:    StatusOpen,
+			Cause:     result.ErrorClass,
+			StartedAt: result.CheckedAt,
+		}
+		if err := s.repo.Create(ctx, active); err != nil {
+			return err
+		}
+		opened = true
+	}
+
+	if err := s.addEntry(ctx, active.ID, EntryFailure, describeFailure(result), result.StatusCode, "", result.CheckedAt); err != nil {
+		return err
+	}
+	if result.AIExplanation != "" {
+		if err := s.addEntry(ctx, active.ID, EntryExplanation, result.AIExplanation, result.StatusCode, "", result.CheckedAt); err != nil {
+			return err
+		}
+	}
+	if opened {
+		return s.notifyListeners(ctx, active)
+	}
+	return nil
+}
+
+func (s *serviceImpl) AddListener(l Listener) {
+	s.listeners = append(s.listeners, l)
+}
+
+// notifyListeners fans a state change out to all listeners, returning their joined errors
+func (s *serviceImpl) notifyListeners(ctx context.Context, inc *Incident) error {
+	var errs []error
+	for _, l := range s.listeners {
+		if err := l.IncidentChanged(ctx, inc); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *serviceImpl) List(ctx context.Context, userID string, status Status) ([]*Incident, error) {
+	return s.repo.List(ctx, userID, status)
+}
+
+func (s *serviceImpl) Get(ctx context.Context, userID, id string) (*Incident, error) {
+	inc, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if inc.UserID != userID {
+		return nil, ErrForbidden
+	}
+	return inc, nil
+}
+
+func (s *serviceImpl) Acknowledge(ctx context.Context, userID, id string) (*Incident, error) {
+	inc, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if inc.Status != StatusOpen {
+		return nil, ErrInvalidTransition
+	}
+
+	now := time.Now()
+	inc.Status = StatusAcknowledged
+	inc.AcknowledgedAt = now
+	inc.AcknowledgedBy = userID
+	if err := s.repo.UpdateStatus(ctx, inc); err != nil {
+		return nil, err
+	}
+	if err := s.addEntry(ctx, id, EntryAcknowledged, "incident acknowledged", 0, userID, now); err != nil {
+		return nil, err
+	}
+	if err := s.notifyListeners(ctx, inc); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *serviceImpl) Resolve(ctx context.Context, userID, id string) (*Incident, error) {
+	inc, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if inc.Status == StatusResolved {
+		return nil, ErrInvalidTransition
+	}
+
+	now := time.Now()
+	inc.Status = StatusResolved
+	inc.ResolvedAt = now
+	inc.ResolvedBy = userID
+	if err := s.repo.UpdateStatus(ctx, inc); err != nil {
+		return nil, err
+	}
+	if err := s.addEntry(ctx, id, EntryResolved, "incident resolved manually", 0, userID, now); err != nil {
+		return nil, err
+	}
+	if err := s.notifyListeners(ctx, inc); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *serviceImpl) Comment(ctx context.Context, userID, id string, req CommentReq) (*Incident, error) {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if err := s.addEntry(ctx, id, EntryComment, req.Message, 0, userID, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *serviceImpl) addEntry(ctx context.Context, incidentID string, typ EntryType, message string, statusCode int, author string, at time.Time) error {
+	return s.repo.AddEntry(ctx, &TimelineEntry{
+		IncidentID: incidentID,
+		Type:       typ,
+		Message:    message,
+		StatusCode: statusCode,
+		Author:     author,
+		CreatedAt:  at,
+	})
+}
+
+// describeFailure renders a short human readable summary of a failed check
+func describeFailure(r *monitor.CheckResult) string {
+	if r.FailureReason != "" {
+		return fmt.Sprintf("check failed after %s: %s", r.ResponseTime, r.FailureReason)
+	}
+	if r.StatusCode > 0 {
+		return fmt.Sprintf("check failed with HTTP %d after %s", r.StatusCode, r.ResponseTime)
+	}
+	return fmt.Sprintf("check failed: %s error after %s", r.ErrorClass, r.ResponseTime)
+}
+
+func generateID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return time.Now().Format("20060102") + "-" + hex.EncodeToString(b)
+}
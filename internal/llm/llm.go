@@ -7,10 +7,35 @@ import (
 
 // FailureInput holds context about a health check failure
 type FailureInput struct {
-	URL          string
+	URL string
+	// CheckType names the kind of check, e.g. http or tcp; URL is a host:port for socket checks
+	CheckType    string
+	ErrorClass   string
 	StatusCode   int
 	ResponseTime time.Duration
 	Timestamp    time.Time
+	// FailureReason explains failures that are not visible from the status code alone,
+	// such as a failed response assertion
+	FailureReason string
+	// ExitCode and Output are reported by heartbeat jobs that failed
+	ExitCode *int
+	Output   string
+	// FailedStep names the step a multi-step transaction failed at
+	FailedStep string
+	// GRPCCode is the status code of a failed gRPC health check, e.g. Unavailable
+	GRPCCode string
+	// Timing breaks the request of an HTTP check down into its phases
+	Timing *Timing
+}
+
+// Timing holds the phases of an HTTP request; FirstByte runs from the request being written to
+// the first response byte
+type Timing struct {
+	DNS       time.Duration
+	Connect   time.Duration
+	TLS       time.Duration
+	FirstByte time.Duration
+	Transfer  time.Duration
 }
 
 // Provider defines the interface for AI-powered log/metrics analysis
@@ -36,9 +36,35 @@ func NewOllamaProvider(url, model string) Provider {
 
 func (p *ollamaProvider) AnalyzeFailure(ctx context.Context, input FailureInput) (string, error) {
 	prompt := fmt.Sprintf(
-		"Analyze this monitoring failure. URL: %s, Status Code: %d, Response Time: %s, Timestamp: %s. Briefly explain what might have gone wrong.",
+		"Analyze this monitoring failure. URL: %s, Status Code: %d, Response Time: %s, Timestamp: %s.",
 		input.URL, input.StatusCode, input.ResponseTime.String(), input.Timestamp.Format(time.RFC3339),
 	)
+	if input.CheckType != "" {
+		prompt += fmt.Sprintf(" Check type: %s.", input.CheckType)
+	}
+	if input.ErrorClass != "" {
+		prompt += fmt.Sprintf(" Error class: %s.", input.ErrorClass)
+	}
+	if input.FailureReason != "" {
+		prompt += fmt.Sprintf(" Failure reason: %s.", input.FailureReason)
+	}
+	if input.FailedStep != "" {
+		prompt += fmt.Sprintf(" Failed step: %s.", input.FailedStep)
+	}
+	if t := input.Timing; t != nil {
+		prompt += fmt.Sprintf(" Timing breakdown: DNS %s, TCP connect %s, TLS handshake %s, time to first byte %s, content transfer %s.",
+			t.DNS, t.Connect, t.TLS, t.FirstByte, t.Transfer)
+	}
+	if input.GRPCCode != "" {
+		prompt += fmt.Sprintf(" gRPC status code: %s.", input.GRPCCode)
+	}
+	if input.ExitCode != nil {
+		prompt += fmt.Sprintf(" Job exit code: %d.", *input.ExitCode)
+	}
+	if input.Output != "" {
+		prompt += fmt.Sprintf(" Job output: %s.", input.Output)
+	}
+	prompt += " Briefly explain what might have gone wrong."
 
 	reqBody := ollamaReq{
 		Model:  p.model,
@@ -0,0 +1,67 @@
+package maintenance
+
+import (
+	"time"
+
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+)
+
+// Window is a period during which checks for a monitor, or every monitor carrying a tag, are
+// skipped or have their alerts suppressed. A one-off window spans StartsAt to EndsAt; a
+// recurring window opens whenever Schedule (a 5-field cron expression, UTC) fires and stays
+// open for Duration.
+type Window struct {
+	ID        string                  `json:"id"`
+	UserID    string                  `json:"user_id"`
+	Name      string                  `json:"name"`
+	MonitorID string                  `json:"monitor_id,omitempty"`
+	Tag       string                  `json:"tag,omitempty"`
+	Mode      monitor.MaintenanceMode `json:"mode"`
+	StartsAt  time.Time               `json:"starts_at"`
+	EndsAt    time.Time               `json:"ends_at"`
+	Schedule  string                  `json:"schedule,omitempty"`
+	Duration  time.Duration           `json:"duration"`
+	CreatedAt time.Time               `json:"created_at"`
+
+	sched *schedule
+}
+
+// Recurring reports whether the window is driven by a cron schedule
+func (w *Window) Recurring() bool {
+	return w.Schedule != ""
+}
+
+// compile parses the schedule of a recurring window, once when the window is created or loaded
+func (w *Window) compile() error {
+	if !w.Recurring() {
+		return nil
+	}
+	sched, err := parseSchedule(w.Schedule)
+	if err != nil {
+		return err
+	}
+	w.sched = sched
+	return nil
+}
+
+// Active reports whether the window is open at the given time
+func (w *Window) Active(at time.Time) bool {
+	if !w.Recurring() {
+		return !at.Before(w.StartsAt) && at.Before(w.EndsAt)
+	}
+	if w.sched == nil {
+		return false
+	}
+	return w.sched.firedWithin(at.UTC(), w.Duration)
+}
+
+// Applies reports whether the window covers the given monitor
+func (w *Window) Applies(m *monitor.Monitor) bool {
+	if w.UserID != m.UserID {
+		return false
+	}
+	if w.MonitorID != "" {
+		return w.MonitorID == m.ID
+	}
+	return m.HasTag(w.Tag)
+}
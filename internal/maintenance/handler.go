@@ -0,0 +1,149 @@
+package maintenance
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+)
+
+// WindowResponse is the DTO used to shape the API response, with durations in minutes
+type WindowResponse struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	MonitorID string     `json:"monitor_id,omitempty"`
+	Tag       string     `json:"tag,omitempty"`
+	Mode      string     `json:"mode"`
+	StartsAt  *time.Time `json:"starts_at,omitempty"`
+	EndsAt    *time.Time `json:"ends_at,omitempty"`
+	Schedule  string     `json:"schedule,omitempty"`
+	Duration  int64      `json:"duration,omitempty"`
+	Active    bool       `json:"active"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func mapToResponse(w *Window) WindowResponse {
+	res := WindowResponse{
+		ID:        w.ID,
+		Name:      w.Name,
+		MonitorID: w.MonitorID,
+		Tag:       w.Tag,
+		Mode:      string(w.Mode),
+		Schedule:  w.Schedule,
+		Duration:  int64(w.Duration / time.Minute),
+		Active:    w.Active(time.Now()),
+		CreatedAt: w.CreatedAt,
+	}
+	if !w.Recurring() {
+		startsAt, endsAt := w.StartsAt, w.EndsAt
+		res.StartsAt = &startsAt
+		res.EndsAt = &endsAt
+	}
+	return res
+}
+
+// Handler processes HTTP maintenance window actions
+type Handler struct {
+	svc Service
+}
+
+// NewHandler generates a dependency-resolved Handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// Create handles registering a new maintenance window
+func (h *Handler) Create(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	var req CreateWindowReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid request data", "data": nil})
+		return
+	}
+
+	w, err := h.svc.Create(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		respondError(c, err, "failed to create maintenance window")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "message": "maintenance window created successfully", "data": mapToResponse(w)})
+}
+
+// List handles returning the caller's maintenance windows
+func (h *Handler) List(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	windows, err := h.svc.List(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "failed to list maintenance windows", "data": nil})
+		return
+	}
+
+	responseData := make([]WindowResponse, 0, len(windows))
+	for _, w := range windows {
+		responseData = append(responseData, mapToResponse(w))
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "maintenance windows retrieved", "data": responseData})
+}
+
+// Get handles fetching a single maintenance window
+func (h *Handler) Get(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	w, err := h.svc.Get(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to get maintenance window")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "maintenance window retrieved", "data": mapToResponse(w)})
+}
+
+// Delete handles removing a maintenance window
+func (h *Handler) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		respondError(c, err, "failed to delete maintenance window")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "maintenance window deleted successfully", "data": nil})
+}
+
+// respondError maps service errors onto HTTP status codes, falling back to a 500 with the given message
+func respondError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrInvalidWindow), errors.Is(err, ErrInvalidSchedule):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error(), "data": nil})
+	case errors.Is(err, ErrWindowNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "maintenance window not found", "data": nil})
+	case errors.Is(err, monitor.ErrMonitorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "monitor not found", "data": nil})
+	case errors.Is(err, ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "message": "forbidden", "data": nil})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": fallback, "data": nil})
+	}
+}
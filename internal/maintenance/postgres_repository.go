@@ -0,0 +1,153 @@
+package maintenance
+
+import (
+	"context"
+	"database/sql"
+)
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a postgres maintenance repository on an established connection pool
+func NewPostgresRepository(db *sql.DB) (Repository, error) {
+	if err := initSchema(db); err != nil {
+		return nil, err
+	}
+
+	return &postgresRepository{db: db}, nil
+}
+
+func initSchema(db *sql.DB) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS maintenance_windows (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			monitor_id TEXT REFERENCES monitors(id) ON DELETE CASCADE,
+			tag TEXT NOT NULL DEFAULT '',
+			mode TEXT NOT NULL,
+			starts_at TIMESTAMP NOT NULL,
+			ends_at TIMESTAMP NOT NULL,
+			schedule TEXT NOT NULL DEFAULT '',
+			duration BIGINT NOT NULL DEFAULT 0,
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_maintenance_windows_user ON maintenance_windows (user_id)`,
+		// A single row counts window changes so every replica can tell when its cache is stale
+		`CREATE TABLE IF NOT EXISTS maintenance_version (
+			id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+			version BIGINT NOT NULL DEFAULT 0
+		)`,
+		`INSERT INTO maintenance_version (id) VALUES (TRUE) ON CONFLICT DO NOTHING`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const windowColumns = `id, user_id, name, COALESCE(monitor_id, ''), tag, mode, starts_at, ends_at, schedule, duration, created_at`
+
+func (r *postgresRepository) Create(ctx context.Context, w *Window) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+	INSERT INTO maintenance_windows (id, user_id, name, monitor_id, tag, mode, starts_at, ends_at, schedule, duration, created_at)
+	VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9, $10, $11)
+	`
+	if _, err := tx.ExecContext(ctx, query,
+		w.ID, w.UserID, w.Name, w.MonitorID, w.Tag, w.Mode, w.StartsAt, w.EndsAt, w.Schedule, w.Duration, w.CreatedAt,
+	); err != nil {
+		return err
+	}
+	if err := bumpVersion(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRepository) GetByID(ctx context.Context, id string) (*Window, error) {
+	windows, err := r.queryWindows(ctx, `SELECT `+windowColumns+` FROM maintenance_windows WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(windows) == 0 {
+		return nil, ErrWindowNotFound
+	}
+	return windows[0], nil
+}
+
+func (r *postgresRepository) List(ctx context.Context, userID string) ([]*Window, error) {
+	return r.queryWindows(ctx, `SELECT `+windowColumns+` FROM maintenance_windows WHERE user_id = $1 ORDER BY created_at`, userID)
+}
+
+func (r *postgresRepository) All(ctx context.Context) ([]*Window, error) {
+	return r.queryWindows(ctx, `SELECT `+windowColumns+` FROM maintenance_windows`)
+}
+
+func (r *postgresRepository) queryWindows(ctx context.Context, query string, args ...interface{}) ([]*Window, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*Window
+	for rows.Next() {
+		var w Window
+		if err := rows.Scan(
+			&w.ID, &w.UserID, &w.Name, &w.MonitorID, &w.Tag, &w.Mode, &w.StartsAt, &w.EndsAt, &w.Schedule, &w.Duration, &w.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := w.compile(); err != nil {
+			return nil, err
+		}
+		result = append(result, &w)
+	}
+	return result, rows.Err()
+}
+
+func (r *postgresRepository) Delete(ctx context.Context, id string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM maintenance_windows WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrWindowNotFound
+	}
+	if err := bumpVersion(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// bumpVersion records a window change in the same transaction as the change itself
+func bumpVersion(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, `UPDATE maintenance_version SET version = version + 1`)
+	return err
+}
+
+func (r *postgresRepository) Version(ctx context.Context) (int64, error) {
+	var version int64
+	err := r.db.QueryRowContext(ctx, `SELECT version FROM maintenance_version`).Scan(&version)
+	return version, err
+}
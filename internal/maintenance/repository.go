@@ -0,0 +1,104 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Repository defines data access for maintenance windows
+type Repository interface {
+	Create(ctx context.Context, w *Window) error
+	GetByID(ctx context.Context, id string) (*Window, error)
+	List(ctx context.Context, userID string) ([]*Window, error)
+	// All returns every window across users, used to evaluate maintenance during scheduling
+	All(ctx context.Context) ([]*Window, error)
+	Delete(ctx context.Context, id string) error
+	// Version returns a counter bumped by every Create and Delete, so that nodes caching windows
+	// notice changes made through any other node
+	Version(ctx context.Context) (int64, error)
+}
+
+var ErrWindowNotFound = errors.New("maintenance window not found")
+
+type inMemoryRepository struct {
+	mu      sync.RWMutex
+	windows map[string]*Window
+	version int64
+}
+
+// NewRepository creates a new in-memory maintenance repository
+func NewRepository() Repository {
+	return &inMemoryRepository{
+		windows: make(map[string]*Window),
+	}
+}
+
+func (r *inMemoryRepository) Create(ctx context.Context, w *Window) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.windows[w.ID]; exists {
+		return errors.New("maintenance window already exists")
+	}
+	clone := *w
+	r.windows[w.ID] = &clone
+	r.version++
+	return nil
+}
+
+func (r *inMemoryRepository) GetByID(ctx context.Context, id string) (*Window, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	w, exists := r.windows[id]
+	if !exists {
+		return nil, ErrWindowNotFound
+	}
+	clone := *w
+	return &clone, nil
+}
+
+func (r *inMemoryRepository) List(ctx context.Context, userID string) ([]*Window, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*Window
+	for _, w := range r.windows {
+		if w.UserID == userID {
+			clone := *w
+			result = append(result, &clone)
+		}
+	}
+	return result, nil
+}
+
+func (r *inMemoryRepository) All(ctx context.Context) ([]*Window, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*Window, 0, len(r.windows))
+	for _, w := range r.windows {
+		clone := *w
+		result = append(result, &clone)
+	}
+	return result, nil
+}
+
+func (r *inMemoryRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.windows[id]; !exists {
+		return ErrWindowNotFound
+	}
+	delete(r.windows, id)
+	r.version++
+	return nil
+}
+
+func (r *inMemoryRepository) Version(ctx context.Context) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.version, nil
+}
@@ -0,0 +1,182 @@
+package maintenance
+
+import (
+	"errors"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSchedule is returned for cron expressions that cannot be parsed
+var ErrInvalidSchedule = errors.New("schedule must be a 5-field cron expression")
+
+// schedule is a parsed cron expression: minute, hour, day of month, month, day of week.
+// Each field is a bitset of the values it matches.
+type schedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+type fieldBounds struct {
+	min, max int
+}
+
+var scheduleFields = []fieldBounds{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 7},  // day of week, 0 and 7 are both Sunday
+}
+
+// parseSchedule parses a standard 5-field cron expression supporting *, lists, ranges and steps
+func parseSchedule(expr string) (*schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != len(scheduleFields) {
+		return nil, ErrInvalidSchedule
+	}
+
+	sets := make([]uint64, len(fields))
+	for i, field := range fields {
+		set, err := parseField(field, scheduleFields[i])
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = set
+	}
+
+	// Fold day 7 onto Sunday
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+
+	return &schedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}, nil
+}
+
+func parseField(field string, bounds fieldBounds) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if base, s, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 {
+				return 0, ErrInvalidSchedule
+			}
+			step = n
+			part = base
+		}
+
+		lo, hi := bounds.min, bounds.max
+		if part != "*" {
+			first, last, isRange := strings.Cut(part, "-")
+			var err error
+			if lo, err = strconv.Atoi(first); err != nil {
+				return 0, ErrInvalidSchedule
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(last); err != nil {
+					return 0, ErrInvalidSchedule
+				}
+			} else if step > 1 {
+				// "5/15" means every 15 starting at 5
+				hi = bounds.max
+			}
+		}
+		if lo < bounds.min || hi > bounds.max || lo > hi {
+			return 0, ErrInvalidSchedule
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// matches reports whether the schedule fires at the minute containing t
+func (s *schedule) matches(t time.Time) bool {
+	return s.minute&(1<<uint(t.Minute())) != 0 && s.hour&(1<<uint(t.Hour())) != 0 &&
+		s.month&(1<<uint(t.Month())) != 0 && s.dayMatches(t)
+}
+
+// dayMatches reports whether the schedule fires on the day of t. As in cron, when both day of
+// month and day of week are restricted a day matching either one fires.
+func (s *schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dowMatch
+	case s.dowAny:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+// firedWithin reports whether the schedule fired in the span of d ending at t, i.e. whether a
+// window of length d opened by the schedule is still open at t
+func (s *schedule) firedWithin(t time.Time, d time.Duration) bool {
+	_, ok := s.lastFire(t, t.Add(-d))
+	return ok
+}
+
+// lastFire returns the latest minute at or before t at which the schedule fires, provided it is
+// after since. Months, days and hours that cannot match are skipped whole, and within a matching
+// hour the minute is read off the bitset.
+func (s *schedule) lastFire(t, since time.Time) (time.Time, bool) {
+	at := t.Truncate(time.Minute)
+	for at.After(since) {
+		year, month, day := at.Date()
+		loc := at.Location()
+
+		months := s.month & (1<<uint(month+1) - 1)
+		if months == 0 {
+			at = time.Date(year, time.January, 1, 0, 0, 0, 0, loc).Add(-time.Minute)
+			continue
+		}
+		if m := time.Month(bits.Len64(months) - 1); m != month {
+			at = time.Date(year, m+1, 1, 0, 0, 0, 0, loc).Add(-time.Minute)
+			continue
+		}
+
+		if !s.dayMatches(at) {
+			at = time.Date(year, month, day, 0, 0, 0, 0, loc).Add(-time.Minute)
+			continue
+		}
+
+		hours := s.hour & (1<<uint(at.Hour()+1) - 1)
+		if hours == 0 {
+			at = time.Date(year, month, day, 0, 0, 0, 0, loc).Add(-time.Minute)
+			continue
+		}
+		if h := bits.Len64(hours) - 1; h != at.Hour() {
+			at = time.Date(year, month, day, h, 59, 0, 0, loc)
+			continue
+		}
+
+		minutes := s.minute & (1<<uint(at.Minute()+1) - 1)
+		if minutes == 0 {
+			at = time.Date(year, month, day, at.Hour(), 0, 0, 0, loc).Add(-time.Minute)
+			continue
+		}
+		at = time.Date(year, month, day, at.Hour(), bits.Len64(minutes)-1, 0, 0, loc)
+		if !at.After(since) {
+			break
+		}
+		return at, true
+	}
+	return time.Time{}, false
+}
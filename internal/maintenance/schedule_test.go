@@ -0,0 +1,117 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+)
+
+// bruteLastFire scans back minute by minute, as a reference for lastFire
+func bruteLastFire(s *schedule, t, since time.Time) (time.Time, bool) {
+	for at := t.Truncate(time.Minute); at.After(since); at = at.Add(-time.Minute) {
+		if s.matches(at) {
+			return at, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func TestScheduleLastFire(t *testing.T) {
+	exprs := []string{
+		"* * * * *",
+		"0 2 * * *",
+		"*/15 * * * *",
+		"30 22 * * 6,0",
+		"0 0 1 * *",
+		"0 3 15 * 1",
+		"45 23 31 12 *",
+		"5/20 8-17 * 1-6 1-5",
+		"0 0 29 2 *",
+	}
+	times := []time.Time{
+		time.Date(2026, 3, 1, 0, 0, 30, 0, time.UTC),
+		time.Date(2026, 1, 1, 0, 10, 0, 0, time.UTC),
+		time.Date(2026, 6, 15, 3, 0, 0, 0, time.UTC),
+		time.Date(2026, 10, 17, 22, 44, 59, 0, time.UTC),
+		time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+	}
+	spans := []time.Duration{time.Minute, 90 * time.Minute, 24 * time.Hour, 400 * 24 * time.Hour}
+
+	for _, expr := range exprs {
+		s, err := parseSchedule(expr)
+		if err != nil {
+			t.Fatalf("parse %q: %v", expr, err)
+		}
+		for _, at := range times {
+			for _, span := range spans {
+				since := at.Add(-span)
+				got, gotOK := s.lastFire(at, since)
+				want, wantOK := bruteLastFire(s, at, since)
+				if gotOK != wantOK || !got.Equal(want) {
+					t.Errorf("%q at %s within %s: got %s %v, want %s %v", expr, at, span, got, gotOK, want, wantOK)
+				}
+			}
+		}
+	}
+}
+
+func TestWindowActive(t *testing.T) {
+	tests := []struct {
+		name   string
+		window Window
+		at     time.Time
+		want   bool
+	}{
+		{
+			name:   "one-off open",
+			window: Window{StartsAt: time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC), EndsAt: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)},
+			at:     time.Date(2026, 5, 1, 11, 0, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "one-off ended",
+			window: Window{StartsAt: time.Date(2026, 5, 1, 10, 0, 0, 0, time.UTC), EndsAt: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)},
+			at:     time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "recurring at its start",
+			window: Window{Schedule: "0 2 * * *", Duration: time.Hour},
+			at:     time.Date(2026, 5, 1, 2, 0, 0, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "recurring just before it closes",
+			window: Window{Schedule: "0 2 * * *", Duration: time.Hour},
+			at:     time.Date(2026, 5, 1, 2, 59, 59, 0, time.UTC),
+			want:   true,
+		},
+		{
+			name:   "recurring closed",
+			window: Window{Schedule: "0 2 * * *", Duration: time.Hour},
+			at:     time.Date(2026, 5, 1, 3, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "recurring spanning midnight",
+			window: Window{Schedule: "30 23 * * 5", Duration: 2 * time.Hour},
+			at:     time.Date(2026, 10, 17, 1, 15, 0, 0, time.UTC), // a Saturday
+			want:   true,
+		},
+		{
+			name:   "recurring evaluated in UTC",
+			window: Window{Schedule: "0 2 * * *", Duration: time.Hour},
+			at:     time.Date(2026, 5, 1, 4, 30, 0, 0, time.FixedZone("UTC+2", 2*60*60)),
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := tt.window
+			if err := w.compile(); err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			if got := w.Active(tt.at); got != tt.want {
+				t.Errorf("active = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package maintenance
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+)
+
+// ErrForbidden is returned when a user references a window or monitor they do not own
+var ErrForbidden = errors.New("window or monitor belongs to another user")
+
+// ErrInvalidWindow is returned when a window request does not describe exactly one target and
+// exactly one of a one-off range or a recurring schedule
+var ErrInvalidWindow = errors.New("window needs either monitor_id or tag, and either starts_at/ends_at or schedule/duration")
+
+// versionCheckInterval bounds how stale the window set consulted during scheduling may be. The
+// cache is reused until the repository's version moves, which any replica's change does, and
+// the version is read at most this often.
+const versionCheckInterval = time.Second
+
+// CreateWindowReq defines the payload for creating a maintenance window
+type CreateWindowReq struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	MonitorID string     `json:"monitor_id"`
+	Tag       string     `json:"tag" binding:"omitempty,max=50"`
+	Mode      string     `json:"mode" binding:"required,oneof=skip suppress"`
+	StartsAt  *time.Time `json:"starts_at"`
+	EndsAt    *time.Time `json:"ends_at"`
+	Schedule  string     `json:"schedule"`
+	Duration  int        `json:"duration" binding:"omitempty,min=1,max=1440"` // in minutes
+}
+
+// Service manages maintenance windows and answers whether a monitor is currently in one
+type Service interface {
+	monitor.MaintenanceChecker
+	Create(ctx context.Context, userID string, req CreateWindowReq) (*Window, error)
+	List(ctx context.Context, userID string) ([]*Window, error)
+	Get(ctx context.Context, userID, id string) (*Window, error)
+	Delete(ctx context.Context, userID, id string) error
+}
+
+type serviceImpl struct {
+	repo     Repository
+	monitors monitor.Repository
+
+	mu        sync.Mutex
+	cached    []*Window
+	version   int64
+	checkedAt time.Time
+}
+
+// NewService creates a new maintenance service
+func NewService(repo Repository, monitors monitor.Repository) Service {
+	return &serviceImpl{repo: repo, monitors: monitors}
+}
+
+// ActiveMode returns the strictest mode among the windows open for the monitor at the given time
+func (s *serviceImpl) ActiveMode(ctx context.Context, m *monitor.Monitor, at time.Time) (monitor.MaintenanceMode, error) {
+	windows, err := s.windows(ctx)
+	if err != nil {
+		return monitor.MaintenanceNone, err
+	}
+
+	mode := monitor.MaintenanceNone
+	for _, w := range windows {
+		if !w.Applies(m) || !w.Active(at) {
+			continue
+		}
+		if w.Mode == monitor.MaintenanceSkip {
+			return monitor.MaintenanceSkip, nil
+		}
+		mode = w.Mode
+	}
+	return mode, nil
+}
+
+// windows returns every window, reloading from the repository when another change has been
+// recorded since the cache was filled
+func (s *serviceImpl) windows(ctx context.Context) ([]*Window, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != nil && time.Since(s.checkedAt) < versionCheckInterval {
+		return s.cached, nil
+	}
+
+	version, err := s.repo.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.cached != nil && version == s.version {
+		s.checkedAt = time.Now()
+		return s.cached, nil
+	}
+
+	windows, err := s.repo.All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if windows == nil {
+		windows = []*Window{}
+	}
+	// A change landing between the two reads leaves the older version, so the next check reloads
+	s.cached = windows
+	s.version = version
+	s.checkedAt = time.Now()
+	return windows, nil
+}
+
+// invalidate drops the cache so this node sees its own changes at once; other nodes pick them
+// up through the version
+func (s *serviceImpl) invalidate() {
+	s.mu.Lock()
+	s.cached = nil
+	s.mu.Unlock()
+}
+
+func (s *serviceImpl) Create(ctx context.Context, userID string, req CreateWindowReq) (*Window, error) {
+	if (req.MonitorID == "") == (req.Tag == "") {
+		return nil, ErrInvalidWindow
+	}
+
+	w := &Window{
+		ID:        generateID(),
+		UserID:    userID,
+		Name:      req.Name,
+		MonitorID: req.MonitorID,
+		Tag:       req.Tag,
+		Mode:      monitor.MaintenanceMode(req.Mode),
+		CreatedAt: time.Now(),
+	}
+
+	switch {
+	case req.Schedule != "" && req.Duration > 0 && req.StartsAt == nil && req.EndsAt == nil:
+		w.Schedule = req.Schedule
+		w.Duration = time.Duration(req.Duration) * time.Minute
+		if err := w.compile(); err != nil {
+			return nil, err
+		}
+	case req.Schedule == "" && req.Duration == 0 && req.StartsAt != nil && req.EndsAt != nil:
+		if !req.StartsAt.Before(*req.EndsAt) {
+			return nil, ErrInvalidWindow
+		}
+		w.StartsAt = *req.StartsAt
+		w.EndsAt = *req.EndsAt
+	default:
+		return nil, ErrInvalidWindow
+	}
+
+	if w.MonitorID != "" {
+		m, err := s.monitors.GetByID(ctx, w.MonitorID)
+		if err != nil {
+			return nil, err
+		}
+		if m.UserID != userID {
+			return nil, ErrForbidden
+		}
+	}
+
+	if err := s.repo.Create(ctx, w); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return w, nil
+}
+
+func (s *serviceImpl) List(ctx context.Context, userID string) ([]*Window, error) {
+	return s.repo.List(ctx, userID)
+}
+
+func (s *serviceImpl) Get(ctx context.Context, userID, id string) (*Window, error) {
+	w, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if w.UserID != userID {
+		return nil, ErrForbidden
+	}
+	return w, nil
+}
+
+func (s *serviceImpl) Delete(ctx context.Context, userID, id string) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+	return nil
+}
+
+func generateID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return time.Now().Format("20060102") + "-" + hex.EncodeToString(b)
+}
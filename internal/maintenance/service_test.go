@@ -0,0 +1,103 @@
+package maintenance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+)
+
+func TestActiveModeSeesChangesFromOtherNodes(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	monitors := monitor.NewRepository()
+	m := &monitor.Monitor{ID: "m", UserID: "u", Interval: time.Minute}
+	if err := monitors.Add(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+
+	// Two replicas share the repository; windows change through the other one
+	repo := NewRepository()
+	local := NewService(repo, monitors).(*serviceImpl)
+	other := NewService(repo, monitors)
+
+	starts, ends := now.Add(-time.Minute), now.Add(time.Hour)
+	req := CreateWindowReq{Name: "deploy", MonitorID: "m", Mode: string(monitor.MaintenanceSkip), StartsAt: &starts, EndsAt: &ends}
+
+	mode := func() monitor.MaintenanceMode {
+		t.Helper()
+		got, err := local.ActiveMode(ctx, m, now)
+		if err != nil {
+			t.Fatalf("active mode: %v", err)
+		}
+		return got
+	}
+	// expireCheck lets the next lookup read the version instead of waiting out the interval
+	expireCheck := func() {
+		local.mu.Lock()
+		local.checkedAt = time.Time{}
+		local.mu.Unlock()
+	}
+
+	if got := mode(); got != monitor.MaintenanceNone {
+		t.Fatalf("before any window: mode %q, want none", got)
+	}
+
+	w, err := other.Create(ctx, "u", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mode(); got != monitor.MaintenanceNone {
+		t.Errorf("within the check interval: mode %q, want the cached none", got)
+	}
+	expireCheck()
+	if got := mode(); got != monitor.MaintenanceSkip {
+		t.Fatalf("after a window was created elsewhere: mode %q, want skip", got)
+	}
+
+	if err := other.Delete(ctx, "u", w.ID); err != nil {
+		t.Fatal(err)
+	}
+	expireCheck()
+	if got := mode(); got != monitor.MaintenanceNone {
+		t.Errorf("after the window was deleted elsewhere: mode %q, want none", got)
+	}
+
+	// Changes made through this node apply at once
+	if _, err := local.Create(ctx, "u", req); err != nil {
+		t.Fatal(err)
+	}
+	if got := mode(); got != monitor.MaintenanceSkip {
+		t.Errorf("after a local create: mode %q, want skip", got)
+	}
+}
+
+func TestWindowsReusedWhileVersionUnchanged(t *testing.T) {
+	ctx := context.Background()
+	repo := &countingRepository{Repository: NewRepository()}
+	s := NewService(repo, monitor.NewRepository()).(*serviceImpl)
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.windows(ctx); err != nil {
+			t.Fatal(err)
+		}
+		s.mu.Lock()
+		s.checkedAt = time.Time{}
+		s.mu.Unlock()
+	}
+	if repo.loads != 1 {
+		t.Errorf("loaded windows %d times with no change, want once", repo.loads)
+	}
+}
+
+// countingRepository counts full window loads
+type countingRepository struct {
+	Repository
+	loads int
+}
+
+func (r *countingRepository) All(ctx context.Context) ([]*Window, error) {
+	r.loads++
+	return r.Repository.All(ctx)
+}
@@ -0,0 +1,161 @@
+package monitor
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler serves operational endpoints for administrators
+type AdminHandler struct {
+	scheduler *Scheduler
+	pool      *WorkerPool
+}
+
+// NewAdminHandler generates a dependency-resolved AdminHandler
+func NewAdminHandler(scheduler *Scheduler, pool *WorkerPool) *AdminHandler {
+	return &AdminHandler{scheduler: scheduler, pool: pool}
+}
+
+// SchedulerStatsResponse is the DTO used to shape scheduler metrics, with drift in milliseconds
+type SchedulerStatsResponse struct {
+	Ticks        int64     `json:"ticks"`
+	LastTickAt   time.Time `json:"last_tick_at"`
+	LastClaimed  int       `json:"last_claimed"`
+	MaxClaimed   int       `json:"max_claimed"`
+	TotalClaimed int64     `json:"total_claimed"`
+	LastMaxDrift int64     `json:"last_max_drift"`
+	MaxDrift     int64     `json:"max_drift"`
+	AvgDrift     int64     `json:"avg_drift"`
+	// ReclaimedLeases counts leases released because they expired or were left by a restart
+	ReclaimedLeases int64 `json:"reclaimed_leases"`
+	// SaturatedTicks counts ticks skipped because the worker queue was full, and Requeued the
+	// claimed monitors handed back when the pool could not take them
+	SaturatedTicks  int64     `json:"saturated_ticks"`
+	LastSaturatedAt time.Time `json:"last_saturated_at"`
+	Requeued        int64     `json:"requeued"`
+}
+
+// PoolStatsResponse is the DTO used to shape worker pool metrics, with the age of the oldest
+// queued job in milliseconds
+type PoolStatsResponse struct {
+	Workers       int   `json:"workers"`
+	TargetWorkers int   `json:"target_workers"`
+	Busy          int   `json:"busy"`
+	QueueDepth    int   `json:"queue_depth"`
+	QueueCapacity int   `json:"queue_capacity"`
+	OldestQueued  int64 `json:"oldest_queued"`
+	Submitted     int64 `json:"submitted"`
+	Waited        int64 `json:"waited"`
+	Dropped       int64 `json:"dropped"`
+}
+
+// ResizePoolReq is the payload used to change the number of workers
+type ResizePoolReq struct {
+	Workers int `json:"workers" binding:"required"`
+}
+
+// LeaseResponse is the DTO used to shape a monitor's lease; an expired lease is reclaimed on the
+// next scheduler tick
+type LeaseResponse struct {
+	MonitorID string    `json:"monitor_id"`
+	UserID    string    `json:"user_id"`
+	Type      string    `json:"type"`
+	URL       string    `json:"url"`
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Expired   bool      `json:"expired"`
+}
+
+// Scheduler reports how many monitors the scheduler claims per tick and how late it claims them
+func (h *AdminHandler) Scheduler(c *gin.Context) {
+	stats := h.scheduler.Stats()
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "scheduler stats retrieved", "data": SchedulerStatsResponse{
+		Ticks:        stats.Ticks,
+		LastTickAt:   stats.LastTickAt,
+		LastClaimed:  stats.LastClaimed,
+		MaxClaimed:   stats.MaxClaimed,
+		TotalClaimed: stats.TotalClaimed,
+		LastMaxDrift: stats.LastMaxDrift.Milliseconds(),
+		MaxDrift:     stats.MaxDrift.Milliseconds(),
+		AvgDrift:     stats.AvgDrift.Milliseconds(),
+
+		ReclaimedLeases: stats.ReclaimedLeases,
+		SaturatedTicks:  stats.SaturatedTicks,
+		LastSaturatedAt: stats.LastSaturatedAt,
+		Requeued:        stats.Requeued,
+	}})
+}
+
+// Workers reports the worker pool's size, queue depth and how many submissions waited or were
+// dropped
+func (h *AdminHandler) Workers(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "worker pool stats retrieved", "data": toPoolStatsResponse(h.pool.Stats())})
+}
+
+// ResizeWorkers changes the number of workers without a restart; the change is lost on restart,
+// where WORKER_POOL_SIZE applies again
+func (h *AdminHandler) ResizeWorkers(c *gin.Context) {
+	var req ResizePoolReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid request data", "data": nil})
+		return
+	}
+
+	if err := h.pool.Resize(req.Workers); err != nil {
+		respondError(c, err, "failed to resize worker pool")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "worker pool resized", "data": toPoolStatsResponse(h.pool.Stats())})
+}
+
+func toPoolStatsResponse(stats PoolStats) PoolStatsResponse {
+	return PoolStatsResponse{
+		Workers:       stats.Workers,
+		TargetWorkers: stats.TargetWorkers,
+		Busy:          stats.Busy,
+		QueueDepth:    stats.QueueDepth,
+		QueueCapacity: stats.QueueCapacity,
+		OldestQueued:  stats.OldestQueued.Milliseconds(),
+		Submitted:     stats.Submitted,
+		Waited:        stats.Waited,
+		Dropped:       stats.Dropped,
+	}
+}
+
+// Leases lists the monitors currently claimed by a node, so stuck checks can be spotted
+func (h *AdminHandler) Leases(c *gin.Context) {
+	monitors, err := h.scheduler.Leases(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "failed to list leases")
+		return
+	}
+
+	now := time.Now()
+	response := make([]LeaseResponse, 0, len(monitors))
+	for _, m := range monitors {
+		response = append(response, LeaseResponse{
+			MonitorID: m.ID,
+			UserID:    m.UserID,
+			Type:      string(m.Type),
+			URL:       m.URL,
+			Owner:     m.LeaseOwner,
+			ExpiresAt: m.LeaseExpiresAt,
+			Expired:   !m.Leased(now),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "leases retrieved", "data": response})
+}
+
+// ReleaseLease force-releases a monitor's lease so that it is claimed again once due
+func (h *AdminHandler) ReleaseLease(c *gin.Context) {
+	if err := h.scheduler.ForceRelease(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err, "failed to release lease")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "lease released", "data": nil})
+}
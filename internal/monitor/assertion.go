@@ -0,0 +1,331 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxBodyBytes caps how much of a response body is read for assertions; larger bodies are
+// evaluated on their first maxBodyBytes only
+const maxBodyBytes = 1 << 20
+
+// Assertion types evaluated against the response body, headers and timing
+const (
+	AssertContains    = "contains"
+	AssertNotContains = "not_contains"
+	AssertRegex       = "regex"
+	AssertJSONPath    = "json_path"
+	AssertJSONSchema  = "json_schema"
+	AssertHeader      = "header"
+	AssertLatency     = "latency"
+)
+
+// Severities decide whether a failed assertion fails the check or only marks it degraded
+const (
+	SeverityFail    = "fail"
+	SeverityDegrade = "degrade"
+)
+
+// Operators comparing the value found at a JSON path
+const (
+	OpEquals       = "equals"
+	OpNotEquals    = "not_equals"
+	OpExists       = "exists"
+	OpNotExists    = "not_exists"
+	OpGreater      = "gt"
+	OpGreaterEqual = "gte"
+	OpLess         = "lt"
+	OpLessEqual    = "lte"
+	OpContains     = "contains"
+	OpMatches      = "matches"
+)
+
+// Assertion is a condition a response must satisfy for the check to pass. Value holds the
+// keyword, regular expression, comparison operand or, for latency, the limit in milliseconds;
+// Path applies to json_path, Name to header, Op to both and Schema to json_schema. A failed
+// assertion with SeverityDegrade marks the check degraded instead of failed.
+type Assertion struct {
+	Type     string          `json:"type"`
+	Severity string          `json:"severity,omitempty"`
+	Name     string          `json:"name,omitempty"`
+	Path     string          `json:"path,omitempty"`
+	Op       string          `json:"op,omitempty"`
+	Value    string          `json:"value,omitempty"`
+	Schema   json.RawMessage `json:"schema,omitempty"`
+}
+
+// response is the part of an HTTP response that assertions inspect
+type response struct {
+	header  http.Header
+	body    []byte
+	elapsed time.Duration
+}
+
+// needsBody reports whether any assertion inspects the response body
+func needsBody(assertions []Assertion) bool {
+	for _, a := range assertions {
+		switch a.Type {
+		case AssertContains, AssertNotContains, AssertRegex, AssertJSONPath, AssertJSONSchema:
+			return true
+		}
+	}
+	return false
+}
+
+// validateAssertions rejects assertions that could never be evaluated
+func validateAssertions(assertions []Assertion) error {
+	for i, a := range assertions {
+		if err := a.validate(); err != nil {
+			return fmt.Errorf("assertion %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (a Assertion) validate() error {
+	switch a.Severity {
+	case "", SeverityFail, SeverityDegrade:
+	default:
+		return fmt.Errorf("%w: unknown severity %q", ErrInvalidCheck, a.Severity)
+	}
+
+	switch a.Type {
+	case AssertContains, AssertNotContains:
+		if a.Value == "" {
+			return fmt.Errorf("%w: %s needs a value", ErrInvalidCheck, a.Type)
+		}
+	case AssertRegex:
+		if _, err := regexp.Compile(a.Value); err != nil {
+			return fmt.Errorf("%w: regex %q: %v", ErrInvalidCheck, a.Value, err)
+		}
+	case AssertJSONPath:
+		if _, err := parseJSONPath(a.Path); err != nil {
+			return err
+		}
+		switch a.Op {
+		case OpExists, OpNotExists, OpEquals, OpNotEquals:
+		case OpGreater, OpGreaterEqual, OpLess, OpLessEqual:
+			if _, err := strconv.ParseFloat(a.Value, 64); err != nil {
+				return fmt.Errorf("%w: %s needs a numeric value", ErrInvalidCheck, a.Op)
+			}
+		default:
+			return fmt.Errorf("%w: unknown json path operator %q", ErrInvalidCheck, a.Op)
+		}
+	case AssertJSONSchema:
+		if _, err := parseJSONSchema(a.Schema); err != nil {
+			return err
+		}
+	case AssertHeader:
+		if a.Name == "" {
+			return fmt.Errorf("%w: header assertion needs a name", ErrInvalidCheck)
+		}
+		switch a.Op {
+		case OpExists, OpNotExists, OpEquals, OpNotEquals, OpContains:
+		case OpMatches:
+			if _, err := regexp.Compile(a.Value); err != nil {
+				return fmt.Errorf("%w: regex %q: %v", ErrInvalidCheck, a.Value, err)
+			}
+		default:
+			return fmt.Errorf("%w: unknown header operator %q", ErrInvalidCheck, a.Op)
+		}
+	case AssertLatency:
+		if ms, err := strconv.Atoi(a.Value); err != nil || ms <= 0 {
+			return fmt.Errorf("%w: latency needs a positive limit in milliseconds", ErrInvalidCheck)
+		}
+	default:
+		return fmt.Errorf("%w: unknown assertion type %q", ErrInvalidCheck, a.Type)
+	}
+	return nil
+}
+
+// evaluateAssertions runs every assertion against the response. It returns the reason of the
+// first failing assertion with fail severity and of the first with degrade severity; both are
+// "" when every assertion passes.
+func evaluateAssertions(assertions []Assertion, res response) (failure, degradation string) {
+	body := res.body
+
+	// The body is decoded at most once, and only if a JSON assertion asks for it
+	var doc interface{}
+	var decoded, valid bool
+	decode := func() (interface{}, bool) {
+		if !decoded {
+			decoded = true
+			valid = json.Unmarshal(body, &doc) == nil
+		}
+		return doc, valid
+	}
+
+	for _, a := range assertions {
+		var reason string
+		switch a.Type {
+		case AssertContains:
+			if !bytes.Contains(body, []byte(a.Value)) {
+				reason = fmt.Sprintf("body does not contain %q", a.Value)
+			}
+		case AssertNotContains:
+			if bytes.Contains(body, []byte(a.Value)) {
+				reason = fmt.Sprintf("body contains %q", a.Value)
+			}
+		case AssertRegex:
+			if !regexp.MustCompile(a.Value).Match(body) {
+				reason = fmt.Sprintf("body does not match %q", a.Value)
+			}
+		case AssertJSONPath:
+			v, ok := decode()
+			if !ok {
+				reason = "body is not valid JSON"
+				break
+			}
+			reason = a.evaluatePath(v)
+		case AssertJSONSchema:
+			v, ok := decode()
+			if !ok {
+				reason = "body is not valid JSON"
+				break
+			}
+			schema, _ := parseJSONSchema(a.Schema)
+			if msg := schema.validate(v, "$"); msg != "" {
+				reason = "body violates schema: " + msg
+			}
+		case AssertHeader:
+			reason = a.evaluateHeader(res.header)
+		case AssertLatency:
+			limit, _ := strconv.Atoi(a.Value)
+			if res.elapsed > time.Duration(limit)*time.Millisecond {
+				reason = fmt.Sprintf("response time %dms exceeds %dms", res.elapsed.Milliseconds(), limit)
+			}
+		}
+
+		switch {
+		case reason == "":
+		case a.Severity == SeverityDegrade:
+			if degradation == "" {
+				degradation = reason
+			}
+		default:
+			return reason, degradation
+		}
+	}
+	return "", degradation
+}
+
+func (a Assertion) evaluateHeader(header http.Header) string {
+	values, found := header[http.CanonicalHeaderKey(a.Name)]
+	actual := strings.Join(values, ", ")
+
+	switch a.Op {
+	case OpExists:
+		if !found {
+			return fmt.Sprintf("header %s is missing", a.Name)
+		}
+		return ""
+	case OpNotExists:
+		if found {
+			return fmt.Sprintf("header %s is present", a.Name)
+		}
+		return ""
+	}
+	if !found {
+		return fmt.Sprintf("header %s is missing", a.Name)
+	}
+
+	switch a.Op {
+	case OpEquals:
+		if actual != a.Value {
+			return fmt.Sprintf("header %s is %q, expected %q", a.Name, actual, a.Value)
+		}
+	case OpNotEquals:
+		if actual == a.Value {
+			return fmt.Sprintf("header %s is %q", a.Name, actual)
+		}
+	case OpContains:
+		if !strings.Contains(actual, a.Value) {
+			return fmt.Sprintf("header %s is %q, expected it to contain %q", a.Name, actual, a.Value)
+		}
+	case OpMatches:
+		if !regexp.MustCompile(a.Value).MatchString(actual) {
+			return fmt.Sprintf("header %s is %q, expected it to match %q", a.Name, actual, a.Value)
+		}
+	}
+	return ""
+}
+
+func (a Assertion) evaluatePath(doc interface{}) string {
+	path, _ := parseJSONPath(a.Path)
+	actual, found := path.lookup(doc)
+
+	switch a.Op {
+	case OpExists:
+		if !found {
+			return fmt.Sprintf("%s does not exist", a.Path)
+		}
+		return ""
+	case OpNotExists:
+		if found {
+			return fmt.Sprintf("%s exists", a.Path)
+		}
+		return ""
+	}
+	if !found {
+		return fmt.Sprintf("%s does not exist", a.Path)
+	}
+
+	switch a.Op {
+	case OpEquals:
+		if !jsonValueEquals(actual, a.Value) {
+			return fmt.Sprintf("%s is %s, expected %s", a.Path, formatJSONValue(actual), a.Value)
+		}
+	case OpNotEquals:
+		if jsonValueEquals(actual, a.Value) {
+			return fmt.Sprintf("%s is %s", a.Path, a.Value)
+		}
+	default:
+		n, ok := actual.(float64)
+		if !ok {
+			return fmt.Sprintf("%s is %s, not a number", a.Path, formatJSONValue(actual))
+		}
+		want, _ := strconv.ParseFloat(a.Value, 64)
+		var pass bool
+		switch a.Op {
+		case OpGreater:
+			pass = n > want
+		case OpGreaterEqual:
+			pass = n >= want
+		case OpLess:
+			pass = n < want
+		case OpLessEqual:
+			pass = n <= want
+		}
+		if !pass {
+			return fmt.Sprintf("%s is %v, expected %s %s", a.Path, n, a.Op, a.Value)
+		}
+	}
+	return ""
+}
+
+// jsonValueEquals compares a decoded JSON value with the operand as written by the user:
+// strings compare verbatim, numbers numerically and anything else by its JSON encoding
+func jsonValueEquals(actual interface{}, want string) bool {
+	switch v := actual.(type) {
+	case string:
+		return v == want
+	case float64:
+		n, err := strconv.ParseFloat(want, 64)
+		return err == nil && n == v
+	}
+	return formatJSONValue(actual) == want
+}
+
+func formatJSONValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	encoded, _ := json.Marshal(v)
+	return string(encoded)
+}
@@ -0,0 +1,181 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestValidateAssertionsMalformed(t *testing.T) {
+	tests := []struct {
+		name      string
+		assertion Assertion
+	}{
+		{name: "unknown type", assertion: Assertion{Type: "xpath"}},
+		{name: "unknown severity", assertion: Assertion{Type: AssertContains, Value: "ok", Severity: "warn"}},
+		{name: "contains without value", assertion: Assertion{Type: AssertContains}},
+		{name: "invalid regex", assertion: Assertion{Type: AssertRegex, Value: "(unclosed"}},
+		{name: "malformed json path", assertion: Assertion{Type: AssertJSONPath, Path: "$.items[", Op: OpExists}},
+		{name: "json path without root", assertion: Assertion{Type: AssertJSONPath, Path: "items", Op: OpExists}},
+		{name: "unknown json path operator", assertion: Assertion{Type: AssertJSONPath, Path: "$.a", Op: "like"}},
+		{name: "non-numeric comparison", assertion: Assertion{Type: AssertJSONPath, Path: "$.a", Op: OpGreater, Value: "many"}},
+		{name: "malformed schema", assertion: Assertion{Type: AssertJSONSchema, Schema: json.RawMessage(`{"type":"uuid"}`)}},
+		{name: "header without name", assertion: Assertion{Type: AssertHeader, Op: OpExists}},
+		{name: "unknown header operator", assertion: Assertion{Type: AssertHeader, Name: "X", Op: OpGreater}},
+		{name: "invalid header regex", assertion: Assertion{Type: AssertHeader, Name: "X", Op: OpMatches, Value: "["}},
+		{name: "latency not a number", assertion: Assertion{Type: AssertLatency, Value: "fast"}},
+		{name: "latency not positive", assertion: Assertion{Type: AssertLatency, Value: "0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid := Assertion{Type: AssertContains, Value: "ok"}
+			err := validateAssertions([]Assertion{valid, tt.assertion})
+			if !errors.Is(err, ErrInvalidCheck) {
+				t.Fatalf("err = %v, want ErrInvalidCheck", err)
+			}
+		})
+	}
+}
+
+func TestEvaluateAssertions(t *testing.T) {
+	res := response{
+		header:  http.Header{"Content-Type": {"application/json; charset=utf-8"}, "X-Version": {"2"}},
+		body:    []byte(`{"status":"ok","data":{"count":3,"items":[{"id":1,"name":"a"},{"id":2,"name":"b"}],"ready":true,"owner":null}}`),
+		elapsed: 150 * time.Millisecond,
+	}
+
+	tests := []struct {
+		name       string
+		assertions []Assertion
+		body       string
+		failure    string
+		degraded   string
+	}{
+		{
+			name:       "body contains",
+			assertions: []Assertion{{Type: AssertContains, Value: `"status":"ok"`}, {Type: AssertNotContains, Value: "error"}},
+		},
+		{
+			name:       "body missing keyword",
+			assertions: []Assertion{{Type: AssertContains, Value: "healthy"}},
+			failure:    `body does not contain "healthy"`,
+		},
+		{
+			name:       "body regex",
+			assertions: []Assertion{{Type: AssertRegex, Value: `"count":\d+`}},
+		},
+		{
+			name: "nested path and array index",
+			assertions: []Assertion{
+				{Type: AssertJSONPath, Path: "$.data.items[1].name", Op: OpEquals, Value: "b"},
+				{Type: AssertJSONPath, Path: "$.data.items[0].id", Op: OpEquals, Value: "1.0"},
+				{Type: AssertJSONPath, Path: "$.data.ready", Op: OpEquals, Value: "true"},
+				{Type: AssertJSONPath, Path: "$.data.owner", Op: OpEquals, Value: "null"},
+				{Type: AssertJSONPath, Path: "$.data.items[2]", Op: OpNotExists},
+				{Type: AssertJSONPath, Path: "$.data.count", Op: OpGreaterEqual, Value: "3"},
+				{Type: AssertJSONPath, Path: "$.data.count", Op: OpLess, Value: "10"},
+			},
+		},
+		{
+			name:       "path value differs",
+			assertions: []Assertion{{Type: AssertJSONPath, Path: "$.data.items[1].name", Op: OpEquals, Value: "c"}},
+			failure:    `$.data.items[1].name is "b", expected c`,
+		},
+		{
+			name:       "index past the end",
+			assertions: []Assertion{{Type: AssertJSONPath, Path: "$.data.items[5].id", Op: OpEquals, Value: "5"}},
+			failure:    "$.data.items[5].id does not exist",
+		},
+		{
+			name:       "number compared with a string",
+			assertions: []Assertion{{Type: AssertJSONPath, Path: "$.data.count", Op: OpEquals, Value: "three"}},
+			failure:    "$.data.count is 3, expected three",
+		},
+		{
+			name:       "ordering on a non-number",
+			assertions: []Assertion{{Type: AssertJSONPath, Path: "$.status", Op: OpGreater, Value: "1"}},
+			failure:    `$.status is "ok", not a number`,
+		},
+		{
+			name:       "ordering on an object",
+			assertions: []Assertion{{Type: AssertJSONPath, Path: "$.data.items[0]", Op: OpLessEqual, Value: "1"}},
+			failure:    `$.data.items[0] is {"id":1,"name":"a"}, not a number`,
+		},
+		{
+			name:       "key on an array",
+			assertions: []Assertion{{Type: AssertJSONPath, Path: "$.data.items.id", Op: OpExists}},
+			failure:    "$.data.items.id does not exist",
+		},
+		{
+			name:       "comparison fails",
+			assertions: []Assertion{{Type: AssertJSONPath, Path: "$.data.count", Op: OpGreater, Value: "3"}},
+			failure:    "$.data.count is 3, expected gt 3",
+		},
+		{
+			name:       "body is not JSON",
+			assertions: []Assertion{{Type: AssertJSONPath, Path: "$.status", Op: OpExists}},
+			body:       "<html>ok</html>",
+			failure:    "body is not valid JSON",
+		},
+		{
+			name:       "schema violation",
+			assertions: []Assertion{{Type: AssertJSONSchema, Schema: json.RawMessage(`{"properties":{"data":{"properties":{"count":{"type":"string"}}}}}`)}},
+			failure:    "body violates schema: $.data.count: expected type [string], got integer",
+		},
+		{
+			name: "headers",
+			assertions: []Assertion{
+				{Type: AssertHeader, Name: "content-type", Op: OpContains, Value: "json"},
+				{Type: AssertHeader, Name: "X-Version", Op: OpEquals, Value: "2"},
+				{Type: AssertHeader, Name: "X-Version", Op: OpMatches, Value: `^\d+$`},
+				{Type: AssertHeader, Name: "Retry-After", Op: OpNotExists},
+			},
+		},
+		{
+			name:       "header missing",
+			assertions: []Assertion{{Type: AssertHeader, Name: "X-Request-Id", Op: OpEquals, Value: "1"}},
+			failure:    "header X-Request-Id is missing",
+		},
+		{
+			name:       "latency within limit",
+			assertions: []Assertion{{Type: AssertLatency, Value: "200"}},
+		},
+		{
+			name:       "latency over limit degrades",
+			assertions: []Assertion{{Type: AssertLatency, Value: "100", Severity: SeverityDegrade}},
+			degraded:   "response time 150ms exceeds 100ms",
+		},
+		{
+			name: "failure reported after a degradation",
+			assertions: []Assertion{
+				{Type: AssertLatency, Value: "100", Severity: SeverityDegrade},
+				{Type: AssertContains, Value: "healthy"},
+				{Type: AssertNotContains, Value: "ok"},
+			},
+			failure:  `body does not contain "healthy"`,
+			degraded: "response time 150ms exceeds 100ms",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateAssertions(tt.assertions); err != nil {
+				t.Fatalf("validate: %v", err)
+			}
+			r := res
+			if tt.body != "" {
+				r.body = []byte(tt.body)
+			}
+			failure, degraded := evaluateAssertions(tt.assertions, r)
+			if failure != tt.failure {
+				t.Errorf("failure = %q, want %q", failure, tt.failure)
+			}
+			if degraded != tt.degraded {
+				t.Errorf("degradation = %q, want %q", degraded, tt.degraded)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DNS record types a dns monitor can resolve
+const (
+	RecordA     = "A"
+	RecordAAAA  = "AAAA"
+	RecordCNAME = "CNAME"
+	RecordMX    = "MX"
+	RecordTXT   = "TXT"
+)
+
+// DNSCheck configures a dns monitor. Resolver is a host[:port] queried instead of the system
+// resolver. Expected values must all be among the answers, or be exactly the answers when Exact
+// is set; MX values are written as "preference host".
+type DNSCheck struct {
+	RecordType string   `json:"record_type,omitempty"`
+	Resolver   string   `json:"resolver,omitempty"`
+	Expected   []string `json:"expected,omitempty"`
+	Exact      bool     `json:"exact,omitempty"`
+}
+
+func (c DNSCheck) recordType() string {
+	if c.RecordType == "" {
+		return RecordA
+	}
+	return c.RecordType
+}
+
+// resolver returns a resolver that sends every query to the configured address, or the system
+// resolver when none is set
+func (c DNSCheck) resolver() *net.Resolver {
+	if c.Resolver == "" {
+		return net.DefaultResolver
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, c.Resolver)
+		},
+	}
+}
+
+// checkDNS resolves the monitor's name, compares the answers against the expected values and
+// flags answers that differ from those of the previous successful resolution
+func (wp *WorkerPool) checkDNS(ctx context.Context, m *Monitor) *CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, m.Request.timeout())
+	defer cancel()
+
+	start := time.Now()
+	answers, err := lookup(ctx, m.DNS.resolver(), m.DNS.recordType(), m.URL)
+	duration := time.Since(start)
+
+	result := &CheckResult{
+		MonitorID:    m.ID,
+		CheckedAt:    time.Now(),
+		ResponseTime: duration,
+	}
+	if err != nil {
+		// Queries were redirected by the dialer, so the error names the system resolver instead
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && m.DNS.Resolver != "" {
+			dnsErr.Server = m.DNS.Resolver
+		}
+		wp.logger.Warn("DNS resolution failed", zap.Error(err), zap.String("name", m.URL))
+		result.ErrorClass = classifyError(err)
+		result.FailureReason = err.Error()
+		return result
+	}
+
+	result.Answers = answers
+	result.AnswersChanged = len(m.Answers) > 0 && !slices.Equal(m.Answers, answers)
+	if result.AnswersChanged {
+		wp.logger.Warn("DNS answers changed",
+			zap.String("name", m.URL),
+			zap.Strings("previous", m.Answers),
+			zap.Strings("current", answers),
+		)
+	}
+
+	result.IsHealthy = true
+	if reason := m.DNS.mismatch(answers); reason != "" {
+		result.IsHealthy = false
+		result.ErrorClass = ErrorClassAssertion
+		result.FailureReason = reason
+	}
+	return result
+}
+
+// mismatch describes how the answers miss the expected values, or returns "" when they match
+func (c DNSCheck) mismatch(answers []string) string {
+	if len(c.Expected) == 0 {
+		return ""
+	}
+	expected := make([]string, 0, len(c.Expected))
+	for _, v := range c.Expected {
+		expected = append(expected, normalizeAnswer(c.recordType(), v))
+	}
+	slices.Sort(expected)
+	expected = slices.Compact(expected)
+
+	if c.Exact {
+		if !slices.Equal(expected, answers) {
+			return fmt.Sprintf("%s answers [%s] differ from expected [%s]",
+				c.recordType(), strings.Join(answers, ", "), strings.Join(expected, ", "))
+		}
+		return ""
+	}
+	for _, v := range expected {
+		if !slices.Contains(answers, v) {
+			return fmt.Sprintf("%s answers [%s] do not include %s", c.recordType(), strings.Join(answers, ", "), v)
+		}
+	}
+	return ""
+}
+
+// lookup resolves name for a record type and returns its normalized answers, sorted
+func lookup(ctx context.Context, r *net.Resolver, recordType, name string) ([]string, error) {
+	var answers []string
+	switch recordType {
+	case RecordA, RecordAAAA:
+		network := "ip4"
+		if recordType == RecordAAAA {
+			network = "ip6"
+		}
+		ips, err := r.LookupIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case RecordCNAME:
+		cname, err := r.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, cname)
+	case RecordMX:
+		mxs, err := r.LookupMX(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range mxs {
+			answers = append(answers, fmt.Sprintf("%d %s", mx.Pref, mx.Host))
+		}
+	case RecordTXT:
+		txts, err := r.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		answers = txts
+	default:
+		return nil, fmt.Errorf("%w: unsupported record type %q", ErrInvalidCheck, recordType)
+	}
+
+	for i, a := range answers {
+		answers[i] = normalizeAnswer(recordType, a)
+	}
+	slices.Sort(answers)
+	return slices.Compact(answers), nil
+}
+
+// normalizeAnswer puts an answer or expected value into a comparable form: canonical IPs, and
+// lower-case names without the trailing dot. TXT values are compared verbatim.
+func normalizeAnswer(recordType, v string) string {
+	switch recordType {
+	case RecordA, RecordAAAA:
+		if ip := net.ParseIP(strings.TrimSpace(v)); ip != nil {
+			return ip.String()
+		}
+		return strings.TrimSpace(v)
+	case RecordCNAME:
+		return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(v), "."))
+	case RecordMX:
+		fields := strings.Fields(v)
+		if len(fields) == 2 {
+			return fields[0] + " " + strings.ToLower(strings.TrimSuffix(fields[1], "."))
+		}
+		return strings.ToLower(strings.TrimSpace(v))
+	default:
+		return v
+	}
+}
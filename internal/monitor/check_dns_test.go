@@ -0,0 +1,147 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startDNSStub serves the given A records over UDP on localhost and answers NXDOMAIN for any
+// other name; it returns the stub's address
+func startDNSStub(t *testing.T, records map[string][]string) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var req dnsmessage.Message
+			if err := req.Unpack(buf[:n]); err != nil || len(req.Questions) != 1 {
+				continue
+			}
+			resp, err := answerStub(req, records)
+			if err != nil {
+				continue
+			}
+			_, _ = conn.WriteTo(resp, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func answerStub(req dnsmessage.Message, records map[string][]string) ([]byte, error) {
+	q := req.Questions[0]
+	resp := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: req.ID, Response: true, Authoritative: true, RecursionDesired: req.RecursionDesired},
+		Questions: req.Questions,
+	}
+
+	ips, ok := records[strings.TrimSuffix(q.Name.String(), ".")]
+	if !ok {
+		resp.RCode = dnsmessage.RCodeNameError
+		return resp.Pack()
+	}
+	if q.Type != dnsmessage.TypeA {
+		// The name exists but has no records of this type
+		return resp.Pack()
+	}
+	for _, ip := range ips {
+		var a dnsmessage.AResource
+		copy(a.A[:], net.ParseIP(ip).To4())
+		resp.Answers = append(resp.Answers, dnsmessage.Resource{
+			Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: 60},
+			Body:   &a,
+		})
+	}
+	return resp.Pack()
+}
+
+func TestCheckDNSAgainstStub(t *testing.T) {
+	addr := startDNSStub(t, map[string][]string{
+		"api.example.test": {"192.0.2.10", "192.0.2.11"},
+	})
+	wp := NewWorkerPool(1, nil, zap.NewNop(), nil)
+
+	tests := []struct {
+		name        string
+		host        string
+		dns         DNSCheck
+		healthy     bool
+		errorClass  string
+		wantAnswers []string
+	}{
+		{
+			name:        "expected answer present",
+			host:        "api.example.test",
+			dns:         DNSCheck{Expected: []string{"192.0.2.11"}},
+			healthy:     true,
+			wantAnswers: []string{"192.0.2.10", "192.0.2.11"},
+		},
+		{
+			name:    "exact answers match in any order",
+			host:    "api.example.test",
+			dns:     DNSCheck{Expected: []string{"192.0.2.11", "192.0.2.10"}, Exact: true},
+			healthy: true,
+		},
+		{
+			name:       "expected answer missing",
+			host:       "api.example.test",
+			dns:        DNSCheck{Expected: []string{"192.0.2.99"}},
+			errorClass: ErrorClassAssertion,
+		},
+		{
+			name:       "exact answers differ",
+			host:       "api.example.test",
+			dns:        DNSCheck{Expected: []string{"192.0.2.10"}, Exact: true},
+			errorClass: ErrorClassAssertion,
+		},
+		{
+			name:       "unknown name",
+			host:       "missing.example.test",
+			dns:        DNSCheck{Expected: []string{"192.0.2.10"}},
+			errorClass: ErrorClassDNS,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.dns.Resolver = addr
+			m := &Monitor{ID: "dns", Type: TypeDNS, URL: tt.host, DNS: tt.dns}
+
+			result := wp.checkDNS(context.Background(), m)
+			if result.IsHealthy != tt.healthy {
+				t.Fatalf("healthy = %v, want %v (reason %q)", result.IsHealthy, tt.healthy, result.FailureReason)
+			}
+			if result.ErrorClass != tt.errorClass {
+				t.Errorf("error class = %q, want %q", result.ErrorClass, tt.errorClass)
+			}
+			if tt.wantAnswers != nil && strings.Join(result.Answers, ",") != strings.Join(tt.wantAnswers, ",") {
+				t.Errorf("answers = %v, want %v", result.Answers, tt.wantAnswers)
+			}
+		})
+	}
+}
+
+func TestCheckDNSFlagsChangedAnswers(t *testing.T) {
+	addr := startDNSStub(t, map[string][]string{"api.example.test": {"192.0.2.20"}})
+	wp := NewWorkerPool(1, nil, zap.NewNop(), nil)
+
+	m := &Monitor{ID: "dns", Type: TypeDNS, URL: "api.example.test", DNS: DNSCheck{Resolver: addr}, Answers: []string{"192.0.2.10"}}
+	result := wp.checkDNS(context.Background(), m)
+	if !result.IsHealthy || !result.AnswersChanged {
+		t.Fatalf("healthy = %v, changed = %v; want a healthy result flagged as changed", result.IsHealthy, result.AnswersChanged)
+	}
+}
@@ -0,0 +1,86 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// startHealthServer runs an in-process gRPC server exposing the standard health service and
+// returns its address with the service's status controller
+func startHealthServer(t *testing.T) (string, *health.Server) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv := grpc.NewServer()
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return lis.Addr().String(), hs
+}
+
+func TestCheckGRPC(t *testing.T) {
+	addr, hs := startHealthServer(t)
+	hs.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+	hs.SetServingStatus("billing", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	// A listener closed straight away leaves a port nothing answers on
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	unreachable := lis.Addr().String()
+	lis.Close()
+
+	wp := NewWorkerPool(1, nil, zap.NewNop(), nil)
+
+	tests := []struct {
+		name       string
+		target     string
+		service    string
+		healthy    bool
+		errorClass string
+		grpcCode   string
+	}{
+		{name: "server serving", target: addr, healthy: true, grpcCode: codes.OK.String()},
+		{name: "service serving", target: addr, service: "orders", healthy: true, grpcCode: codes.OK.String()},
+		{name: "service not serving", target: addr, service: "billing", errorClass: ErrorClassNotServing, grpcCode: codes.OK.String()},
+		{name: "unknown service", target: addr, service: "missing", errorClass: ErrorClassGRPCStatus, grpcCode: codes.NotFound.String()},
+		{name: "unreachable target", target: unreachable, errorClass: ErrorClassConnection, grpcCode: codes.Unavailable.String()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Monitor{
+				ID:      "grpc",
+				Type:    TypeGRPC,
+				URL:     tt.target,
+				Request: HTTPRequest{Timeout: 2 * time.Second},
+				GRPC:    GRPCCheck{Service: tt.service},
+			}
+
+			result := wp.checkGRPC(context.Background(), m)
+			if result.IsHealthy != tt.healthy {
+				t.Fatalf("healthy = %v, want %v (reason %q)", result.IsHealthy, tt.healthy, result.FailureReason)
+			}
+			if result.ErrorClass != tt.errorClass {
+				t.Errorf("error class = %q, want %q", result.ErrorClass, tt.errorClass)
+			}
+			if result.GRPCCode != tt.grpcCode {
+				t.Errorf("gRPC code = %q, want %q", result.GRPCCode, tt.grpcCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// httpClients holds one client per redirect policy; per-check timeouts are applied through the
+// request context so the clients themselves only carry a safety ceiling
+type httpClients struct {
+	follow   *http.Client
+	noFollow *http.Client
+}
+
+func newHTTPClients(roots *x509.CertPool) *httpClients {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: roots}
+	// Every check opens its own connection so that its DNS, connect and TLS phases are measured
+	// as a new client would see them
+	transport.DisableKeepAlives = true
+
+	return &httpClients{
+		follow: &http.Client{Timeout: maxCheckTimeout, Transport: transport},
+		noFollow: &http.Client{
+			Timeout:   maxCheckTimeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (c *httpClients) forRequest(spec HTTPRequest) *http.Client {
+	if spec.FollowRedirects {
+		return c.follow
+	}
+	return c.noFollow
+}
+
+// checkHTTP sends the monitor's configured request and returns its raw outcome, judging health
+// by the expected status set, then by any response assertions and finally by the certificate
+// of an HTTPS target
+func (wp *WorkerPool) checkHTTP(ctx context.Context, clients *httpClients, m *Monitor) *CheckResult {
+	result, _ := wp.sendHTTP(ctx, clients, m, m.URL, m.Request, m.Assertions, false)
+	return result
+}
+
+// sendHTTP sends one request to target and judges the response as checkHTTP does. The response
+// is returned for a reachable target, with its body read when keepBody is set or an assertion
+// needs it.
+func (wp *WorkerPool) sendHTTP(ctx context.Context, clients *httpClients, m *Monitor, target string, spec HTTPRequest, assertions []Assertion, keepBody bool) (*CheckResult, *response) {
+	ctx, cancel := context.WithTimeout(ctx, spec.timeout())
+	defer cancel()
+	tracer := &requestTracer{}
+	ctx = httptrace.WithClientTrace(ctx, tracer.clientTrace())
+
+	start := time.Now()
+
+	var body io.Reader
+	if spec.Body != "" {
+		body = strings.NewReader(spec.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, spec.method(), target, body)
+	if err != nil {
+		wp.logger.Error("Failed to create request", zap.Error(err), zap.String("url", target))
+		return &CheckResult{MonitorID: m.ID, CheckedAt: time.Now(), ErrorClass: ErrorClassInvalidRequest}, nil
+	}
+	for k, v := range spec.Headers {
+		if strings.EqualFold(k, "Host") {
+			req.Host = v
+			continue
+		}
+		req.Header.Set(k, v)
+	}
+
+	res, err := clients.forRequest(spec).Do(req)
+	duration := time.Since(start)
+
+	now := time.Now()
+
+	if err != nil {
+		wp.logger.Warn("Health check unreachable", zap.Error(err), zap.String("url", target))
+		return &CheckResult{
+			MonitorID:    m.ID,
+			CheckedAt:    now,
+			ResponseTime: duration,
+			ErrorClass:   classifyError(err),
+			Timing:       tracer.finish(),
+		}, nil
+	}
+	defer res.Body.Close()
+
+	result := &CheckResult{
+		MonitorID:    m.ID,
+		CheckedAt:    now,
+		StatusCode:   res.StatusCode,
+		ResponseTime: duration,
+	}
+	inspected := &response{header: res.Header, elapsed: duration}
+	judgeResponse(spec, assertions, res, inspected, keepBody, result)
+	// The rest of the body is read, up to the same cap, so that its transfer is timed
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxBodyBytes-int64(len(inspected.body))))
+	result.Timing = tracer.finish()
+	if res.TLS != nil {
+		cert := inspectCertificates(res.TLS.PeerCertificates, res.Request.URL.Hostname(), wp.roots, now)
+		judgeCertificate(result, cert, m.TLS.ExpiryWarnDays, now)
+	}
+	return result, inspected
+}
+
+// judgeResponse decides a result from the status code and then from the assertions, reading the
+// body into inspected when needed
+func judgeResponse(spec HTTPRequest, assertions []Assertion, res *http.Response, inspected *response, keepBody bool, result *CheckResult) {
+	result.IsHealthy = spec.expected().contains(res.StatusCode)
+	if !result.IsHealthy {
+		result.ErrorClass = ErrorClassHTTPStatus
+		return
+	}
+
+	if keepBody || needsBody(assertions) {
+		body, err := io.ReadAll(io.LimitReader(res.Body, maxBodyBytes))
+		if err != nil {
+			result.IsHealthy = false
+			result.ErrorClass = classifyError(err)
+			return
+		}
+		inspected.body = body
+	}
+	if len(assertions) == 0 {
+		return
+	}
+
+	failure, degradation := evaluateAssertions(assertions, *inspected)
+	switch {
+	case failure != "":
+		result.IsHealthy = false
+		result.ErrorClass = ErrorClassAssertion
+		result.FailureReason = failure
+	case degradation != "":
+		result.IsHealthy = false
+		result.IsDegraded = true
+		result.FailureReason = degradation
+	}
+}
@@ -0,0 +1,79 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TCPCheck configures a tcp monitor. Send is written once connected; Expect, when set, must
+// prefix the first bytes read back, which for most protocols is the server's banner or the
+// reply to Send.
+type TCPCheck struct {
+	Send   string `json:"send,omitempty"`
+	Expect string `json:"expect,omitempty"`
+}
+
+// checkTCP connects to a host:port target and, when configured, exchanges a payload and matches
+// the response prefix. The recorded response time is the connect time.
+func (wp *WorkerPool) checkTCP(ctx context.Context, m *Monitor) *CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, m.Request.timeout())
+	defer cancel()
+
+	var dialer net.Dialer
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", m.URL)
+	duration := time.Since(start)
+
+	if err != nil {
+		wp.logger.Warn("TCP connect failed", zap.Error(err), zap.String("target", m.URL))
+		return &CheckResult{
+			MonitorID:    m.ID,
+			CheckedAt:    time.Now(),
+			ResponseTime: duration,
+			ErrorClass:   classifyError(err),
+		}
+	}
+	defer conn.Close()
+
+	result := &CheckResult{
+		MonitorID:    m.ID,
+		CheckedAt:    time.Now(),
+		ResponseTime: duration,
+		IsHealthy:    true,
+	}
+	if m.TCP.Send == "" && m.TCP.Expect == "" {
+		return result
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if m.TCP.Send != "" {
+		if _, err := io.WriteString(conn, m.TCP.Send); err != nil {
+			result.IsHealthy = false
+			result.ErrorClass = classifyError(err)
+			return result
+		}
+	}
+	if m.TCP.Expect != "" {
+		// Read exactly as many bytes as the expected prefix; a short read leaves what arrived
+		got := make([]byte, len(m.TCP.Expect))
+		n, err := io.ReadFull(conn, got)
+		if !bytes.HasPrefix(got[:n], []byte(m.TCP.Expect)) {
+			result.IsHealthy = false
+			result.ErrorClass = ErrorClassAssertion
+			result.FailureReason = fmt.Sprintf("expected response starting with %q, got %q", m.TCP.Expect, got[:n])
+			if n == 0 && err != nil {
+				result.ErrorClass = classifyError(err)
+				result.FailureReason = fmt.Sprintf("expected response starting with %q, got none: %v", m.TCP.Expect, err)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+package monitor
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// startTCPServer accepts connections on a local port and hands each one to serve, returning the
+// host:port to dial
+func startTCPServer(t *testing.T, serve func(conn net.Conn)) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				serve(conn)
+			}()
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func TestCheckTCP(t *testing.T) {
+	banner := func(text string) func(net.Conn) {
+		return func(conn net.Conn) { _, _ = conn.Write([]byte(text)) }
+	}
+	// pong answers a PING line and hangs up on anything else
+	pong := func(conn net.Conn) {
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err == nil && strings.TrimSpace(line) == "PING" {
+			_, _ = conn.Write([]byte("+PONG\r\n"))
+		}
+	}
+	silent := func(conn net.Conn) { time.Sleep(time.Second) }
+
+	tests := []struct {
+		name       string
+		serve      func(net.Conn)
+		check      TCPCheck
+		timeout    time.Duration
+		healthy    bool
+		errorClass string
+	}{
+		{name: "connect only", serve: silent, healthy: true},
+		{name: "banner matches", serve: banner("SSH-2.0-OpenSSH_9.6\r\n"), check: TCPCheck{Expect: "SSH-2.0"}, healthy: true},
+		{name: "banner mismatch", serve: banner("220 mail.example.test ESMTP\r\n"), check: TCPCheck{Expect: "SSH-2.0"}, errorClass: ErrorClassAssertion},
+		{name: "short banner", serve: banner("SSH"), check: TCPCheck{Expect: "SSH-2.0"}, errorClass: ErrorClassAssertion},
+		{name: "send then expect", serve: pong, check: TCPCheck{Send: "PING\r\n", Expect: "+PONG"}, healthy: true},
+		{name: "send with wrong reply", serve: pong, check: TCPCheck{Send: "PING\r\n", Expect: "-ERR"}, errorClass: ErrorClassAssertion},
+		{name: "closed without a reply", serve: pong, check: TCPCheck{Send: "QUIT\r\n", Expect: "+PONG"}, errorClass: ErrorClassConnection},
+		{name: "no reply before the timeout", serve: silent, check: TCPCheck{Expect: "+OK"}, timeout: 100 * time.Millisecond, errorClass: ErrorClassTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := startTCPServer(t, tt.serve)
+			wp := NewWorkerPool(1, nil, zap.NewNop(), nil)
+
+			m := &Monitor{ID: "tcp", Type: TypeTCP, URL: addr, TCP: tt.check, Request: HTTPRequest{Timeout: tt.timeout}}
+			result := wp.checkTCP(context.Background(), m)
+			if result.IsHealthy != tt.healthy || result.ErrorClass != tt.errorClass {
+				t.Fatalf("healthy = %v, error class %q; want %v, %q (reason %q)",
+					result.IsHealthy, result.ErrorClass, tt.healthy, tt.errorClass, result.FailureReason)
+			}
+			if result.ResponseTime <= 0 {
+				t.Errorf("response time = %s, want the connect time", result.ResponseTime)
+			}
+		})
+	}
+}
+
+func TestCheckTCPConnectionRefused(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	wp := NewWorkerPool(1, nil, zap.NewNop(), nil)
+	result := wp.checkTCP(context.Background(), &Monitor{ID: "tcp", Type: TypeTCP, URL: addr})
+	if result.IsHealthy || result.ErrorClass != ErrorClassConnection {
+		t.Errorf("healthy = %v, error class %q; want a connection failure", result.IsHealthy, result.ErrorClass)
+	}
+}
@@ -0,0 +1,158 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultExpiryWarnDays is how long before expiry a certificate marks its monitor degraded
+const defaultExpiryWarnDays = 14
+
+// TLSCheck configures certificate inspection. ExpiryWarnDays of 0 disables the expiry warning;
+// ServerName overrides the name a tls monitor sends via SNI and verifies against the certificate.
+type TLSCheck struct {
+	ExpiryWarnDays int    `json:"expiry_warn_days"`
+	ServerName     string `json:"server_name,omitempty"`
+}
+
+// CertInfo describes the leaf certificate presented by a target and whether its chain verified
+type CertInfo struct {
+	Subject    string    `json:"subject"`
+	Issuer     string    `json:"issuer"`
+	DNSNames   []string  `json:"dns_names,omitempty"`
+	NotBefore  time.Time `json:"not_before"`
+	NotAfter   time.Time `json:"not_after"`
+	ChainValid bool      `json:"chain_valid"`
+	ChainError string    `json:"chain_error,omitempty"`
+}
+
+// DaysRemaining returns the whole days left until the certificate expires, negative once expired
+func (c *CertInfo) DaysRemaining(now time.Time) int {
+	return int(c.NotAfter.Sub(now).Hours() / 24)
+}
+
+// inspectCertificates verifies the presented chain against roots (the system pool when nil)
+// for serverName and summarizes the leaf certificate
+func inspectCertificates(peers []*x509.Certificate, serverName string, roots *x509.CertPool, now time.Time) *CertInfo {
+	if len(peers) == 0 {
+		return nil
+	}
+	leaf := peers[0]
+	info := &CertInfo{
+		Subject:   leaf.Subject.String(),
+		Issuer:    leaf.Issuer.String(),
+		DNSNames:  leaf.DNSNames,
+		NotBefore: leaf.NotBefore,
+		NotAfter:  leaf.NotAfter,
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range peers[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := leaf.Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	})
+	info.ChainValid = err == nil
+	if err != nil {
+		info.ChainError = err.Error()
+	}
+	return info
+}
+
+// judgeCertificate fails a result whose certificate is expired or does not verify, and marks an
+// otherwise healthy result degraded once the certificate is within warnDays of expiry
+func judgeCertificate(result *CheckResult, cert *CertInfo, warnDays int, now time.Time) {
+	result.Certificate = cert
+	if cert == nil {
+		return
+	}
+
+	switch {
+	case now.After(cert.NotAfter):
+		result.IsHealthy = false
+		result.IsDegraded = false
+		result.ErrorClass = ErrorClassTLS
+		result.FailureReason = fmt.Sprintf("certificate expired on %s", cert.NotAfter.Format(time.RFC3339))
+	case !cert.ChainValid:
+		result.IsHealthy = false
+		result.IsDegraded = false
+		result.ErrorClass = ErrorClassTLS
+		result.FailureReason = "certificate chain invalid: " + cert.ChainError
+	case result.IsHealthy && warnDays > 0 && cert.NotAfter.Sub(now) < time.Duration(warnDays)*24*time.Hour:
+		result.IsHealthy = false
+		result.IsDegraded = true
+		result.FailureReason = fmt.Sprintf("certificate expires in %d days on %s", cert.DaysRemaining(now), cert.NotAfter.Format(time.RFC3339))
+	}
+}
+
+// checkTLS performs a TLS handshake with a host:port target and judges the presented
+// certificate. Verification is done after the handshake so that certificate details are
+// recorded even when the chain is invalid.
+func (wp *WorkerPool) checkTLS(ctx context.Context, m *Monitor) *CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, m.Request.timeout())
+	defer cancel()
+
+	host, _, err := net.SplitHostPort(m.URL)
+	if err != nil {
+		return &CheckResult{MonitorID: m.ID, CheckedAt: time.Now(), ErrorClass: ErrorClassInvalidRequest}
+	}
+	serverName := m.TLS.ServerName
+	if serverName == "" {
+		serverName = host
+	}
+
+	dialer := &tls.Dialer{Config: &tls.Config{ServerName: serverName, InsecureSkipVerify: true}}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", m.URL)
+	duration := time.Since(start)
+	now := time.Now()
+
+	if err != nil {
+		wp.logger.Warn("TLS handshake failed", zap.Error(err), zap.String("target", m.URL))
+		return &CheckResult{
+			MonitorID:    m.ID,
+			CheckedAt:    now,
+			ResponseTime: duration,
+			ErrorClass:   classifyError(err),
+		}
+	}
+	defer conn.Close()
+
+	result := &CheckResult{
+		MonitorID:    m.ID,
+		CheckedAt:    now,
+		ResponseTime: duration,
+		IsHealthy:    true,
+	}
+	state := conn.(*tls.Conn).ConnectionState()
+	judgeCertificate(result, inspectCertificates(state.PeerCertificates, serverName, wp.roots, now), m.TLS.ExpiryWarnDays, now)
+	return result
+}
+
+// LoadRootCAs returns the system trust roots extended with the PEM certificates in path
+func LoadRootCAs(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	roots, err := x509.SystemCertPool()
+	if err != nil {
+		roots = x509.NewCertPool()
+	}
+	if !roots.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no PEM certificates found")
+	}
+	return roots, nil
+}
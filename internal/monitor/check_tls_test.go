@@ -0,0 +1,139 @@
+package monitor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// startTLSServer serves HTTPS with a self-signed certificate for 127.0.0.1 valid until notAfter,
+// returning its host:port and a pool trusting the certificate
+func startTLSServer(t *testing.T, notAfter time.Time) (string, *x509.CertPool) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "sentinel test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	// The check hangs up right after the handshake, which the server would log as an error
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	return strings.TrimPrefix(srv.URL, "https://"), roots
+}
+
+func TestCheckTLSExpiryThreshold(t *testing.T) {
+	tests := []struct {
+		name     string
+		validFor time.Duration
+		warnDays int
+		healthy  bool
+		degraded bool
+	}{
+		{name: "outside warning window", validFor: 30 * 24 * time.Hour, warnDays: 14, healthy: true},
+		{name: "inside warning window", validFor: 5 * 24 * time.Hour, warnDays: 14, degraded: true},
+		{name: "warning disabled", validFor: 5 * 24 * time.Hour, warnDays: 0, healthy: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, roots := startTLSServer(t, time.Now().Add(tt.validFor))
+			wp := NewWorkerPool(1, nil, zap.NewNop(), nil)
+			wp.SetRootCAs(roots)
+
+			m := &Monitor{ID: "tls", Type: TypeTLS, URL: addr, TLS: TLSCheck{ExpiryWarnDays: tt.warnDays}}
+			result := wp.checkTLS(context.Background(), m)
+			if result.IsHealthy != tt.healthy || result.IsDegraded != tt.degraded {
+				t.Fatalf("healthy = %v, degraded = %v; want %v, %v (reason %q)",
+					result.IsHealthy, result.IsDegraded, tt.healthy, tt.degraded, result.FailureReason)
+			}
+			if result.Certificate == nil || !result.Certificate.ChainValid {
+				t.Fatalf("certificate = %+v, want a valid chain", result.Certificate)
+			}
+			if days := result.Certificate.DaysRemaining(time.Now()); days != int(tt.validFor.Hours()/24)-1 {
+				t.Errorf("days remaining = %d, want %d", days, int(tt.validFor.Hours()/24)-1)
+			}
+		})
+	}
+}
+
+func TestCheckTLSVerificationFailure(t *testing.T) {
+	addr, roots := startTLSServer(t, time.Now().Add(90*24*time.Hour))
+
+	tests := []struct {
+		name       string
+		roots      *x509.CertPool
+		serverName string
+	}{
+		{name: "untrusted issuer", roots: x509.NewCertPool()},
+		{name: "name mismatch", roots: roots, serverName: "other.example.test"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wp := NewWorkerPool(1, nil, zap.NewNop(), nil)
+			wp.SetRootCAs(tt.roots)
+
+			m := &Monitor{ID: "tls", Type: TypeTLS, URL: addr, TLS: TLSCheck{ExpiryWarnDays: 14, ServerName: tt.serverName}}
+			result := wp.checkTLS(context.Background(), m)
+			if result.IsHealthy || result.IsDegraded {
+				t.Fatalf("healthy = %v, degraded = %v; want down", result.IsHealthy, result.IsDegraded)
+			}
+			if result.ErrorClass != ErrorClassTLS {
+				t.Errorf("error class = %q, want %q", result.ErrorClass, ErrorClassTLS)
+			}
+			if result.Certificate == nil || result.Certificate.ChainValid {
+				t.Errorf("certificate = %+v, want details of an invalid chain", result.Certificate)
+			}
+		})
+	}
+}
+
+func TestJudgeCertificateExpired(t *testing.T) {
+	now := time.Now()
+	result := &CheckResult{IsHealthy: true}
+	judgeCertificate(result, &CertInfo{NotAfter: now.Add(-time.Hour), ChainValid: true}, 14, now)
+	if result.IsHealthy || result.ErrorClass != ErrorClassTLS || !strings.Contains(result.FailureReason, "expired") {
+		t.Fatalf("result = %+v, want an expired certificate failure", result)
+	}
+}
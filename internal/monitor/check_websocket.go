@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// WebSocketCheck configures a websocket monitor. Send is written as a text message once the
+// upgrade completes; Expect, when set, is a pattern one of the following messages must match
+// before the monitor's timeout. Without Send, Expect waits for a message the server pushes.
+type WebSocketCheck struct {
+	Send   string `json:"send,omitempty"`
+	Expect string `json:"expect,omitempty"`
+}
+
+// checkWebSocket completes the upgrade handshake with a ws(s) target, sending the monitor's
+// request headers, and optionally exchanges a message. The recorded response time is the
+// handshake time; the exchange is recorded separately as the round-trip time.
+func (wp *WorkerPool) checkWebSocket(ctx context.Context, m *Monitor) *CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, m.Request.timeout())
+	defer cancel()
+
+	header := http.Header{}
+	for k, v := range m.Request.Headers {
+		header.Set(k, v)
+	}
+	dialer := &websocket.Dialer{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{RootCAs: wp.roots},
+	}
+
+	start := time.Now()
+	conn, res, err := dialer.DialContext(ctx, m.URL, header)
+	duration := time.Since(start)
+
+	result := &CheckResult{
+		MonitorID:    m.ID,
+		CheckedAt:    time.Now(),
+		ResponseTime: duration,
+	}
+	if res != nil {
+		result.StatusCode = res.StatusCode
+	}
+	if err != nil {
+		wp.logger.Warn("WebSocket handshake failed", zap.Error(err), zap.String("url", m.URL))
+		if errors.Is(err, websocket.ErrBadHandshake) && res != nil {
+			result.ErrorClass = ErrorClassHTTPStatus
+			result.FailureReason = fmt.Sprintf("upgrade rejected with HTTP %d", res.StatusCode)
+			return result
+		}
+		result.ErrorClass = classifyError(err)
+		return result
+	}
+	defer conn.Close()
+
+	result.IsHealthy = true
+	if tlsConn, ok := conn.NetConn().(*tls.Conn); ok {
+		now := time.Now()
+		state := tlsConn.ConnectionState()
+		judgeCertificate(result, inspectCertificates(state.PeerCertificates, state.ServerName, wp.roots, now), m.TLS.ExpiryWarnDays, now)
+	}
+	if m.WebSocket.Send == "" && m.WebSocket.Expect == "" {
+		return result
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetWriteDeadline(deadline)
+		_ = conn.SetReadDeadline(deadline)
+	}
+	start = time.Now()
+	if m.WebSocket.Send != "" {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(m.WebSocket.Send)); err != nil {
+			result.IsHealthy = false
+			result.IsDegraded = false
+			result.ErrorClass = classifyError(err)
+			result.FailureReason = "sending message: " + err.Error()
+			return result
+		}
+	}
+	if m.WebSocket.Expect == "" {
+		return result
+	}
+
+	// Messages that do not match, such as unrelated broadcasts, are skipped until the deadline
+	pattern := regexp.MustCompile(m.WebSocket.Expect)
+	var last []byte
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			result.IsHealthy = false
+			result.IsDegraded = false
+			result.ErrorClass = classifyError(err)
+			result.FailureReason = fmt.Sprintf("no message matching %q: %v", m.WebSocket.Expect, err)
+			if last != nil {
+				result.ErrorClass = ErrorClassAssertion
+				result.FailureReason = fmt.Sprintf("no message matching %q, last received %q", m.WebSocket.Expect, last[:min(len(last), 200)])
+			}
+			return result
+		}
+		if pattern.Match(msg) {
+			result.RoundTripTime = time.Since(start)
+			return result
+		}
+		last = msg
+	}
+}
@@ -0,0 +1,129 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// replyDelay is how long the test server takes to answer a message, so the round trip can be
+// told apart from the handshake
+const replyDelay = 100 * time.Millisecond
+
+// startWebSocketServer upgrades requests bearing the test token and hands each connection to
+// serve, returning the ws:// URL to dial
+func startWebSocketServer(t *testing.T, serve func(conn *websocket.Conn)) string {
+	t.Helper()
+
+	var upgrader websocket.Upgrader
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		serve(conn)
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func TestCheckWebSocket(t *testing.T) {
+	// echo answers every message with its upper-cased text after replyDelay
+	echo := func(conn *websocket.Conn) {
+		for {
+			_, msg, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			time.Sleep(replyDelay)
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(strings.ToUpper(string(msg)))); err != nil {
+				return
+			}
+		}
+	}
+	// ticker pushes unrelated messages until the client hangs up
+	ticker := func(conn *websocket.Conn) {
+		for {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"tick"}`)); err != nil {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		serve      func(*websocket.Conn)
+		token      string
+		check      WebSocketCheck
+		healthy    bool
+		errorClass string
+		status     int
+		roundTrip  bool
+	}{
+		{name: "handshake rejected", serve: echo, token: "wrong", errorClass: ErrorClassHTTPStatus, status: http.StatusForbidden},
+		{name: "handshake only", serve: echo, token: "test", healthy: true, status: http.StatusSwitchingProtocols},
+		{name: "matching reply", serve: echo, token: "test", check: WebSocketCheck{Send: "ping", Expect: "^PING$"},
+			healthy: true, status: http.StatusSwitchingProtocols, roundTrip: true},
+		{name: "pushed message", serve: ticker, token: "test", check: WebSocketCheck{Expect: `"tick"`},
+			healthy: true, status: http.StatusSwitchingProtocols, roundTrip: true},
+		{name: "no matching reply before the timeout", serve: ticker, token: "test", check: WebSocketCheck{Send: "subscribe", Expect: `"snapshot"`},
+			errorClass: ErrorClassAssertion, status: http.StatusSwitchingProtocols},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := startWebSocketServer(t, tt.serve)
+			wp := NewWorkerPool(1, nil, zap.NewNop(), nil)
+
+			m := &Monitor{ID: "ws", Type: TypeWebSocket, URL: url, WebSocket: tt.check, Request: HTTPRequest{
+				Timeout: 300 * time.Millisecond,
+				Headers: map[string]string{"Authorization": "Bearer " + tt.token},
+			}}
+			result := wp.checkWebSocket(context.Background(), m)
+			if result.IsHealthy != tt.healthy || result.ErrorClass != tt.errorClass || result.StatusCode != tt.status {
+				t.Fatalf("healthy = %v, error class %q, status %d; want %v, %q, %d (reason %q)",
+					result.IsHealthy, result.ErrorClass, result.StatusCode, tt.healthy, tt.errorClass, tt.status, result.FailureReason)
+			}
+			if tt.roundTrip != (result.RoundTripTime > 0) {
+				t.Errorf("round-trip time = %s, want one recorded: %v", result.RoundTripTime, tt.roundTrip)
+			}
+		})
+	}
+}
+
+func TestCheckWebSocketTimesHandshakeAndRoundTripSeparately(t *testing.T) {
+	url := startWebSocketServer(t, func(conn *websocket.Conn) {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+		time.Sleep(replyDelay)
+		_ = conn.WriteMessage(websocket.TextMessage, []byte("pong"))
+	})
+	wp := NewWorkerPool(1, nil, zap.NewNop(), nil)
+
+	m := &Monitor{ID: "ws", Type: TypeWebSocket, URL: url, WebSocket: WebSocketCheck{Send: "ping", Expect: "pong"}, Request: HTTPRequest{
+		Headers: map[string]string{"Authorization": "Bearer test"},
+	}}
+	result := wp.checkWebSocket(context.Background(), m)
+	if !result.IsHealthy {
+		t.Fatalf("check failed: %s", result.FailureReason)
+	}
+	if result.RoundTripTime < replyDelay {
+		t.Errorf("round-trip time = %s, want at least the %s reply delay", result.RoundTripTime, replyDelay)
+	}
+	if result.ResponseTime >= replyDelay {
+		t.Errorf("response time = %s, want the handshake alone, not the %s reply delay", result.ResponseTime, replyDelay)
+	}
+}
@@ -2,16 +2,178 @@ package monitor
 
 import "time"
 
+// Health is the confirmed state of a monitor after applying failure/recovery thresholds
+type Health string
+
+const (
+	HealthPending  Health = "pending"
+	HealthUp       Health = "up"
+	HealthDegraded Health = "degraded"
+	HealthDown     Health = "down"
+)
+
+// Available reports whether the target is serving, fully or degraded
+func (h Health) Available() bool {
+	return h == HealthUp || h == HealthDegraded
+}
+
 // Monitor represents a health check target
 type Monitor struct {
-	ID            string        `json:"id"`
-	UserID        string        `json:"user_id"`
-	URL           string        `json:"url"`
-	Interval      time.Duration `json:"interval"`
-	LastChecked   time.Time     `json:"last_checked"`
+	ID            string         `json:"id"`
+	UserID        string         `json:"user_id"`
+	Type          MonitorType    `json:"type"`
+	URL           string         `json:"url"`
+	Interval      time.Duration  `json:"interval"`
+	LastChecked   time.Time      `json:"last_checked"`
+	StatusCode    int            `json:"status_code"`
+	ResponseTime  time.Duration  `json:"response_time"`
+	IsHealthy     bool           `json:"is_healthy"`
+	IsPaused      bool           `json:"is_paused"`
+	AIExplanation string         `json:"ai_explanation,omitempty"`
+	Tags          []string       `json:"tags"`
+	Request       HTTPRequest    `json:"request"`
+	Assertions    []Assertion    `json:"assertions"`
+	TLS           TLSCheck       `json:"tls"`
+	TCP           TCPCheck       `json:"tcp"`
+	DNS           DNSCheck       `json:"dns"`
+	Heartbeat     HeartbeatCheck `json:"heartbeat"`
+	Steps         []Step         `json:"steps"`
+	GRPC          GRPCCheck      `json:"grpc"`
+	WebSocket     WebSocketCheck `json:"websocket"`
+	PingToken     string         `json:"-"`
+
+	// Locations the check runs from through probe agents instead of locally; the monitor is down
+	// only when at least Quorum of them fail, a majority when Quorum is zero
+	Locations []string `json:"locations,omitempty"`
+	Quorum    int      `json:"quorum,omitempty"`
+
+	// Confirmation settings: consecutive raw results required before the confirmed health flips,
+	// and immediate retries (with exponential backoff) attempted within a single job
+	FailureThreshold  int           `json:"failure_threshold"`
+	RecoveryThreshold int           `json:"recovery_threshold"`
+	RetryCount        int           `json:"retry_count"`
+	RetryBackoff      time.Duration `json:"retry_backoff"`
+
+	Health               Health `json:"health"`
+	HealthReason         string `json:"health_reason,omitempty"`
+	ConsecutiveFailures  int    `json:"consecutive_failures"`
+	ConsecutiveSuccesses int    `json:"consecutive_successes"`
+	IsFlapping           bool   `json:"is_flapping"`
+
+	// NextRunAt is when the scheduler next claims the monitor; zero leaves it unscheduled, as for
+	// a heartbeat that has not received its first ping
+	NextRunAt time.Time `json:"next_run_at"`
+	// Lease held by the node that claimed the monitor for a check; once expired any node may
+	// reclaim it
+	LeaseOwner     string    `json:"lease_owner,omitempty"`
+	LeaseExpiresAt time.Time `json:"lease_expires_at"`
+
+	// Answers of the last successful DNS resolution, used to detect changed answers
+	Answers []string `json:"answers,omitempty"`
+	// Timing of the last HTTP or transaction check
+	Timing *Timing `json:"timing,omitempty"`
+	// StartedAt is when a heartbeat job last reported its start
+	StartedAt time.Time `json:"started_at"`
+}
+
+// StatusUpdate carries the state written back to a monitor after a check
+type StatusUpdate struct {
+	LastChecked          time.Time
+	StatusCode           int
+	ResponseTime         time.Duration
+	Health               Health
+	HealthReason         string
+	ConsecutiveFailures  int
+	ConsecutiveSuccesses int
+	IsFlapping           bool
+	AIExplanation        string
+	Answers              []string
+	Timing               *Timing
+	NextRunAt            time.Time
+}
+
+// Error classes describing why a check failed, recorded alongside each result
+const (
+	ErrorClassTimeout        = "timeout"
+	ErrorClassDNS            = "dns"
+	ErrorClassConnection     = "connection"
+	ErrorClassTLS            = "tls"
+	ErrorClassInvalidRequest = "invalid_request"
+	ErrorClassAssertion      = "assertion"
+	ErrorClassHTTPStatus     = "http_status"
+	ErrorClassMissedPing     = "missed_ping"
+	ErrorClassJobFailed      = "job_failed"
+	ErrorClassGRPCStatus     = "grpc_status"
+	ErrorClassNotServing     = "not_serving"
+	ErrorClassNoAgents       = "no_agents"
+)
+
+// CheckResult records the outcome of a single executed health check
+type CheckResult struct {
+	ID            int64         `json:"id"`
+	MonitorID     string        `json:"monitor_id"`
+	CheckedAt     time.Time     `json:"checked_at"`
 	StatusCode    int           `json:"status_code"`
 	ResponseTime  time.Duration `json:"response_time"`
 	IsHealthy     bool          `json:"is_healthy"`
-	IsRunning     bool          `json:"is_running"`
+	IsDegraded    bool          `json:"is_degraded"`
+	Attempts      int           `json:"attempts"`
+	InMaintenance bool          `json:"in_maintenance"`
+	ErrorClass    string        `json:"error_class,omitempty"`
+	FailureReason string        `json:"failure_reason,omitempty"`
 	AIExplanation string        `json:"ai_explanation,omitempty"`
+	Certificate   *CertInfo     `json:"certificate,omitempty"`
+
+	// DNS answers, sorted, and whether they differ from the previous successful resolution
+	Answers        []string `json:"answers,omitempty"`
+	AnswersChanged bool     `json:"answers_changed"`
+
+	// Exit code and output reported by a heartbeat job; output is kept for failures only
+	ExitCode *int   `json:"exit_code,omitempty"`
+	Output   string `json:"output,omitempty"`
+
+	// Steps of a transaction check, up to and including the first that failed
+	Steps []StepResult `json:"steps,omitempty"`
+
+	// Status code of a grpc health RPC, e.g. OK or Unavailable
+	GRPCCode string `json:"grpc_code,omitempty"`
+
+	// Time from sending a websocket message to receiving the expected reply; ResponseTime is the
+	// handshake time
+	RoundTripTime time.Duration `json:"round_trip_time,omitempty"`
+
+	// Phases of an HTTP or transaction check
+	Timing *Timing `json:"timing,omitempty"`
+
+	// Per-location outcomes of a check run by probe agents; the other fields are those of a
+	// representative location
+	Locations []LocationResult `json:"locations,omitempty"`
+}
+
+// ResultQuery filters and paginates check history; zero From/To leave that bound open
+type ResultQuery struct {
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
+}
+
+// HasTag reports whether the monitor carries the given tag
+func (m *Monitor) HasTag(tag string) bool {
+	for _, t := range m.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (q ResultQuery) matches(r *CheckResult) bool {
+	if !q.From.IsZero() && r.CheckedAt.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && r.CheckedAt.After(q.To) {
+		return false
+	}
+	return true
 }
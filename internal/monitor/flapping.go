@@ -0,0 +1,94 @@
+package monitor
+
+import (
+	"sync"
+	"time"
+)
+
+// FlapChange marks a check that started or stopped a flapping period
+type FlapChange string
+
+const (
+	FlapNone    FlapChange = ""
+	FlapStarted FlapChange = "started"
+	FlapStopped FlapChange = "stopped"
+)
+
+// Default flapping policy: five confirmed transitions within half an hour
+const (
+	defaultFlapWindow    = 30 * time.Minute
+	defaultFlapThreshold = 5
+)
+
+// flapDetector tracks confirmed state transitions per monitor over a sliding window
+type flapDetector struct {
+	mu        sync.Mutex
+	window    time.Duration
+	threshold int
+	history   map[string][]time.Time
+	baseline  map[string]Health
+}
+
+func newFlapDetector(window time.Duration, threshold int) *flapDetector {
+	if window <= 0 {
+		window = defaultFlapWindow
+	}
+	if threshold < 2 {
+		threshold = defaultFlapThreshold
+	}
+	return &flapDetector{
+		window:    window,
+		threshold: threshold,
+		history:   make(map[string][]time.Time),
+		baseline:  make(map[string]Health),
+	}
+}
+
+// observe records a check at the given time and returns how many transitions fall inside the window
+func (d *flapDetector) observe(id string, at time.Time, transition bool) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	history := d.history[id]
+	if transition {
+		history = append(history, at)
+	}
+
+	cutoff := at.Add(-d.window)
+	kept := history[:0]
+	for _, t := range history {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+
+	if len(kept) == 0 {
+		delete(d.history, id)
+	} else {
+		d.history[id] = kept
+	}
+	return len(kept)
+}
+
+// update decides whether the monitor enters or leaves the flapping state. Leaving requires the
+// transition count to fall to half the threshold so a monitor on the boundary does not oscillate.
+// previous is the confirmed health before this check; the health held when flapping began is
+// returned on FlapStopped so observers can reconcile against it.
+func (d *flapDetector) update(m *Monitor, count int, previous Health) (FlapChange, Health) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	switch {
+	case !m.IsFlapping && count >= d.threshold:
+		d.baseline[m.ID] = previous
+		return FlapStarted, previous
+	case m.IsFlapping && count <= d.threshold/2:
+		baseline, ok := d.baseline[m.ID]
+		delete(d.baseline, m.ID)
+		if !ok {
+			baseline = HealthPending
+		}
+		return FlapStopped, baseline
+	}
+	return FlapNone, previous
+}
@@ -0,0 +1,88 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFlapDetectorObserveWindow(t *testing.T) {
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		checks []time.Duration // offsets of transitions from start
+		at     time.Duration   // offset of the final check, which is not a transition
+		want   int
+	}{
+		{name: "no transitions", at: time.Minute, want: 0},
+		{name: "all inside the window", checks: []time.Duration{0, time.Minute, 2 * time.Minute}, at: 3 * time.Minute, want: 3},
+		{name: "old transitions slide out", checks: []time.Duration{0, time.Minute, 20 * time.Minute}, at: 31 * time.Minute, want: 1},
+		{name: "transition on the window edge has expired", checks: []time.Duration{0}, at: 30 * time.Minute, want: 0},
+		{name: "everything expired", checks: []time.Duration{0, time.Minute}, at: 2 * time.Hour, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newFlapDetector(30*time.Minute, 5)
+			for _, offset := range tt.checks {
+				d.observe("m", start.Add(offset), true)
+			}
+			if got := d.observe("m", start.Add(tt.at), false); got != tt.want {
+				t.Errorf("transitions in window = %d, want %d", got, tt.want)
+			}
+			if _, kept := d.history["m"]; kept != (tt.want > 0) {
+				t.Errorf("history kept = %v with %d transitions in the window", kept, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlapDetectorUpdate(t *testing.T) {
+	tests := []struct {
+		name     string
+		flapping bool
+		count    int
+		change   FlapChange
+	}{
+		{name: "below the threshold", count: 4, change: FlapNone},
+		{name: "reaching the threshold starts flapping", count: 5, change: FlapStarted},
+		{name: "still above half the threshold keeps flapping", flapping: true, count: 3, change: FlapNone},
+		{name: "half the threshold stops flapping", flapping: true, count: 2, change: FlapStopped},
+		{name: "already flapping above the threshold", flapping: true, count: 6, change: FlapNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newFlapDetector(30*time.Minute, 5)
+			m := &Monitor{ID: "m", IsFlapping: tt.flapping}
+			if change, _ := d.update(m, tt.count, HealthUp); change != tt.change {
+				t.Errorf("change = %q, want %q", change, tt.change)
+			}
+		})
+	}
+}
+
+func TestFlapDetectorRestoresBaseline(t *testing.T) {
+	d := newFlapDetector(30*time.Minute, 5)
+	m := &Monitor{ID: "m"}
+
+	if change, baseline := d.update(m, 5, HealthUp); change != FlapStarted || baseline != HealthUp {
+		t.Fatalf("start: change %q, baseline %s; want started from up", change, baseline)
+	}
+	m.IsFlapping = true
+	if change, baseline := d.update(m, 2, HealthDown); change != FlapStopped || baseline != HealthUp {
+		t.Fatalf("stop: change %q, baseline %s; want stopped reporting up", change, baseline)
+	}
+
+	// A detector that did not see flapping begin, e.g. after a restart, reports pending
+	if change, baseline := newFlapDetector(0, 0).update(m, 0, HealthDown); change != FlapStopped || baseline != HealthPending {
+		t.Errorf("stop without a baseline: change %q, baseline %s; want stopped reporting pending", change, baseline)
+	}
+}
+
+func TestNewFlapDetectorDefaults(t *testing.T) {
+	d := newFlapDetector(0, 1)
+	if d.window != defaultFlapWindow || d.threshold != defaultFlapThreshold {
+		t.Errorf("window %s, threshold %d; want the defaults", d.window, d.threshold)
+	}
+}
@@ -1,7 +1,11 @@
 package monitor
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -9,33 +13,380 @@ import (
 
 // MonitorResponse is the DTO used to shape the API response
 type MonitorResponse struct {
-	ID            string    `json:"id"`
-	UserID        string    `json:"user_id"`
-	URL           string    `json:"url"`
-	Interval      int64     `json:"interval"`
-	LastChecked   time.Time `json:"last_checked"`
-	StatusCode    int       `json:"status_code"`
-	ResponseTime  int64     `json:"response_time"`
-	IsHealthy     bool      `json:"is_healthy"`
-	IsRunning     bool      `json:"is_running"`
-	AIExplanation string    `json:"ai_explanation,omitempty"`
+	ID            string          `json:"id"`
+	UserID        string          `json:"user_id"`
+	Type          MonitorType     `json:"type"`
+	URL           string          `json:"url"`
+	Interval      int64           `json:"interval"`
+	LastChecked   time.Time       `json:"last_checked"`
+	NextRunAt     *time.Time      `json:"next_run_at"`
+	StatusCode    int             `json:"status_code"`
+	ResponseTime  int64           `json:"response_time"`
+	IsHealthy     bool            `json:"is_healthy"`
+	IsRunning     bool            `json:"is_running"`
+	IsPaused      bool            `json:"is_paused"`
+	AIExplanation string          `json:"ai_explanation,omitempty"`
+	Tags          []string        `json:"tags"`
+	Request       RequestResponse `json:"request"`
+	Assertions    []Assertion     `json:"assertions"`
+	TLS           TLSCheck        `json:"tls"`
+	TCP           TCPCheck        `json:"tcp"`
+	DNS           DNSCheck        `json:"dns"`
+	GRPC          GRPCCheck       `json:"grpc"`
+	WebSocket     WebSocketCheck  `json:"websocket"`
+	Answers       []string        `json:"answers,omitempty"`
+	Timing        *TimingResponse `json:"timing,omitempty"`
+
+	Heartbeat *HeartbeatResponse `json:"heartbeat,omitempty"`
+	Steps     []StepResponse     `json:"steps,omitempty"`
+	Locations []string           `json:"locations,omitempty"`
+	Quorum    int                `json:"quorum,omitempty"` // effective quorum, a majority unless set
+
+	Health               Health `json:"health"`
+	HealthReason         string `json:"health_reason,omitempty"`
+	ConsecutiveFailures  int    `json:"consecutive_failures"`
+	ConsecutiveSuccesses int    `json:"consecutive_successes"`
+	IsFlapping           bool   `json:"is_flapping"`
+	FailureThreshold     int    `json:"failure_threshold"`
+	RecoveryThreshold    int    `json:"recovery_threshold"`
+	RetryCount           int    `json:"retry_count"`
+	RetryBackoff         int64  `json:"retry_backoff"`
+}
+
+// StepResponse is the DTO used to shape a transaction step, masking its request like the
+// monitor's own
+type StepResponse struct {
+	Name       string          `json:"name,omitempty"`
+	URL        string          `json:"url"`
+	Request    RequestResponse `json:"request"`
+	Assertions []Assertion     `json:"assertions,omitempty"`
+	Captures   []Capture       `json:"captures,omitempty"`
+}
+
+// HeartbeatResponse is the DTO used to shape heartbeat settings, with the grace in seconds and
+// the ping URL relative to the API host
+type HeartbeatResponse struct {
+	Grace     int64     `json:"grace"`
+	PingURL   string    `json:"ping_url"`
+	StartedAt time.Time `json:"started_at"`
 }
 
 func mapToResponse(m *Monitor) MonitorResponse {
+	var nextRunAt *time.Time
+	if !m.NextRunAt.IsZero() {
+		nextRunAt = &m.NextRunAt
+	}
+	var heartbeat *HeartbeatResponse
+	if m.Type == TypeHeartbeat {
+		heartbeat = &HeartbeatResponse{
+			Grace:     int64(m.Heartbeat.grace().Seconds()),
+			PingURL:   "/api/v1/ping/" + m.PingToken,
+			StartedAt: m.StartedAt,
+		}
+	}
+
+	var quorum int
+	if len(m.Locations) > 0 {
+		quorum = m.quorum()
+	}
+
+	var steps []StepResponse
+	for _, s := range m.Steps {
+		steps = append(steps, StepResponse{
+			Name:       s.Name,
+			URL:        s.URL,
+			Request:    mapToRequestResponse(s.Request),
+			Assertions: s.Assertions,
+			Captures:   s.Captures,
+		})
+	}
+
 	return MonitorResponse{
 		ID:            m.ID,
 		UserID:        m.UserID,
+		Type:          m.Type,
 		URL:           m.URL,
 		Interval:      int64(m.Interval),
 		LastChecked:   m.LastChecked,
+		NextRunAt:     nextRunAt,
 		StatusCode:    m.StatusCode,
 		ResponseTime:  m.ResponseTime.Milliseconds(),
 		IsHealthy:     m.IsHealthy,
-		IsRunning:     m.IsRunning,
+		IsRunning:     m.Leased(time.Now()),
+		IsPaused:      m.IsPaused,
 		AIExplanation: m.AIExplanation,
+		Tags:          m.Tags,
+		Request:       mapToRequestResponse(m.Request),
+		Assertions:    m.Assertions,
+		TLS:           m.TLS,
+		TCP:           m.TCP,
+		DNS:           m.DNS,
+		GRPC:          m.GRPC,
+		WebSocket:     m.WebSocket,
+		Answers:       m.Answers,
+		Timing:        mapToTimingResponse(m.Timing),
+
+		Heartbeat: heartbeat,
+		Steps:     steps,
+		Locations: m.Locations,
+		Quorum:    quorum,
+
+		Health:               m.Health,
+		HealthReason:         m.HealthReason,
+		ConsecutiveFailures:  m.ConsecutiveFailures,
+		ConsecutiveSuccesses: m.ConsecutiveSuccesses,
+		IsFlapping:           m.IsFlapping,
+		FailureThreshold:     m.FailureThreshold,
+		RecoveryThreshold:    m.RecoveryThreshold,
+		RetryCount:           m.RetryCount,
+		RetryBackoff:         m.RetryBackoff.Milliseconds(),
+	}
+}
+
+// RequestResponse is the DTO used to shape a check's request spec, with the timeout in milliseconds
+// and the body and credential-bearing header values masked
+type RequestResponse struct {
+	Method          string            `json:"method"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	Body            string            `json:"body,omitempty"`
+	Timeout         int64             `json:"timeout"`
+	FollowRedirects bool              `json:"follow_redirects"`
+	ExpectedStatus  string            `json:"expected_status"`
+}
+
+func mapToRequestResponse(r HTTPRequest) RequestResponse {
+	res := RequestResponse{
+		Method:          r.method(),
+		Timeout:         r.timeout().Milliseconds(),
+		FollowRedirects: r.FollowRedirects,
+		ExpectedStatus:  r.ExpectedStatus,
+	}
+	if res.ExpectedStatus == "" {
+		res.ExpectedStatus = defaultExpectedStatus
 	}
+	if r.Body != "" {
+		res.Body = maskedValue
+	}
+	if len(r.Headers) > 0 {
+		res.Headers = make(map[string]string, len(r.Headers))
+		for k, v := range r.Headers {
+			if sensitiveHeader(k) {
+				v = maskedValue
+			}
+			res.Headers[k] = v
+		}
+	}
+	return res
 }
 
+// maskedValue stands in for secrets in responses; sent back in an update, it keeps the stored value
+const maskedValue = "********"
+
+// sensitiveHeader reports whether a header typically carries credentials
+func sensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	switch name {
+	case "authorization", "proxy-authorization", "cookie":
+		return true
+	}
+	return strings.Contains(name, "token") || strings.Contains(name, "secret") || strings.Contains(name, "key")
+}
+
+// ResultResponse is the DTO used to shape a single check history entry
+type ResultResponse struct {
+	ID            int64     `json:"id"`
+	CheckedAt     time.Time `json:"checked_at"`
+	StatusCode    int       `json:"status_code"`
+	ResponseTime  int64     `json:"response_time"`
+	IsHealthy     bool      `json:"is_healthy"`
+	IsDegraded    bool      `json:"is_degraded"`
+	Attempts      int       `json:"attempts"`
+	InMaintenance bool      `json:"in_maintenance"`
+	ErrorClass    string    `json:"error_class,omitempty"`
+	FailureReason string    `json:"failure_reason,omitempty"`
+	AIExplanation string    `json:"ai_explanation,omitempty"`
+
+	Certificate    *CertificateResponse `json:"certificate,omitempty"`
+	Answers        []string             `json:"answers,omitempty"`
+	AnswersChanged bool                 `json:"answers_changed"`
+	ExitCode       *int                 `json:"exit_code,omitempty"`
+	Output         string               `json:"output,omitempty"`
+	Steps          []StepResultResponse `json:"steps,omitempty"`
+	GRPCCode       string               `json:"grpc_code,omitempty"`
+	RoundTripTime  int64                `json:"round_trip_time,omitempty"` // in milliseconds
+	Timing         *TimingResponse      `json:"timing,omitempty"`
+
+	Locations []LocationResultResponse `json:"locations,omitempty"`
+}
+
+// LocationResultResponse is the DTO used to shape the outcome of a check at one location, with
+// the response time in milliseconds
+type LocationResultResponse struct {
+	Location      string `json:"location"`
+	AgentID       string `json:"agent_id,omitempty"`
+	Outcome       Health `json:"outcome"`
+	StatusCode    int    `json:"status_code"`
+	ResponseTime  int64  `json:"response_time"`
+	ErrorClass    string `json:"error_class,omitempty"`
+	FailureReason string `json:"failure_reason,omitempty"`
+}
+
+// TimingResponse is the DTO used to shape the phases of an HTTP check, in milliseconds
+type TimingResponse struct {
+	DNS       int64 `json:"dns"`
+	Connect   int64 `json:"connect"`
+	TLS       int64 `json:"tls"`
+	FirstByte int64 `json:"first_byte"`
+	Transfer  int64 `json:"transfer"`
+}
+
+func mapToTimingResponse(t *Timing) *TimingResponse {
+	if t == nil {
+		return nil
+	}
+	return &TimingResponse{
+		DNS:       t.DNS.Milliseconds(),
+		Connect:   t.Connect.Milliseconds(),
+		TLS:       t.TLS.Milliseconds(),
+		FirstByte: t.FirstByte.Milliseconds(),
+		Transfer:  t.Transfer.Milliseconds(),
+	}
+}
+
+// StepResultResponse is the DTO used to shape the outcome of a transaction step, with the
+// response time in milliseconds
+type StepResultResponse struct {
+	Name          string `json:"name,omitempty"`
+	URL           string `json:"url"`
+	StatusCode    int    `json:"status_code"`
+	ResponseTime  int64  `json:"response_time"`
+	IsHealthy     bool   `json:"is_healthy"`
+	IsDegraded    bool   `json:"is_degraded"`
+	ErrorClass    string `json:"error_class,omitempty"`
+	FailureReason string `json:"failure_reason,omitempty"`
+}
+
+// CertificateResponse is the DTO used to shape an inspected certificate
+type CertificateResponse struct {
+	Subject       string    `json:"subject"`
+	Issuer        string    `json:"issuer"`
+	DNSNames      []string  `json:"dns_names,omitempty"`
+	NotBefore     time.Time `json:"not_before"`
+	NotAfter      time.Time `json:"not_after"`
+	DaysRemaining int       `json:"days_remaining"`
+	ChainValid    bool      `json:"chain_valid"`
+	ChainError    string    `json:"chain_error,omitempty"`
+}
+
+func mapToResultResponse(r *CheckResult) ResultResponse {
+	var cert *CertificateResponse
+	if c := r.Certificate; c != nil {
+		cert = &CertificateResponse{
+			Subject:       c.Subject,
+			Issuer:        c.Issuer,
+			DNSNames:      c.DNSNames,
+			NotBefore:     c.NotBefore,
+			NotAfter:      c.NotAfter,
+			DaysRemaining: c.DaysRemaining(r.CheckedAt),
+			ChainValid:    c.ChainValid,
+			ChainError:    c.ChainError,
+		}
+	}
+
+	var steps []StepResultResponse
+	for _, s := range r.Steps {
+		steps = append(steps, StepResultResponse{
+			Name:          s.Name,
+			URL:           s.URL,
+			StatusCode:    s.StatusCode,
+			ResponseTime:  s.ResponseTime.Milliseconds(),
+			IsHealthy:     s.IsHealthy,
+			IsDegraded:    s.IsDegraded,
+			ErrorClass:    s.ErrorClass,
+			FailureReason: s.FailureReason,
+		})
+	}
+
+	var locations []LocationResultResponse
+	for _, l := range r.Locations {
+		locations = append(locations, LocationResultResponse{
+			Location:      l.Location,
+			AgentID:       l.AgentID,
+			Outcome:       l.Outcome,
+			StatusCode:    l.StatusCode,
+			ResponseTime:  l.ResponseTime.Milliseconds(),
+			ErrorClass:    l.ErrorClass,
+			FailureReason: l.FailureReason,
+		})
+	}
+
+	return ResultResponse{
+		ID:            r.ID,
+		CheckedAt:     r.CheckedAt,
+		StatusCode:    r.StatusCode,
+		ResponseTime:  r.ResponseTime.Milliseconds(),
+		IsHealthy:     r.IsHealthy,
+		IsDegraded:    r.IsDegraded,
+		Attempts:      r.Attempts,
+		InMaintenance: r.InMaintenance,
+		ErrorClass:    r.ErrorClass,
+		FailureReason: r.FailureReason,
+		AIExplanation: r.AIExplanation,
+		Certificate:   cert,
+
+		Answers:        r.Answers,
+		AnswersChanged: r.AnswersChanged,
+		ExitCode:       r.ExitCode,
+		Output:         r.Output,
+		Steps:          steps,
+		GRPCCode:       r.GRPCCode,
+		RoundTripTime:  r.RoundTripTime.Milliseconds(),
+		Timing:         mapToTimingResponse(r.Timing),
+
+		Locations: locations,
+	}
+}
+
+// StatsResponse is the DTO used to shape SLA figures, with durations in milliseconds
+type StatsResponse struct {
+	Window          string    `json:"window"`
+	From            time.Time `json:"from"`
+	To              time.Time `json:"to"`
+	TotalChecks     int       `json:"total_checks"`
+	HealthyChecks   int       `json:"healthy_checks"`
+	DegradedChecks  int       `json:"degraded_checks"`
+	UptimePercent   float64   `json:"uptime_percent"`
+	DegradedPercent float64   `json:"degraded_percent"`
+	P50             int64     `json:"p50_response_time"`
+	P95             int64     `json:"p95_response_time"`
+	P99             int64     `json:"p99_response_time"`
+	MTTR            int64     `json:"mttr"`
+	IncidentCount   int       `json:"incident_count"`
+}
+
+func mapToStatsResponse(s *Stats) StatsResponse {
+	return StatsResponse{
+		Window:          s.Window,
+		From:            s.From,
+		To:              s.To,
+		TotalChecks:     s.TotalChecks,
+		HealthyChecks:   s.HealthyChecks,
+		DegradedChecks:  s.DegradedChecks,
+		UptimePercent:   s.UptimePercent,
+		DegradedPercent: s.DegradedPercent,
+		P50:             s.P50.Milliseconds(),
+		P95:             s.P95.Milliseconds(),
+		P99:             s.P99.Milliseconds(),
+		MTTR:            s.MTTR.Milliseconds(),
+		IncidentCount:   s.IncidentCount,
+	}
+}
+
+const (
+	defaultResultsLimit = 50
+	maxResultsLimit     = 500
+)
+
 // Handler processes HTTP monitoring actions
 type Handler struct {
 	svc Service
@@ -62,7 +413,7 @@ func (h *Handler) Add(c *gin.Context) {
 
 	m, err := h.svc.Add(c.Request.Context(), userID.(string), req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "failed to add monitor", "data": nil})
+		respondError(c, err, "failed to add monitor")
 		return
 	}
 
@@ -98,3 +449,259 @@ func (h *Handler) List(c *gin.Context) {
 		"data":    responseData,
 	})
 }
+
+// Get handles fetching a single monitor owned by the caller
+func (h *Handler) Get(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	m, err := h.svc.Get(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to get monitor")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "monitor retrieved",
+		"data":    mapToResponse(m),
+	})
+}
+
+// Update handles PATCH payloads changing the URL or interval of a monitor
+func (h *Handler) Update(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	var req UpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid request data", "data": nil})
+		return
+	}
+
+	m, err := h.svc.Update(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		respondError(c, err, "failed to update monitor")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "monitor updated successfully",
+		"data":    mapToResponse(m),
+	})
+}
+
+// Delete handles removing a monitor owned by the caller
+func (h *Handler) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		respondError(c, err, "failed to delete monitor")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "monitor deleted successfully", "data": nil})
+}
+
+// Pause handles suspending scheduled checks for a monitor
+func (h *Handler) Pause(c *gin.Context) {
+	h.setPaused(c, true, "monitor paused")
+}
+
+// Resume handles re-enabling scheduled checks for a paused monitor
+func (h *Handler) Resume(c *gin.Context) {
+	h.setPaused(c, false, "monitor resumed")
+}
+
+func (h *Handler) setPaused(c *gin.Context, paused bool, message string) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	m, err := h.svc.SetPaused(c.Request.Context(), userID.(string), c.Param("id"), paused)
+	if err != nil {
+		respondError(c, err, "failed to update monitor")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+		"data":    mapToResponse(m),
+	})
+}
+
+// Results handles paginated retrieval of a monitor's check history within an optional time range
+func (h *Handler) Results(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	q, page, err := parseResultQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error(), "data": nil})
+		return
+	}
+
+	results, total, err := h.svc.Results(c.Request.Context(), userID.(string), c.Param("id"), q)
+	if err != nil {
+		respondError(c, err, "failed to get check results")
+		return
+	}
+
+	responseData := make([]ResultResponse, 0, len(results))
+	for _, r := range results {
+		responseData = append(responseData, mapToResultResponse(r))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "check results retrieved",
+		"data": gin.H{
+			"results": responseData,
+			"page":    page,
+			"limit":   q.Limit,
+			"total":   total,
+		},
+	})
+}
+
+// Stats handles SLA reporting over a 24h, 7d, 30d or custom (from/to) window
+func (h *Handler) Stats(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	q := StatsQuery{Window: c.DefaultQuery("window", "24h")}
+	if q.Window == "custom" {
+		var fromErr, toErr error
+		q.From, fromErr = time.Parse(time.RFC3339, c.Query("from"))
+		q.To, toErr = time.Parse(time.RFC3339, c.Query("to"))
+		if fromErr != nil || toErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "custom window requires RFC3339 from and to", "data": nil})
+			return
+		}
+	}
+
+	stats, err := h.svc.Stats(c.Request.Context(), userID.(string), c.Param("id"), q)
+	if err != nil {
+		if errors.Is(err, ErrInvalidWindow) {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error(), "data": nil})
+			return
+		}
+		respondError(c, err, "failed to compute monitor stats")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "monitor stats computed",
+		"data":    mapToStatsResponse(stats),
+	})
+}
+
+// parseResultQuery reads the from/to (RFC3339) and page/limit query parameters
+func parseResultQuery(c *gin.Context) (ResultQuery, int, error) {
+	q := ResultQuery{Limit: defaultResultsLimit}
+	var err error
+
+	if from := c.Query("from"); from != "" {
+		if q.From, err = time.Parse(time.RFC3339, from); err != nil {
+			return q, 0, errors.New("from must be an RFC3339 timestamp")
+		}
+	}
+	if to := c.Query("to"); to != "" {
+		if q.To, err = time.Parse(time.RFC3339, to); err != nil {
+			return q, 0, errors.New("to must be an RFC3339 timestamp")
+		}
+	}
+	if !q.From.IsZero() && !q.To.IsZero() && q.To.Before(q.From) {
+		return q, 0, errors.New("to must not be before from")
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		if q.Limit, err = strconv.Atoi(limit); err != nil || q.Limit < 1 || q.Limit > maxResultsLimit {
+			return q, 0, errors.New("limit must be between 1 and 500")
+		}
+	}
+
+	page := 1
+	if p := c.Query("page"); p != "" {
+		if page, err = strconv.Atoi(p); err != nil || page < 1 {
+			return q, 0, errors.New("page must be a positive integer")
+		}
+	}
+	q.Offset = (page - 1) * q.Limit
+
+	return q, page, nil
+}
+
+// Ping handles check-ins from heartbeat jobs. It is unauthenticated: the token in the path is
+// the secret. The optional signal is start, fail or a numeric exit code, and a request body is
+// kept as the job output of a failure.
+func (h *Handler) Ping(c *gin.Context) {
+	ping := Ping{Kind: PingSuccess, At: time.Now()}
+	switch signal := c.Param("signal"); signal {
+	case "", "success":
+	case "start":
+		ping.Kind = PingStart
+	case "fail":
+		ping.Kind = PingFail
+	default:
+		code, err := strconv.Atoi(signal)
+		if err != nil || code < 0 || code > 255 {
+			c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "signal must be start, success, fail or an exit code", "data": nil})
+			return
+		}
+		ping.ExitCode = &code
+	}
+
+	if c.Request.Body != nil {
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxPingOutput))
+		if err == nil {
+			ping.Output = string(body)
+		}
+	}
+
+	if err := h.svc.Ping(c.Request.Context(), c.Param("token"), ping); err != nil {
+		respondError(c, err, "failed to record ping")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "ping received", "data": nil})
+}
+
+// respondError maps service errors onto HTTP status codes, falling back to a 500 with the given message
+func respondError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrInvalidCheck):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error(), "data": nil})
+	case errors.Is(err, ErrMonitorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "monitor not found", "data": nil})
+	case errors.Is(err, ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "message": "forbidden", "data": nil})
+	case errors.Is(err, ErrInvalidPoolSize):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error(), "data": nil})
+	case errors.Is(err, ErrQueueFull):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"success": false, "message": err.Error(), "data": nil})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": fallback, "data": nil})
+	}
+}
@@ -0,0 +1,158 @@
+package monitor
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// defaultHeartbeatGrace is how late a ping may arrive before a heartbeat is missed
+	defaultHeartbeatGrace = 5 * time.Minute
+	// maxPingOutput bounds the job output kept from a failure ping
+	maxPingOutput = 10 * 1024
+)
+
+// errPingsUnavailable is returned when a ping arrives before the service has a ping queue
+var errPingsUnavailable = errors.New("ping processing is not configured")
+
+// HeartbeatCheck configures a heartbeat monitor. Pings are expected every Interval of the
+// monitor; Grace is the extra time allowed before a missing ping, or a started job that has not
+// finished, marks it down.
+type HeartbeatCheck struct {
+	Grace time.Duration `json:"grace,omitempty"`
+}
+
+func (c HeartbeatCheck) grace() time.Duration {
+	if c.Grace <= 0 {
+		return defaultHeartbeatGrace
+	}
+	return c.Grace
+}
+
+// PingKind is the signal a job sends to its heartbeat monitor
+type PingKind string
+
+const (
+	PingSuccess PingKind = "success"
+	PingStart   PingKind = "start"
+	PingFail    PingKind = "fail"
+)
+
+// Ping is a check-in from a job. ExitCode is the job's exit status when reported; a non-zero
+// exit code turns any ping into a failure.
+type Ping struct {
+	Kind     PingKind
+	ExitCode *int
+	Output   string
+	At       time.Time
+}
+
+// PingQueue accepts jobs produced by pings and reports whether there was room; implemented by
+// WorkerPool
+type PingQueue interface {
+	Submit(job Job) bool
+}
+
+// heartbeatDeadline returns when the next ping is overdue: grace after a reported start, or a
+// period plus grace after the last result. It is zero until the first ping arrives.
+func (m *Monitor) heartbeatDeadline() time.Time {
+	if m.StartedAt.After(m.LastChecked) {
+		return m.StartedAt.Add(m.Heartbeat.grace())
+	}
+	if m.LastChecked.IsZero() {
+		return time.Time{}
+	}
+	return m.LastChecked.Add(m.Interval + m.Heartbeat.grace())
+}
+
+// checkHeartbeat turns a ping into a result, or, for a scheduled job without a ping, reports a
+// missed heartbeat once its deadline has passed. It returns nil when there is nothing to record.
+func checkHeartbeat(m *Monitor, ping *Ping, now time.Time) *CheckResult {
+	result := &CheckResult{MonitorID: m.ID, CheckedAt: now}
+
+	if ping == nil {
+		deadline := m.heartbeatDeadline()
+		if deadline.IsZero() || now.Before(deadline) {
+			return nil
+		}
+		result.ErrorClass = ErrorClassMissedPing
+		if m.StartedAt.After(m.LastChecked) {
+			result.FailureReason = fmt.Sprintf("job started at %s did not report completion within %s",
+				m.StartedAt.Format(time.RFC3339), m.Heartbeat.grace())
+		} else {
+			result.FailureReason = fmt.Sprintf("no ping received since %s (period %s, grace %s)",
+				m.LastChecked.Format(time.RFC3339), m.Interval, m.Heartbeat.grace())
+		}
+		return result
+	}
+
+	// The ping is recorded at its arrival, not when a worker got to it, so that a queue backlog
+	// does not push out the next deadline
+	result.CheckedAt = ping.At
+
+	// A job that reported its start is timed from then until its final ping
+	if m.StartedAt.After(m.LastChecked) {
+		result.ResponseTime = ping.At.Sub(m.StartedAt)
+	}
+	result.ExitCode = ping.ExitCode
+	if ping.Kind != PingFail {
+		result.IsHealthy = true
+		return result
+	}
+
+	result.ErrorClass = ErrorClassJobFailed
+	result.FailureReason = "job reported failure"
+	if ping.ExitCode != nil {
+		result.FailureReason = fmt.Sprintf("job reported failure with exit code %d", *ping.ExitCode)
+	}
+	result.Output = ping.Output
+	return result
+}
+
+// Ping records a check-in for the heartbeat monitor owning token. A start only marks the job as
+// running; other pings are queued to be recorded like any check result, under the monitor's
+// lease like a scheduled check, and fail with ErrQueueFull when the workers are saturated so
+// that the job can retry. Pings to a paused monitor are accepted and ignored.
+func (s *serviceImpl) Ping(ctx context.Context, token string, ping Ping) error {
+	m, err := s.repo.GetByPingToken(ctx, token)
+	if err != nil {
+		return err
+	}
+	if m.IsPaused {
+		return nil
+	}
+
+	if ping.ExitCode != nil && *ping.ExitCode != 0 {
+		ping.Kind = PingFail
+	}
+	if len(ping.Output) > maxPingOutput {
+		ping.Output = ping.Output[:maxPingOutput]
+	}
+
+	if ping.Kind == PingStart {
+		m.StartedAt = ping.At
+		return s.repo.SetStarted(ctx, m.ID, ping.At, m.nextRunAt())
+	}
+	if s.pings == nil {
+		return errPingsUnavailable
+	}
+	if !s.pings.Submit(Job{Monitor: m, Ping: &ping}) {
+		return ErrQueueFull
+	}
+	return nil
+}
+
+func (s *serviceImpl) SetPingQueue(q PingQueue) {
+	s.pings = q
+}
+
+// generatePingToken returns a random token identifying a heartbeat monitor in its ping URL
+func generatePingToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
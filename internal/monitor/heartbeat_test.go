@@ -0,0 +1,100 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestCheckHeartbeat(t *testing.T) {
+	last := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	m := &Monitor{ID: "m", Type: TypeHeartbeat, Interval: time.Hour, LastChecked: last, Heartbeat: HeartbeatCheck{Grace: 5 * time.Minute}}
+	pingAt := last.Add(time.Hour)
+	// The worker picks the ping up well after it arrived
+	now := pingAt.Add(3 * time.Minute)
+
+	tests := []struct {
+		name      string
+		ping      *Ping
+		now       time.Time
+		checkedAt time.Time
+		healthy   bool
+		errClass  string
+		none      bool
+	}{
+		{name: "success recorded at arrival", ping: &Ping{Kind: PingSuccess, At: pingAt}, now: now, checkedAt: pingAt, healthy: true},
+		{name: "failure recorded at arrival", ping: &Ping{Kind: PingFail, At: pingAt}, now: now, checkedAt: pingAt, errClass: ErrorClassJobFailed},
+		{name: "nothing before the deadline", now: last.Add(time.Hour + 4*time.Minute), none: true},
+		{name: "missed once the deadline passed", now: last.Add(time.Hour + 5*time.Minute), checkedAt: last.Add(time.Hour + 5*time.Minute), errClass: ErrorClassMissedPing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checkHeartbeat(m, tt.ping, tt.now)
+			if tt.none {
+				if result != nil {
+					t.Fatalf("result = %+v, want none", result)
+				}
+				return
+			}
+			if result == nil {
+				t.Fatal("no result")
+			}
+			if !result.CheckedAt.Equal(tt.checkedAt) {
+				t.Errorf("checked at = %s, want %s", result.CheckedAt, tt.checkedAt)
+			}
+			if result.IsHealthy != tt.healthy || result.ErrorClass != tt.errClass {
+				t.Errorf("healthy = %v, class = %q, want %v, %q", result.IsHealthy, result.ErrorClass, tt.healthy, tt.errClass)
+			}
+		})
+	}
+
+	// The next deadline follows the ping, not the moment it was processed
+	next := m.nextRunAfter(StatusUpdate{LastChecked: pingAt})
+	if want := pingAt.Add(time.Hour + 5*time.Minute); !next.Equal(want) {
+		t.Errorf("next deadline = %s, want %s", next, want)
+	}
+}
+
+func TestPingRecordedUnderLease(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository()
+	last := time.Now().Add(-time.Hour)
+	m := &Monitor{ID: "m", UserID: "u", Type: TypeHeartbeat, Interval: time.Hour, FailureThreshold: 2, Health: HealthUp, LastChecked: last}
+	if err := repo.Add(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	// The ping was accepted before an earlier failure was recorded
+	snapshot, _ := repo.GetByID(ctx, "m")
+	if err := repo.UpdateStatus(ctx, "m", StatusUpdate{LastChecked: last, Health: HealthUp, ConsecutiveFailures: 1, NextRunAt: last.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	wp := NewWorkerPool(1, repo, zap.NewNop(), nil)
+	wp.SetLease("node-a", time.Minute)
+	wp.safeProcessJob(ctx, nil, Job{Monitor: snapshot, Ping: &Ping{Kind: PingFail, At: time.Now()}})
+
+	got, _ := repo.GetByID(ctx, "m")
+	if got.Health != HealthDown || got.ConsecutiveFailures != 2 {
+		t.Errorf("health = %s after %d failures, want down after 2", got.Health, got.ConsecutiveFailures)
+	}
+	if got.LeaseOwner != "" {
+		t.Errorf("lease held by %q after the ping", got.LeaseOwner)
+	}
+
+	// While another check holds the lease the ping waits, and is dropped once it gives up
+	if _, err := repo.Claim(ctx, "m", "node-b", time.Now(), time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, 3*pingClaimRetry)
+	defer cancel()
+	wp.safeProcessJob(waitCtx, nil, Job{Monitor: snapshot, Ping: &Ping{Kind: PingSuccess, At: time.Now()}})
+	if _, total, _ := repo.ListResults(ctx, "m", ResultQuery{}); total != 1 {
+		t.Errorf("recorded %d results, want the ping under the other lease dropped", total)
+	}
+	if got, _ := repo.GetByID(ctx, "m"); got.LeaseOwner != "node-b" {
+		t.Errorf("lease owner = %q, want node-b", got.LeaseOwner)
+	}
+}
@@ -0,0 +1,81 @@
+package monitor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPath is a parsed subset of JSONPath: a "$" root followed by ".key", "['key']" and "[index]"
+// segments. Each segment is either a string key or an int index.
+type jsonPath []interface{}
+
+func parseJSONPath(expr string) (jsonPath, error) {
+	invalid := fmt.Errorf("%w: json path %q must look like $.items[0].status", ErrInvalidCheck, expr)
+	if !strings.HasPrefix(expr, "$") {
+		return nil, invalid
+	}
+
+	var path jsonPath
+	rest := expr[1:]
+	for rest != "" {
+		switch {
+		case rest[0] == '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, invalid
+			}
+			path = append(path, rest[:end])
+			rest = rest[end:]
+		case strings.HasPrefix(rest, "['"):
+			end := strings.Index(rest, "']")
+			if end < 0 {
+				return nil, invalid
+			}
+			path = append(path, rest[2:end])
+			rest = rest[end+2:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, invalid
+			}
+			index, err := strconv.Atoi(rest[1:end])
+			if err != nil || index < 0 {
+				return nil, invalid
+			}
+			path = append(path, index)
+			rest = rest[end+1:]
+		default:
+			return nil, invalid
+		}
+	}
+	return path, nil
+}
+
+// lookup resolves the path against a decoded JSON document
+func (p jsonPath) lookup(doc interface{}) (interface{}, bool) {
+	current := doc
+	for _, segment := range p {
+		switch key := segment.(type) {
+		case string:
+			obj, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = obj[key]; !ok {
+				return nil, false
+			}
+		case int:
+			arr, ok := current.([]interface{})
+			if !ok || key >= len(arr) {
+				return nil, false
+			}
+			current = arr[key]
+		}
+	}
+	return current, true
+}
@@ -0,0 +1,105 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		expr string
+		want jsonPath
+	}{
+		{expr: "$", want: nil},
+		{expr: "$.status", want: jsonPath{"status"}},
+		{expr: "$.data.items", want: jsonPath{"data", "items"}},
+		{expr: "$.items[0].status", want: jsonPath{"items", 0, "status"}},
+		{expr: "$[2]", want: jsonPath{2}},
+		{expr: "$.matrix[1][3]", want: jsonPath{"matrix", 1, 3}},
+		{expr: "$['content-type'].value", want: jsonPath{"content-type", "value"}},
+		{expr: "$['a.b']['c[0]']", want: jsonPath{"a.b", "c[0]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := parseJSONPath(tt.expr)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("path = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSONPathMalformed(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"status",
+		".status",
+		"$.",
+		"$..status",
+		"$.items[",
+		"$.items[]",
+		"$.items[-1]",
+		"$.items[x]",
+		"$['unterminated",
+		"$status",
+		"$.items[0]status",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			if _, err := parseJSONPath(expr); !errors.Is(err, ErrInvalidCheck) {
+				t.Errorf("err = %v, want ErrInvalidCheck", err)
+			}
+		})
+	}
+}
+
+func TestJSONPathLookup(t *testing.T) {
+	var doc interface{}
+	body := `{"status":"ok","data":{"items":[{"id":1,"tags":["a","b"]},{"id":2,"tags":[]}],"count":2},"nothing":null}`
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr  string
+		want  interface{}
+		found bool
+	}{
+		{expr: "$", want: doc, found: true},
+		{expr: "$.status", want: "ok", found: true},
+		{expr: "$.data.count", want: float64(2), found: true},
+		{expr: "$.data.items[1].id", want: float64(2), found: true},
+		{expr: "$.data.items[0].tags[1]", want: "b", found: true},
+		{expr: "$['data']['items'][0]['id']", want: float64(1), found: true},
+		{expr: "$.nothing", want: nil, found: true},
+		{expr: "$.missing", found: false},
+		{expr: "$.data.items[2]", found: false},
+		{expr: "$.data.items[1].tags[0]", found: false},
+		// Segments that do not fit the value's type do not resolve
+		{expr: "$.status[0]", found: false},
+		{expr: "$.data.items.id", found: false},
+		{expr: "$[0]", found: false},
+		{expr: "$.nothing.deeper", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			path, err := parseJSONPath(tt.expr)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			got, found := path.lookup(doc)
+			if found != tt.found {
+				t.Fatalf("found = %v, want %v", found, tt.found)
+			}
+			if found && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("value = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
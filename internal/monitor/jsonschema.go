@@ -0,0 +1,217 @@
+package monitor
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+// jsonSchema is the supported subset of JSON Schema: type, enum, const, properties, required,
+// additionalProperties (boolean), items, min/max length and items, numeric bounds and pattern
+type jsonSchema struct {
+	Type                 schemaTypes            `json:"type"`
+	Enum                 []interface{}          `json:"enum"`
+	Const                interface{}            `json:"const"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Pattern              string                 `json:"pattern"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	ExclusiveMinimum     *float64               `json:"exclusiveMinimum"`
+	ExclusiveMaximum     *float64               `json:"exclusiveMaximum"`
+
+	pattern *regexp.Regexp
+}
+
+// schemaTypes accepts both "type": "string" and "type": ["string", "null"]
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+var schemaTypeNames = map[string]bool{
+	"object": true, "array": true, "string": true, "number": true, "integer": true, "boolean": true, "null": true,
+}
+
+// parseJSONSchema decodes and checks a schema so that evaluation cannot fail on its structure
+func parseJSONSchema(raw json.RawMessage) (*jsonSchema, error) {
+	var s jsonSchema
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return nil, fmt.Errorf("%w: json schema is not valid JSON: %v", ErrInvalidCheck, err)
+	}
+	if err := s.compile(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *jsonSchema) compile() error {
+	for _, t := range s.Type {
+		if !schemaTypeNames[t] {
+			return fmt.Errorf("%w: json schema type %q is not supported", ErrInvalidCheck, t)
+		}
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("%w: json schema pattern %q: %v", ErrInvalidCheck, s.Pattern, err)
+		}
+		s.pattern = re
+	}
+	for _, prop := range s.Properties {
+		if prop == nil {
+			continue
+		}
+		if err := prop.compile(); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.compile()
+	}
+	return nil
+}
+
+// validate returns a description of the first violation found, or "" when v conforms
+func (s *jsonSchema) validate(v interface{}, at string) string {
+	if len(s.Type) > 0 && !s.matchesType(v) {
+		return fmt.Sprintf("%s: expected type %v, got %s", at, []string(s.Type), jsonTypeOf(v))
+	}
+	if s.Const != nil && !reflect.DeepEqual(s.Const, v) {
+		return fmt.Sprintf("%s: expected constant %v", at, s.Const)
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if reflect.DeepEqual(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Sprintf("%s: value is not one of %v", at, s.Enum)
+		}
+	}
+
+	switch val := v.(type) {
+	case string:
+		length := utf8.RuneCountInString(val)
+		if s.MinLength != nil && length < *s.MinLength {
+			return fmt.Sprintf("%s: shorter than %d characters", at, *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			return fmt.Sprintf("%s: longer than %d characters", at, *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(val) {
+			return fmt.Sprintf("%s: does not match pattern %q", at, s.Pattern)
+		}
+	case float64:
+		switch {
+		case s.Minimum != nil && val < *s.Minimum:
+			return fmt.Sprintf("%s: %v is below minimum %v", at, val, *s.Minimum)
+		case s.Maximum != nil && val > *s.Maximum:
+			return fmt.Sprintf("%s: %v is above maximum %v", at, val, *s.Maximum)
+		case s.ExclusiveMinimum != nil && val <= *s.ExclusiveMinimum:
+			return fmt.Sprintf("%s: %v is not above %v", at, val, *s.ExclusiveMinimum)
+		case s.ExclusiveMaximum != nil && val >= *s.ExclusiveMaximum:
+			return fmt.Sprintf("%s: %v is not below %v", at, val, *s.ExclusiveMaximum)
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(val) < *s.MinItems {
+			return fmt.Sprintf("%s: fewer than %d items", at, *s.MinItems)
+		}
+		if s.MaxItems != nil && len(val) > *s.MaxItems {
+			return fmt.Sprintf("%s: more than %d items", at, *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range val {
+				if msg := s.Items.validate(item, fmt.Sprintf("%s[%d]", at, i)); msg != "" {
+					return msg
+				}
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				return fmt.Sprintf("%s: missing required property %q", at, name)
+			}
+		}
+		// Walk properties in a stable order so the reported violation is deterministic
+		names := make([]string, 0, len(val))
+		for name := range val {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, declared := s.Properties[name]
+			if !declared {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Sprintf("%s: unexpected property %q", at, name)
+				}
+				continue
+			}
+			if prop == nil {
+				continue
+			}
+			if msg := prop.validate(val[name], at+"."+name); msg != "" {
+				return msg
+			}
+		}
+	}
+	return ""
+}
+
+func (s *jsonSchema) matchesType(v interface{}) bool {
+	actual := jsonTypeOf(v)
+	for _, t := range s.Type {
+		if t == actual {
+			return true
+		}
+		if t == "number" && actual == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonTypeOf names the JSON Schema type of a value decoded by encoding/json
+func jsonTypeOf(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if val == math.Trunc(val) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
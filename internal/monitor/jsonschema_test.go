@@ -0,0 +1,133 @@
+package monitor
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseJSONSchemaMalformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+	}{
+		{name: "not JSON", schema: `{"type":`},
+		{name: "unknown type", schema: `{"type":"date"}`},
+		{name: "type of wrong kind", schema: `{"type":7}`},
+		{name: "invalid pattern", schema: `{"type":"string","pattern":"("}`},
+		{name: "unknown type in a property", schema: `{"properties":{"id":{"type":"uuid"}}}`},
+		{name: "invalid pattern in items", schema: `{"items":{"pattern":"[a-"}}`},
+		{name: "bound of wrong kind", schema: `{"minimum":"1"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseJSONSchema(json.RawMessage(tt.schema)); !errors.Is(err, ErrInvalidCheck) {
+				t.Errorf("err = %v, want ErrInvalidCheck", err)
+			}
+		})
+	}
+}
+
+func TestJSONSchemaValidate(t *testing.T) {
+	const schema = `{
+		"type": "object",
+		"required": ["status", "items"],
+		"additionalProperties": false,
+		"properties": {
+			"status": {"enum": ["ok", "degraded"]},
+			"version": {"type": "string", "pattern": "^v[0-9]+$", "maxLength": 4},
+			"count": {"type": "integer", "minimum": 0, "exclusiveMaximum": 100},
+			"ratio": {"type": ["number", "null"], "maximum": 1},
+			"items": {
+				"type": "array",
+				"minItems": 1,
+				"items": {
+					"type": "object",
+					"required": ["id"],
+					"properties": {"id": {"type": "integer"}, "name": {"type": "string", "minLength": 1}}
+				}
+			},
+			"kind": {"const": "service"}
+		}
+	}`
+
+	tests := []struct {
+		name string
+		body string
+		// violation is a substring of the reported violation, "" when the body conforms
+		violation string
+	}{
+		{name: "conforming", body: `{"status":"ok","version":"v2","count":3,"ratio":0.5,"items":[{"id":1,"name":"a"}],"kind":"service"}`},
+		{name: "nullable property", body: `{"status":"ok","ratio":null,"items":[{"id":1}]}`},
+		{name: "integer accepted as number", body: `{"status":"ok","ratio":1,"items":[{"id":1}]}`},
+		{name: "root of wrong type", body: `[1,2]`, violation: "$: expected type [object], got array"},
+		{name: "missing required property", body: `{"status":"ok"}`, violation: `$: missing required property "items"`},
+		{name: "unexpected property", body: `{"status":"ok","items":[{"id":1}],"extra":true}`, violation: `$: unexpected property "extra"`},
+		{name: "value outside enum", body: `{"status":"down","items":[{"id":1}]}`, violation: "$.status: value is not one of"},
+		{name: "constant mismatch", body: `{"status":"ok","items":[{"id":1}],"kind":"job"}`, violation: "$.kind: expected constant service"},
+		{name: "pattern mismatch", body: `{"status":"ok","version":"2.0","items":[{"id":1}]}`, violation: "$.version: does not match pattern"},
+		{name: "string too long", body: `{"status":"ok","version":"v1234","items":[{"id":1}]}`, violation: "$.version: longer than 4 characters"},
+		{name: "fraction for integer", body: `{"status":"ok","count":1.5,"items":[{"id":1}]}`, violation: "$.count: expected type [integer], got number"},
+		{name: "string for integer", body: `{"status":"ok","count":"3","items":[{"id":1}]}`, violation: "$.count: expected type [integer], got string"},
+		{name: "below minimum", body: `{"status":"ok","count":-1,"items":[{"id":1}]}`, violation: "$.count: -1 is below minimum 0"},
+		{name: "at exclusive maximum", body: `{"status":"ok","count":100,"items":[{"id":1}]}`, violation: "$.count: 100 is not below 100"},
+		{name: "above maximum", body: `{"status":"ok","ratio":1.5,"items":[{"id":1}]}`, violation: "$.ratio: 1.5 is above maximum 1"},
+		{name: "too few items", body: `{"status":"ok","items":[]}`, violation: "$.items: fewer than 1 items"},
+		{name: "nested item of wrong type", body: `{"status":"ok","items":[{"id":1},{"id":"2"}]}`, violation: "$.items[1].id: expected type [integer], got string"},
+		{name: "nested item missing property", body: `{"status":"ok","items":[{"id":1},{"name":"b"}]}`, violation: `$.items[1]: missing required property "id"`},
+		{name: "nested string too short", body: `{"status":"ok","items":[{"id":1,"name":""}]}`, violation: "$.items[0].name: shorter than 1 characters"},
+	}
+
+	s, err := parseJSONSchema(json.RawMessage(schema))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var doc interface{}
+			if err := json.Unmarshal([]byte(tt.body), &doc); err != nil {
+				t.Fatal(err)
+			}
+			got := s.validate(doc, "$")
+			if tt.violation == "" {
+				if got != "" {
+					t.Errorf("violation = %q, want none", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.violation) {
+				t.Errorf("violation = %q, want it to contain %q", got, tt.violation)
+			}
+		})
+	}
+}
+
+func TestJSONTypeOf(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{body: `null`, want: "null"},
+		{body: `true`, want: "boolean"},
+		{body: `"x"`, want: "string"},
+		{body: `3`, want: "integer"},
+		{body: `3.0`, want: "integer"},
+		{body: `3.5`, want: "number"},
+		{body: `[]`, want: "array"},
+		{body: `{}`, want: "object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			var v interface{}
+			if err := json.Unmarshal([]byte(tt.body), &v); err != nil {
+				t.Fatal(err)
+			}
+			if got := jsonTypeOf(v); got != tt.want {
+				t.Errorf("type = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
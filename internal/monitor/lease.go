@@ -0,0 +1,30 @@
+package monitor
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultLeaseTTL bounds how long a claim on a monitor lasts; it covers the longest check, a
+// transaction or retried check at the maximum timeout, so that only a crashed or hung node lets
+// a lease expire
+const DefaultLeaseTTL = 15 * time.Minute
+
+// A ping waits up to pingClaimWait, polling every pingClaimRetry, for a check holding its
+// monitor's lease to finish
+const (
+	pingClaimWait  = 10 * time.Second
+	pingClaimRetry = 100 * time.Millisecond
+)
+
+// ErrLeaseLost is returned when releasing a monitor whose lease has passed to another owner or
+// was force-released
+var ErrLeaseLost = errors.New("monitor lease is no longer held")
+
+// ErrLeaseHeld is returned when claiming a monitor that is under another unexpired lease
+var ErrLeaseHeld = errors.New("monitor is leased by another check")
+
+// Leased reports whether a node holds an unexpired claim on the monitor at now
+func (m *Monitor) Leased(now time.Time) bool {
+	return m.LeaseOwner != "" && now.Before(m.LeaseExpiresAt)
+}
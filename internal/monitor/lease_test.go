@@ -0,0 +1,43 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReleaseAfterForceRelease(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository()
+	now := time.Now()
+	if err := repo.Add(ctx, &Monitor{ID: "m", UserID: "u", Interval: time.Minute, NextRunAt: now.Add(-time.Second)}); err != nil {
+		t.Fatal(err)
+	}
+
+	stale, err := repo.ClaimDue(ctx, "node-a", now, now.Add(time.Minute), 10)
+	if err != nil || len(stale) != 1 {
+		t.Fatalf("first claim: %v, %v", stale, err)
+	}
+	if err := repo.ForceRelease(ctx, "m"); err != nil {
+		t.Fatal(err)
+	}
+	fresh, err := repo.ClaimDue(ctx, "node-a", now.Add(time.Second), now.Add(2*time.Minute), 10)
+	if err != nil || len(fresh) != 1 {
+		t.Fatalf("second claim: %v, %v", fresh, err)
+	}
+
+	// The check of the first claim finishing late must not end the second one
+	err = repo.Release(ctx, "m", "node-a", stale[0].LeaseExpiresAt, now.Add(time.Minute))
+	if !errors.Is(err, ErrLeaseLost) {
+		t.Fatalf("stale release: err = %v, want ErrLeaseLost", err)
+	}
+	m, _ := repo.GetByID(ctx, "m")
+	if !m.Leased(now.Add(time.Second)) {
+		t.Fatal("second lease was ended by the stale release")
+	}
+
+	if err := repo.Release(ctx, "m", "node-a", fresh[0].LeaseExpiresAt, now.Add(time.Minute)); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+}
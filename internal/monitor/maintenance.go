@@ -0,0 +1,38 @@
+package monitor
+
+import (
+	"context"
+	"time"
+)
+
+// MaintenanceMode controls how checks behave while a monitor is inside a maintenance window
+type MaintenanceMode string
+
+const (
+	// MaintenanceNone means no window applies
+	MaintenanceNone MaintenanceMode = ""
+	// MaintenanceSkip stops checks from running at all
+	MaintenanceSkip MaintenanceMode = "skip"
+	// MaintenanceSuppress keeps checking but records results without changing health,
+	// asking the LLM or notifying observers
+	MaintenanceSuppress MaintenanceMode = "suppress"
+)
+
+// MaintenanceChecker reports the maintenance mode in effect for a monitor at a given time.
+// When several windows overlap, MaintenanceSkip takes precedence over MaintenanceSuppress.
+type MaintenanceChecker interface {
+	ActiveMode(ctx context.Context, m *Monitor, at time.Time) (MaintenanceMode, error)
+}
+
+// maintenanceMode asks the checker for the current mode, treating lookup failures as no window
+// so that a broken maintenance store never silences monitoring
+func maintenanceMode(ctx context.Context, checker MaintenanceChecker, m *Monitor, at time.Time) MaintenanceMode {
+	if checker == nil {
+		return MaintenanceNone
+	}
+	mode, err := checker.ActiveMode(ctx, m, at)
+	if err != nil {
+		return MaintenanceNone
+	}
+	return mode
+}
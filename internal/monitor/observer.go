@@ -0,0 +1,21 @@
+package monitor
+
+import "context"
+
+// CheckEvent describes a recorded check as delivered to observers of the WorkerPool.
+// Monitor reflects the state after the check; Transition is set only when the confirmed
+// health changed from Previous, so raw failures below the threshold are not transitions.
+// Transitions are suppressed while the monitor is flapping; Flap marks the single check that
+// started or stopped a flapping period.
+type CheckEvent struct {
+	Monitor    *Monitor
+	Result     *CheckResult
+	Previous   Health
+	Transition bool
+	Flap       FlapChange
+}
+
+// Observer reacts to check outcomes, e.g. to track incidents or send notifications
+type Observer interface {
+	OnCheck(ctx context.Context, event CheckEvent) error
+}
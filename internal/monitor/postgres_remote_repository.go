@@ -0,0 +1,154 @@
+package monitor
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+type postgresRoundRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRoundRepository creates a postgres round repository on an established connection pool
+func NewPostgresRoundRepository(db *sql.DB) (RoundRepository, error) {
+	if err := initRoundSchema(db); err != nil {
+		return nil, err
+	}
+
+	return &postgresRoundRepository{db: db}, nil
+}
+
+func initRoundSchema(db *sql.DB) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS remote_jobs (
+			round_id TEXT NOT NULL,
+			location TEXT NOT NULL,
+			monitor JSONB NOT NULL,
+			agent_id TEXT NOT NULL DEFAULT '',
+			result JSONB,
+			created_at TIMESTAMP NOT NULL,
+			expires_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (round_id, location)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_remote_jobs_pending ON remote_jobs (location, created_at) WHERE agent_id = ''`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *postgresRoundRepository) Open(ctx context.Context, jobs []*RoundJob, now time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Rounds of a leader that went down mid-round are never closed
+	if _, err := tx.ExecContext(ctx, `DELETE FROM remote_jobs WHERE expires_at <= $1`, now); err != nil {
+		return err
+	}
+	for _, j := range jobs {
+		monitor, err := json.Marshal(j.Monitor)
+		if err != nil {
+			return err
+		}
+		query := `INSERT INTO remote_jobs (round_id, location, monitor, created_at, expires_at) VALUES ($1, $2, $3, $4, $5)`
+		if _, err := tx.ExecContext(ctx, query, j.RoundID, j.Location, monitor, j.CreatedAt, j.ExpiresAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *postgresRoundRepository) Assign(ctx context.Context, agentID, location string, now time.Time, limit int) ([]*RoundJob, error) {
+	// SKIP LOCKED lets agents of the same location pulling through different nodes split the jobs
+	query := `
+	UPDATE remote_jobs SET agent_id = $1
+	WHERE (round_id, location) IN (
+		SELECT round_id, location FROM remote_jobs
+		WHERE location = $2 AND agent_id = '' AND expires_at > $3
+		ORDER BY created_at
+		LIMIT $4
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING round_id, location, monitor, agent_id, result, created_at, expires_at`
+	rows, err := r.db.QueryContext(ctx, query, agentID, location, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs, err := scanRoundJobs(rows)
+	if err != nil {
+		return nil, err
+	}
+	// RETURNING does not follow the subquery's order
+	sortRoundJobs(jobs)
+	return jobs, nil
+}
+
+func (r *postgresRoundRepository) Report(ctx context.Context, roundID, location, agentID string, result *CheckResult, now time.Time) error {
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	query := `
+	UPDATE remote_jobs SET result = $1
+	WHERE round_id = $2 AND location = $3 AND agent_id = $4 AND result IS NULL AND expires_at > $5`
+	res, err := r.db.ExecContext(ctx, query, encoded, roundID, location, agentID, now)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrUnknownJob
+	}
+	return nil
+}
+
+func (r *postgresRoundRepository) Jobs(ctx context.Context, roundID string) ([]*RoundJob, error) {
+	query := `SELECT round_id, location, monitor, agent_id, result, created_at, expires_at FROM remote_jobs WHERE round_id = $1`
+	rows, err := r.db.QueryContext(ctx, query, roundID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRoundJobs(rows)
+}
+
+func (r *postgresRoundRepository) Close(ctx context.Context, roundID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM remote_jobs WHERE round_id = $1`, roundID)
+	return err
+}
+
+func scanRoundJobs(rows *sql.Rows) ([]*RoundJob, error) {
+	var jobs []*RoundJob
+	for rows.Next() {
+		var j RoundJob
+		var monitor, result []byte
+		if err := rows.Scan(&j.RoundID, &j.Location, &monitor, &j.AgentID, &result, &j.CreatedAt, &j.ExpiresAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(monitor, &j.Monitor); err != nil {
+			return nil, err
+		}
+		if result != nil {
+			if err := json.Unmarshal(result, &j.Result); err != nil {
+				return nil, err
+			}
+		}
+		jobs = append(jobs, &j)
+	}
+	return jobs, rows.Err()
+}
@@ -3,38 +3,31 @@ package monitor
 import (
 	"context"
 	"database/sql"
-	"errors"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
-
-	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
+// monitorColumns lists the scalar columns, the JSONB columns backing jsonFields and finally the
+// JSONB check state written only by UpdateStatus
+const monitorColumns = `id, user_id, url, interval, last_checked, status_code, response_time, is_healthy, ai_explanation, is_paused,
+	failure_threshold, recovery_threshold, retry_count, retry_backoff, health, consecutive_failures, consecutive_successes, is_flapping,
+	health_reason, type, ping_token, started_at, next_run_at, lease_owner, lease_expires_at, quorum,
+	tags, request, assertions, tls, tcp, dns, heartbeat, steps, grpc, websocket, locations,
+	answers, timing`
+
+// jsonFields returns the monitor configuration stored as JSONB, in the order of their columns
+func jsonFields(m *Monitor) []interface{} {
+	return []interface{}{&m.Tags, &m.Request, &m.Assertions, &m.TLS, &m.TCP, &m.DNS, &m.Heartbeat, &m.Steps, &m.GRPC, &m.WebSocket, &m.Locations}
+}
+
 type postgresRepository struct {
 	db *sql.DB
 }
 
-// NewPostgresRepository creates a fully connected postgres tracking repository
-func NewPostgresRepository(dsn string) (Repository, error) {
-	db, err := sql.Open("pgx", dsn)
-	if err != nil {
-		return nil, err
-	}
-
-	// Backoff mechanism to ensure backend explicitly waits for dockerized database readiness
-	var pingErr error
-	for i := 0; i < 10; i++ {
-		pingErr = db.Ping()
-		if pingErr == nil {
-			break
-		}
-		time.Sleep(2 * time.Second)
-	}
-
-	if pingErr != nil {
-		return nil, fmt.Errorf("failed to reach database after retries: %w", pingErr)
-	}
-
+// NewPostgresRepository creates a postgres tracking repository on an established connection pool
+func NewPostgresRepository(db *sql.DB) (Repository, error) {
 	if err := initSchema(db); err != nil {
 		return nil, err
 	}
@@ -43,44 +36,165 @@ func NewPostgresRepository(dsn string) (Repository, error) {
 }
 
 func initSchema(db *sql.DB) error {
-	query := `
-	CREATE TABLE IF NOT EXISTS monitors (
-		id TEXT PRIMARY KEY,
-		user_id TEXT,
-		url TEXT,
-		interval BIGINT,
-		last_checked TIMESTAMP,
-		status_code INT,
-		response_time BIGINT,
-		is_healthy BOOLEAN,
-		ai_explanation TEXT,
-		is_running BOOLEAN
-	)`
-	_, err := db.Exec(query)
-	return err
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS monitors (
+			id TEXT PRIMARY KEY,
+			user_id TEXT,
+			url TEXT,
+			interval BIGINT,
+			last_checked TIMESTAMP,
+			status_code INT,
+			response_time BIGINT,
+			is_healthy BOOLEAN,
+			ai_explanation TEXT
+		)`,
+		// Columns added after the initial release are applied idempotently to existing databases
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS is_paused BOOLEAN NOT NULL DEFAULT FALSE`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS failure_threshold INT NOT NULL DEFAULT 1`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS recovery_threshold INT NOT NULL DEFAULT 1`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS retry_count INT NOT NULL DEFAULT 0`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS retry_backoff BIGINT NOT NULL DEFAULT 0`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS health TEXT NOT NULL DEFAULT 'pending'`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS consecutive_failures INT NOT NULL DEFAULT 0`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS consecutive_successes INT NOT NULL DEFAULT 0`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS is_flapping BOOLEAN NOT NULL DEFAULT FALSE`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS health_reason TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS tags JSONB NOT NULL DEFAULT '[]'`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS request JSONB NOT NULL
+			DEFAULT '{"method":"GET","timeout":10000000000,"follow_redirects":true,"expected_status":"200-399"}'`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS assertions JSONB NOT NULL DEFAULT '[]'`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS type TEXT NOT NULL DEFAULT 'http'`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS tls JSONB NOT NULL DEFAULT '{"expiry_warn_days":14}'`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS tcp JSONB NOT NULL DEFAULT '{}'`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS dns JSONB NOT NULL DEFAULT '{}'`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS answers JSONB NOT NULL DEFAULT '[]'`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS heartbeat JSONB NOT NULL DEFAULT '{}'`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS steps JSONB NOT NULL DEFAULT '[]'`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS grpc JSONB NOT NULL DEFAULT '{}'`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS websocket JSONB NOT NULL DEFAULT '{}'`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS timing JSONB NOT NULL DEFAULT 'null'`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS ping_token TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS started_at TIMESTAMP NOT NULL DEFAULT '0001-01-01 00:00:00'`,
+		// Monitors from before next_run_at, and heartbeats awaiting their first ping, are claimed
+		// once and rescheduled from their own state. The backfill runs only with the ALTER that
+		// adds the column: afterwards NULL marks a heartbeat that must stay unscheduled.
+		`DO $$
+		BEGIN
+			IF NOT EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_schema = current_schema() AND table_name = 'monitors' AND column_name = 'next_run_at'
+			) THEN
+				ALTER TABLE monitors ADD COLUMN next_run_at TIMESTAMP;
+				UPDATE monitors SET next_run_at = last_checked;
+			END IF;
+		END $$`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS locations JSONB NOT NULL DEFAULT '[]'`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS quorum INT NOT NULL DEFAULT 0`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS lease_owner TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE monitors ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMP`,
+		// Leases replace the running flag of databases created before them, which was left set
+		// forever by a node that crashed mid-check; dropping it also drops the due index that
+		// filtered on it
+		`ALTER TABLE monitors DROP COLUMN IF EXISTS is_running`,
+		`CREATE INDEX IF NOT EXISTS idx_monitors_due ON monitors (next_run_at) WHERE NOT is_paused`,
+		`CREATE INDEX IF NOT EXISTS idx_monitors_lease ON monitors (lease_expires_at) WHERE lease_owner <> ''`,
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_monitors_ping_token ON monitors (ping_token) WHERE ping_token <> ''`,
+		`CREATE TABLE IF NOT EXISTS check_results (
+			id BIGSERIAL PRIMARY KEY,
+			monitor_id TEXT NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+			checked_at TIMESTAMP NOT NULL,
+			status_code INT,
+			response_time BIGINT,
+			is_healthy BOOLEAN,
+			error_class TEXT,
+			ai_explanation TEXT
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_check_results_monitor_checked ON check_results (monitor_id, checked_at DESC)`,
+		`CREATE INDEX IF NOT EXISTS idx_check_results_checked ON check_results (checked_at)`,
+		`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 1`,
+		`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS in_maintenance BOOLEAN NOT NULL DEFAULT FALSE`,
+		`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS failure_reason TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS is_degraded BOOLEAN NOT NULL DEFAULT FALSE`,
+		`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS certificate JSONB`,
+		`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS answers JSONB`,
+		`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS answers_changed BOOLEAN NOT NULL DEFAULT FALSE`,
+		`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS exit_code INT`,
+		`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS output TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS steps JSONB`,
+		`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS grpc_code TEXT NOT NULL DEFAULT ''`,
+		`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS round_trip_time BIGINT NOT NULL DEFAULT 0`,
+		`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS timing JSONB`,
+		`ALTER TABLE check_results ADD COLUMN IF NOT EXISTS locations JSONB`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *postgresRepository) Add(ctx context.Context, m *Monitor) error {
-	query := `
-	INSERT INTO monitors (id, user_id, url, interval, last_checked, status_code, response_time, is_healthy, ai_explanation, is_running)
-	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
-	`
-	_, err := r.db.ExecContext(ctx, query,
-		m.ID, m.UserID, m.URL, m.Interval, m.LastChecked, m.StatusCode, m.ResponseTime, m.IsHealthy, m.AIExplanation, m.IsRunning,
-	)
+	encoded, err := encodeJSONFields(m)
+	if err != nil {
+		return err
+	}
+	answers, err := json.Marshal(m.Answers)
+	if err != nil {
+		return err
+	}
+	timing, err := json.Marshal(m.Timing)
+	if err != nil {
+		return err
+	}
+
+	args := append([]interface{}{
+		m.ID, m.UserID, m.URL, m.Interval, m.LastChecked, m.StatusCode, m.ResponseTime, m.IsHealthy, m.AIExplanation, m.IsPaused,
+		m.FailureThreshold, m.RecoveryThreshold, m.RetryCount, m.RetryBackoff, m.Health, m.ConsecutiveFailures, m.ConsecutiveSuccesses, m.IsFlapping,
+		m.HealthReason, m.Type, m.PingToken, m.StartedAt, nullableTime(m.NextRunAt), m.LeaseOwner, nullableTime(m.LeaseExpiresAt), m.Quorum,
+	}, encoded...)
+	args = append(args, answers, timing)
+
+	query := `INSERT INTO monitors (` + monitorColumns + `) VALUES (` + placeholders(1, len(args)) + `)`
+	_, err = r.db.ExecContext(ctx, query, args...)
 	return err
 }
 
 func (r *postgresRepository) List(ctx context.Context, userID string) ([]*Monitor, error) {
-	query := `SELECT id, user_id, url, interval, last_checked, status_code, response_time, is_healthy, ai_explanation, is_running FROM monitors WHERE user_id = $1`
+	query := `SELECT ` + monitorColumns + ` FROM monitors WHERE user_id = $1`
 	return r.queryMonitors(ctx, query, userID)
 }
 
 func (r *postgresRepository) GetAll(ctx context.Context) ([]*Monitor, error) {
-	query := `SELECT id, user_id, url, interval, last_checked, status_code, response_time, is_healthy, ai_explanation, is_running FROM monitors`
+	query := `SELECT ` + monitorColumns + ` FROM monitors`
 	return r.queryMonitors(ctx, query)
 }
 
+func (r *postgresRepository) GetByID(ctx context.Context, id string) (*Monitor, error) {
+	query := `SELECT ` + monitorColumns + ` FROM monitors WHERE id = $1`
+	monitors, err := r.queryMonitors(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(monitors) == 0 {
+		return nil, ErrMonitorNotFound
+	}
+	return monitors[0], nil
+}
+
+func (r *postgresRepository) GetByPingToken(ctx context.Context, token string) (*Monitor, error) {
+	query := `SELECT ` + monitorColumns + ` FROM monitors WHERE ping_token = $1 AND ping_token <> ''`
+	monitors, err := r.queryMonitors(ctx, query, token)
+	if err != nil {
+		return nil, err
+	}
+	if len(monitors) == 0 {
+		return nil, ErrMonitorNotFound
+	}
+	return monitors[0], nil
+}
+
 func (r *postgresRepository) queryMonitors(ctx context.Context, query string, args ...interface{}) ([]*Monitor, error) {
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -91,52 +205,420 @@ func (r *postgresRepository) queryMonitors(ctx context.Context, query string, ar
 	var result []*Monitor
 	for rows.Next() {
 		var m Monitor
-		if err := rows.Scan(
-			&m.ID, &m.UserID, &m.URL, &m.Interval, &m.LastChecked, &m.StatusCode, &m.ResponseTime, &m.IsHealthy, &m.AIExplanation, &m.IsRunning,
-		); err != nil {
+		var nextRunAt, leaseExpiresAt sql.NullTime
+		fields := append(jsonFields(&m), &m.Answers, &m.Timing)
+		raw := make([][]byte, len(fields))
+		dest := []interface{}{
+			&m.ID, &m.UserID, &m.URL, &m.Interval, &m.LastChecked, &m.StatusCode, &m.ResponseTime, &m.IsHealthy, &m.AIExplanation, &m.IsPaused,
+			&m.FailureThreshold, &m.RecoveryThreshold, &m.RetryCount, &m.RetryBackoff, &m.Health, &m.ConsecutiveFailures, &m.ConsecutiveSuccesses, &m.IsFlapping,
+			&m.HealthReason, &m.Type, &m.PingToken, &m.StartedAt, &nextRunAt, &m.LeaseOwner, &leaseExpiresAt, &m.Quorum,
+		}
+		for i := range raw {
+			dest = append(dest, &raw[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
 			return nil, err
 		}
+		for i, field := range fields {
+			if err := json.Unmarshal(raw[i], field); err != nil {
+				return nil, err
+			}
+		}
+		m.NextRunAt = nextRunAt.Time
+		m.LeaseExpiresAt = leaseExpiresAt.Time
 		result = append(result, &m)
 	}
 	return result, rows.Err()
 }
 
-func (r *postgresRepository) UpdateStatus(ctx context.Context, id string, lastChecked time.Time, statusCode int, responseTime time.Duration, isHealthy bool, aiExplanation string) error {
+func (r *postgresRepository) Update(ctx context.Context, m *Monitor) error {
+	encoded, err := encodeJSONFields(m)
+	if err != nil {
+		return err
+	}
+
 	query := `
-	UPDATE monitors 
-	SET last_checked = $1, status_code = $2, response_time = $3, is_healthy = $4, ai_explanation = $5
-	WHERE id = $6
+	UPDATE monitors
+	SET url = $1, interval = $2, is_paused = $3, failure_threshold = $4, recovery_threshold = $5, retry_count = $6, retry_backoff = $7,
+		tags = $8, request = $9, assertions = $10, tls = $11, tcp = $12, dns = $13, heartbeat = $14, steps = $15, grpc = $16, websocket = $17,
+		locations = $18, next_run_at = $19, quorum = $20
+	WHERE id = $21
 	`
-	res, err := r.db.ExecContext(ctx, query, lastChecked, statusCode, responseTime, isHealthy, aiExplanation, id)
+	args := append([]interface{}{m.URL, m.Interval, m.IsPaused, m.FailureThreshold, m.RecoveryThreshold, m.RetryCount, m.RetryBackoff}, encoded...)
+	args = append(args, nullableTime(m.NextRunAt), m.Quorum)
+	res, err := r.db.ExecContext(ctx, query, append(args, m.ID)...)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+func (r *postgresRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM monitors WHERE id = $1`, id)
 	if err != nil {
 		return err
 	}
+	return expectAffected(res)
+}
 
+func (r *postgresRepository) UpdateStatus(ctx context.Context, id string, u StatusUpdate) error {
+	query := `
+	UPDATE monitors
+	SET last_checked = $1, status_code = $2, response_time = $3, is_healthy = $4, ai_explanation = $5,
+		health = $6, consecutive_failures = $7, consecutive_successes = $8, is_flapping = $9, health_reason = $10, answers = $11, timing = $12,
+		next_run_at = $13
+	WHERE id = $14
+	`
+	answers, err := json.Marshal(u.Answers)
+	if err != nil {
+		return err
+	}
+	timing, err := json.Marshal(u.Timing)
+	if err != nil {
+		return err
+	}
+	res, err := r.db.ExecContext(ctx, query,
+		u.LastChecked, u.StatusCode, u.ResponseTime, u.Health == HealthUp, u.AIExplanation,
+		u.Health, u.ConsecutiveFailures, u.ConsecutiveSuccesses, u.IsFlapping, u.HealthReason, answers, timing, nullableTime(u.NextRunAt), id,
+	)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+// ClaimDue locks due rows with SKIP LOCKED so that concurrent schedulers claim disjoint monitors.
+// Rows under an expired lease are due as well, so a crashed node's monitors are picked up again.
+func (r *postgresRepository) ClaimDue(ctx context.Context, owner string, now, expiresAt time.Time, limit int) ([]*Monitor, error) {
+	query := `
+	UPDATE monitors SET lease_owner = $1, lease_expires_at = $2
+	WHERE id IN (
+		SELECT id FROM monitors
+		WHERE next_run_at <= $3 AND NOT is_paused AND (lease_owner = '' OR lease_expires_at <= $3)
+		ORDER BY next_run_at
+		LIMIT $4
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING ` + monitorColumns
+	return r.queryMonitors(ctx, query, owner, expiresAt, now, limit)
+}
+
+func (r *postgresRepository) Claim(ctx context.Context, id, owner string, now, expiresAt time.Time) (*Monitor, error) {
+	query := `
+	UPDATE monitors SET lease_owner = $1, lease_expires_at = $2
+	WHERE id = $3 AND (lease_owner = '' OR lease_expires_at <= $4)
+	RETURNING ` + monitorColumns
+	monitors, err := r.queryMonitors(ctx, query, owner, expiresAt, id, now)
+	if err != nil {
+		return nil, err
+	}
+	if len(monitors) == 0 {
+		// Tell a held lease apart from a monitor deleted since the ping was accepted
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, ErrLeaseHeld
+	}
+	return monitors[0], nil
+}
+
+func (r *postgresRepository) Release(ctx context.Context, id, owner string, expiresAt, nextRunAt time.Time) error {
+	query := `
+	UPDATE monitors SET lease_owner = '', lease_expires_at = NULL, next_run_at = $1
+	WHERE id = $2 AND lease_owner = $3 AND lease_expires_at = $4`
+	res, err := r.db.ExecContext(ctx, query, nullableTime(nextRunAt), id, owner, expiresAt)
+	if err != nil {
+		return err
+	}
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
 	if rowsAffected == 0 {
-		return errors.New("monitor not found")
+		return ErrLeaseLost
 	}
-
 	return nil
 }
 
-func (r *postgresRepository) SetRunning(ctx context.Context, id string, isRunning bool) error {
-	query := `UPDATE monitors SET is_running = $1 WHERE id = $2`
-	res, err := r.db.ExecContext(ctx, query, isRunning, id)
+func (r *postgresRepository) ReclaimLeases(ctx context.Context, now time.Time, owner string) ([]string, error) {
+	query := `
+	UPDATE monitors SET lease_owner = '', lease_expires_at = NULL
+	WHERE lease_owner <> '' AND (lease_expires_at <= $1 OR lease_owner = $2)
+	RETURNING id
+	`
+	rows, err := r.db.QueryContext(ctx, query, now, owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *postgresRepository) ListLeased(ctx context.Context) ([]*Monitor, error) {
+	query := `SELECT ` + monitorColumns + ` FROM monitors WHERE lease_owner <> '' ORDER BY lease_expires_at`
+	return r.queryMonitors(ctx, query)
+}
+
+func (r *postgresRepository) ForceRelease(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE monitors SET lease_owner = '', lease_expires_at = NULL WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+func (r *postgresRepository) SetStarted(ctx context.Context, id string, at, nextRunAt time.Time) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE monitors SET started_at = $1, next_run_at = $2 WHERE id = $3`, at, nullableTime(nextRunAt), id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+func (r *postgresRepository) AddResult(ctx context.Context, result *CheckResult) error {
+	certificate, err := encodeNullable(result.Certificate)
 	if err != nil {
 		return err
 	}
+	timing, err := encodeNullable(result.Timing)
+	if err != nil {
+		return err
+	}
+	var answers, steps, locations interface{}
+	if result.Answers != nil {
+		if answers, err = json.Marshal(result.Answers); err != nil {
+			return err
+		}
+	}
+	if result.Steps != nil {
+		if steps, err = json.Marshal(result.Steps); err != nil {
+			return err
+		}
+	}
+	if result.Locations != nil {
+		if locations, err = json.Marshal(result.Locations); err != nil {
+			return err
+		}
+	}
+
+	query := `
+	INSERT INTO check_results (monitor_id, checked_at, status_code, response_time, is_healthy, is_degraded, attempts, in_maintenance,
+		error_class, failure_reason, ai_explanation, certificate, answers, answers_changed, exit_code, output, steps, grpc_code, round_trip_time, timing,
+		locations)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+	RETURNING id
+	`
+	return r.db.QueryRowContext(ctx, query,
+		result.MonitorID, result.CheckedAt, result.StatusCode, result.ResponseTime, result.IsHealthy, result.IsDegraded, result.Attempts, result.InMaintenance,
+		result.ErrorClass, result.FailureReason, result.AIExplanation, certificate, answers, result.AnswersChanged, result.ExitCode, result.Output, steps, result.GRPCCode, result.RoundTripTime, timing,
+		locations,
+	).Scan(&result.ID)
+}
+
+func (r *postgresRepository) ResultStats(ctx context.Context, monitorID string, from, to time.Time) (Stats, error) {
+	// Checks taken during maintenance do not count; percentile_disc picks the nearest-rank value,
+	// as computeStats does
+	const window = `monitor_id = $1 AND checked_at >= $2 AND checked_at <= $3 AND NOT in_maintenance`
+
+	var stats Stats
+	var p50, p95, p99 int64
+	query := `
+	SELECT COUNT(*),
+		COUNT(*) FILTER (WHERE is_healthy),
+		COUNT(*) FILTER (WHERE is_degraded AND NOT is_healthy),
+		COALESCE(percentile_disc(0.50) WITHIN GROUP (ORDER BY response_time) FILTER (WHERE response_time > 0), 0),
+		COALESCE(percentile_disc(0.95) WITHIN GROUP (ORDER BY response_time) FILTER (WHERE response_time > 0), 0),
+		COALESCE(percentile_disc(0.99) WITHIN GROUP (ORDER BY response_time) FILTER (WHERE response_time > 0), 0)
+	FROM check_results WHERE ` + window
+	if err := r.db.QueryRowContext(ctx, query, monitorID, from, to).Scan(
+		&stats.TotalChecks, &stats.HealthyChecks, &stats.DegradedChecks, &p50, &p95, &p99,
+	); err != nil {
+		return Stats{}, err
+	}
+	stats.P50, stats.P95, stats.P99 = time.Duration(p50), time.Duration(p95), time.Duration(p99)
+	if stats.TotalChecks > 0 {
+		stats.UptimePercent = float64(stats.HealthyChecks+stats.DegradedChecks) / float64(stats.TotalChecks) * 100
+		stats.DegradedPercent = float64(stats.DegradedChecks) / float64(stats.TotalChecks) * 100
+	}
 
+	// An incident starts at a down check following an available one, or opening the window, and
+	// is repaired by the first available check after it
+	var repaired int
+	var repairSeconds float64
+	query = `
+	SELECT COUNT(*), COUNT(recovered_at), COALESCE(EXTRACT(EPOCH FROM SUM(recovered_at - checked_at))::float8, 0)
+	FROM (
+		SELECT checked_at, available,
+			LAG(available) OVER checks AS prev_available,
+			MIN(checked_at) FILTER (WHERE available) OVER (checks ROWS BETWEEN 1 FOLLOWING AND UNBOUNDED FOLLOWING) AS recovered_at
+		FROM (
+			SELECT id, checked_at, is_healthy OR is_degraded AS available FROM check_results WHERE ` + window + `
+		) results
+		WINDOW checks AS (ORDER BY checked_at, id)
+	) runs
+	WHERE NOT available AND prev_available IS NOT FALSE`
+	if err := r.db.QueryRowContext(ctx, query, monitorID, from, to).Scan(&stats.IncidentCount, &repaired, &repairSeconds); err != nil {
+		return Stats{}, err
+	}
+	if repaired > 0 {
+		stats.MTTR = time.Duration(repairSeconds*float64(time.Second)) / time.Duration(repaired)
+	}
+	return stats, nil
+}
+
+func (r *postgresRepository) PruneResults(ctx context.Context, before time.Time, limit int) (int, error) {
+	// Deleting in batches keeps each transaction short on a large history
+	query := `DELETE FROM check_results WHERE id IN (SELECT id FROM check_results WHERE checked_at < $1 LIMIT $2)`
+	res, err := r.db.ExecContext(ctx, query, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rowsAffected), nil
+}
+
+func (r *postgresRepository) ListResults(ctx context.Context, monitorID string, q ResultQuery) ([]*CheckResult, int, error) {
+	where := `monitor_id = $1`
+	args := []interface{}{monitorID}
+	if !q.From.IsZero() {
+		args = append(args, q.From)
+		where += fmt.Sprintf(` AND checked_at >= $%d`, len(args))
+	}
+	if !q.To.IsZero() {
+		args = append(args, q.To)
+		where += fmt.Sprintf(` AND checked_at <= $%d`, len(args))
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM check_results WHERE `+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+	SELECT id, monitor_id, checked_at, status_code, response_time, is_healthy, is_degraded, attempts, in_maintenance, error_class, failure_reason,
+		ai_explanation, certificate, answers, answers_changed, exit_code, output, steps, grpc_code, round_trip_time, timing,
+		locations
+	FROM check_results WHERE ` + where + ` ORDER BY checked_at DESC, id DESC`
+	if q.Limit > 0 {
+		args = append(args, q.Limit)
+		query += fmt.Sprintf(` LIMIT $%d`, len(args))
+	}
+	if q.Offset > 0 {
+		args = append(args, q.Offset)
+		query += fmt.Sprintf(` OFFSET $%d`, len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var results []*CheckResult
+	for rows.Next() {
+		var res CheckResult
+		var certificate, answers, steps, timing, locations []byte
+		var exitCode sql.NullInt32
+		if err := rows.Scan(
+			&res.ID, &res.MonitorID, &res.CheckedAt, &res.StatusCode, &res.ResponseTime, &res.IsHealthy, &res.IsDegraded, &res.Attempts, &res.InMaintenance, &res.ErrorClass, &res.FailureReason, &res.AIExplanation,
+			&certificate, &answers, &res.AnswersChanged, &exitCode, &res.Output, &steps, &res.GRPCCode, &res.RoundTripTime, &timing,
+			&locations,
+		); err != nil {
+			return nil, 0, err
+		}
+		if certificate != nil {
+			if err := json.Unmarshal(certificate, &res.Certificate); err != nil {
+				return nil, 0, err
+			}
+		}
+		if timing != nil {
+			if err := json.Unmarshal(timing, &res.Timing); err != nil {
+				return nil, 0, err
+			}
+		}
+		if exitCode.Valid {
+			code := int(exitCode.Int32)
+			res.ExitCode = &code
+		}
+		if answers != nil {
+			if err := json.Unmarshal(answers, &res.Answers); err != nil {
+				return nil, 0, err
+			}
+		}
+		if steps != nil {
+			if err := json.Unmarshal(steps, &res.Steps); err != nil {
+				return nil, 0, err
+			}
+		}
+		if locations != nil {
+			if err := json.Unmarshal(locations, &res.Locations); err != nil {
+				return nil, 0, err
+			}
+		}
+		results = append(results, &res)
+	}
+	return results, total, rows.Err()
+}
+
+// encodeJSONFields marshals the fields returned by jsonFields, in column order
+func encodeJSONFields(m *Monitor) ([]interface{}, error) {
+	fields := jsonFields(m)
+	encoded := make([]interface{}, len(fields))
+	for i, field := range fields {
+		b, err := json.Marshal(field)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = b
+	}
+	return encoded, nil
+}
+
+// encodeNullable marshals an optional value, mapping nil onto SQL NULL
+func encodeNullable[T any](v *T) (interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// nullableTime stores a zero time as NULL
+func nullableTime(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+// placeholders renders the positional parameters $from..$to for a VALUES list
+func placeholders(from, to int) string {
+	parts := make([]string, 0, to-from+1)
+	for i := from; i <= to; i++ {
+		parts = append(parts, fmt.Sprintf("$%d", i))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// expectAffected maps an update that touched no rows onto ErrMonitorNotFound
+func expectAffected(res sql.Result) error {
 	rowsAffected, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
 	if rowsAffected == 0 {
-		return errors.New("monitor not found")
+		return ErrMonitorNotFound
 	}
-
 	return nil
 }
@@ -0,0 +1,24 @@
+package monitor
+
+import (
+	"context"
+	"crypto/x509"
+
+	"go.uber.org/zap"
+)
+
+// Prober runs checks outside a WorkerPool and returns their results instead of recording them,
+// as probe agents do for the server
+type Prober struct {
+	wp *WorkerPool
+}
+
+// NewProber creates a prober; roots, when not nil, replace the system pool for verifying targets
+func NewProber(logger *zap.Logger, roots *x509.CertPool) *Prober {
+	return &Prober{wp: &WorkerPool{logger: logger, roots: roots}}
+}
+
+// Probe runs a monitor's check with its retries; it returns nil when ctx is cancelled
+func (p *Prober) Probe(ctx context.Context, m *Monitor) *CheckResult {
+	return p.wp.checkWithRetries(ctx, newHTTPClients(p.wp.roots), m)
+}
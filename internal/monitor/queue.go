@@ -0,0 +1,219 @@
+package monitor
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaultQueueSize bounds the jobs waiting for a worker
+const defaultQueueSize = 1000
+
+// submitWait is how long Submit waits for room in a full queue before giving up on a job
+const submitWait = 5 * time.Second
+
+// jobQueue is a bounded priority queue of jobs, ordered so that the most overdue job runs first.
+// Waiters are woken by closing the changed channel, which is replaced on every push, pop or
+// explicit wake.
+type jobQueue struct {
+	mu       sync.Mutex
+	items    jobHeap
+	capacity int
+	seq      uint64
+	closed   bool
+	changed  chan struct{}
+}
+
+func newJobQueue(capacity int) *jobQueue {
+	return &jobQueue{capacity: capacity, changed: make(chan struct{})}
+}
+
+// queuedJob is a job with its place in the queue
+type queuedJob struct {
+	job      Job
+	due      time.Time
+	seq      uint64
+	queuedAt time.Time
+}
+
+// due returns when the job should have run: a scheduled monitor's next run, or when a ping
+// arrived. Monitors never checked before have a zero next run and go first.
+func (j Job) due() time.Time {
+	if j.Ping != nil {
+		return j.Ping.At
+	}
+	return j.Monitor.NextRunAt
+}
+
+// push queues a job, waiting up to wait for room when the queue is full. It reports whether the
+// job was queued; it gives up early when ctx is done or the queue is closed.
+func (q *jobQueue) push(ctx context.Context, job Job, wait time.Duration) (queued, waited bool) {
+	var timeout <-chan time.Time
+	for {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			return false, waited
+		}
+		if len(q.items) < q.capacity {
+			q.seq++
+			heap.Push(&q.items, queuedJob{job: job, due: job.due(), seq: q.seq, queuedAt: time.Now()})
+			q.notifyLocked()
+			q.mu.Unlock()
+			return true, waited
+		}
+		changed := q.changed
+		q.mu.Unlock()
+
+		if timeout == nil {
+			if wait <= 0 {
+				return false, false
+			}
+			timer := time.NewTimer(wait)
+			defer timer.Stop()
+			timeout = timer.C
+			waited = true
+		}
+		select {
+		case <-changed:
+		case <-timeout:
+			return false, waited
+		case <-ctx.Done():
+			return false, waited
+		}
+	}
+}
+
+// pop removes the most overdue job. When the queue is empty it blocks until something changes
+// and returns false, so that callers can re-check whether they should stop.
+func (q *jobQueue) pop(ctx context.Context) (Job, bool) {
+	q.mu.Lock()
+	if len(q.items) > 0 {
+		item := heap.Pop(&q.items).(queuedJob)
+		q.notifyLocked()
+		q.mu.Unlock()
+		return item.job, true
+	}
+	changed := q.changed
+	q.mu.Unlock()
+
+	select {
+	case <-changed:
+	case <-ctx.Done():
+	}
+	return Job{}, false
+}
+
+// wake releases everyone blocked on the queue
+func (q *jobQueue) wake() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.notifyLocked()
+}
+
+// close makes pending and future pushes fail; queued jobs are dropped with the pool
+func (q *jobQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.notifyLocked()
+}
+
+func (q *jobQueue) notifyLocked() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// depth returns the queued job count and the age of the oldest queued job
+func (q *jobQueue) depth(now time.Time) (int, time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var oldest time.Duration
+	for _, item := range q.items {
+		oldest = max(oldest, now.Sub(item.queuedAt))
+	}
+	return len(q.items), oldest
+}
+
+// free returns how many more jobs fit without waiting
+func (q *jobQueue) free() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.capacity - len(q.items)
+}
+
+// jobHeap implements heap.Interface, earliest due first and in submission order on ties
+type jobHeap []queuedJob
+
+func (h jobHeap) Len() int { return len(h) }
+
+func (h jobHeap) Less(i, j int) bool {
+	if !h[i].due.Equal(h[j].due) {
+		return h[i].due.Before(h[j].due)
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h jobHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *jobHeap) Push(x any) { *h = append(*h, x.(queuedJob)) }
+
+func (h *jobHeap) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// ErrQueueFull is returned when a ping cannot be queued because the workers are saturated
+var ErrQueueFull = errors.New("check queue is full, retry later")
+
+// maxWorkers bounds the size a pool can be resized to
+const maxWorkers = 500
+
+// ErrInvalidPoolSize is returned when resizing the pool outside its bounds
+var ErrInvalidPoolSize = errors.New("invalid worker pool size")
+
+// PoolStats describes the worker pool's capacity and how submissions fared. Waited counts jobs
+// that found the queue full and had to wait for room; Dropped counts those that gave up.
+type PoolStats struct {
+	Workers       int           `json:"workers"`
+	TargetWorkers int           `json:"target_workers"`
+	Busy          int           `json:"busy"`
+	QueueDepth    int           `json:"queue_depth"`
+	QueueCapacity int           `json:"queue_capacity"`
+	OldestQueued  time.Duration `json:"oldest_queued"`
+	Submitted     int64         `json:"submitted"`
+	Waited        int64         `json:"waited"`
+	Dropped       int64         `json:"dropped"`
+	// RemoteRounds counts checks handed to probe agents whose results are still being collected
+	RemoteRounds int `json:"remote_rounds"`
+}
+
+type poolStats struct {
+	mu    sync.Mutex
+	stats PoolStats
+}
+
+func (s *poolStats) submitted(queued, waited bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if queued {
+		s.stats.Submitted++
+	} else {
+		s.stats.Dropped++
+	}
+	if waited {
+		s.stats.Waited++
+	}
+}
+
+func (s *poolStats) snapshot() PoolStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
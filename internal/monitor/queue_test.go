@@ -0,0 +1,214 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestJobQueueOrder(t *testing.T) {
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	scheduled := func(id string, offset time.Duration) Job {
+		return Job{Monitor: &Monitor{ID: id, NextRunAt: base.Add(offset)}}
+	}
+
+	tests := []struct {
+		name string
+		jobs []Job
+		want []string
+	}{
+		{
+			name: "most overdue first",
+			jobs: []Job{scheduled("b", 2*time.Second), scheduled("c", 3*time.Second), scheduled("a", time.Second)},
+			want: []string{"a", "b", "c"},
+		},
+		{
+			name: "ties in submission order",
+			jobs: []Job{scheduled("first", 0), scheduled("second", 0), scheduled("third", 0)},
+			want: []string{"first", "second", "third"},
+		},
+		{
+			name: "never checked goes first",
+			jobs: []Job{scheduled("due", 0), {Monitor: &Monitor{ID: "new"}}},
+			want: []string{"new", "due"},
+		},
+		{
+			name: "pings ordered by arrival",
+			jobs: []Job{scheduled("scheduled", 2*time.Second), {Monitor: &Monitor{ID: "ping", NextRunAt: base.Add(time.Hour)}, Ping: &Ping{At: base.Add(time.Second)}}},
+			want: []string{"ping", "scheduled"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			q := newJobQueue(len(tt.jobs))
+			for _, job := range tt.jobs {
+				if queued, _ := q.push(ctx, job, 0); !queued {
+					t.Fatalf("push %s: not queued", job.Monitor.ID)
+				}
+			}
+			for i, want := range tt.want {
+				job, ok := q.pop(ctx)
+				if !ok || job.Monitor.ID != want {
+					t.Fatalf("pop %d = %q, want %q", i, job.Monitor.ID, want)
+				}
+			}
+		})
+	}
+}
+
+func TestJobQueuePushWait(t *testing.T) {
+	job := Job{Monitor: &Monitor{ID: "m"}}
+
+	tests := []struct {
+		name string
+		// act runs while a push waits on the full queue
+		act    func(q *jobQueue, cancel context.CancelFunc)
+		wait   time.Duration
+		queued bool
+		waited bool
+	}{
+		{name: "no wait gives up at once", wait: 0},
+		{name: "gives up after the wait", wait: 50 * time.Millisecond, waited: true},
+		{name: "queued once a slot frees", wait: time.Second, queued: true, waited: true,
+			act: func(q *jobQueue, _ context.CancelFunc) { q.pop(context.Background()) }},
+		{name: "closed queue gives up", wait: time.Second, waited: true,
+			act: func(q *jobQueue, _ context.CancelFunc) { q.close() }},
+		{name: "cancelled context gives up", wait: time.Second, waited: true,
+			act: func(_ *jobQueue, cancel context.CancelFunc) { cancel() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			q := newJobQueue(1)
+			if queued, _ := q.push(ctx, job, 0); !queued {
+				t.Fatal("first push not queued")
+			}
+			if tt.act != nil {
+				time.AfterFunc(20*time.Millisecond, func() { tt.act(q, cancel) })
+			}
+
+			start := time.Now()
+			queued, waited := q.push(ctx, job, tt.wait)
+			if queued != tt.queued || waited != tt.waited {
+				t.Fatalf("queued = %v, waited = %v; want %v, %v", queued, waited, tt.queued, tt.waited)
+			}
+			if elapsed := time.Since(start); elapsed > tt.wait+500*time.Millisecond {
+				t.Errorf("push took %s with a %s wait", elapsed, tt.wait)
+			}
+			if got := q.free(); got != 0 {
+				t.Errorf("free = %d, want a full queue", got)
+			}
+		})
+	}
+}
+
+func TestWorkerPoolResize(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wp := NewWorkerPool(2, NewRepository(), zap.NewNop(), nil)
+	wp.Start(ctx)
+
+	for _, size := range []int{0, maxWorkers + 1} {
+		if err := wp.Resize(size); !errors.Is(err, ErrInvalidPoolSize) {
+			t.Errorf("resize to %d: err = %v, want ErrInvalidPoolSize", size, err)
+		}
+	}
+
+	for _, size := range []int{5, 1, 3} {
+		if err := wp.Resize(size); err != nil {
+			t.Fatalf("resize to %d: %v", size, err)
+		}
+		// Surplus idle workers exit once woken; poll until they have
+		var stats PoolStats
+		for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			if stats = wp.Stats(); stats.Workers == size {
+				break
+			}
+		}
+		if stats.Workers != size || stats.TargetWorkers != size {
+			t.Fatalf("after resizing to %d: %d workers, target %d", size, stats.Workers, stats.TargetWorkers)
+		}
+	}
+}
+
+func TestSchedulerBackpressure(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		// prepare leaves the pool unable to take jobs
+		prepare   func(t *testing.T, wp *WorkerPool)
+		saturated int64
+		requeued  int64
+	}{
+		{
+			name: "full queue claims nothing",
+			prepare: func(t *testing.T, wp *WorkerPool) {
+				for i := 0; i < defaultQueueSize; i++ {
+					if queued, _ := wp.queue.push(context.Background(), Job{Monitor: &Monitor{ID: "filler"}}, 0); !queued {
+						t.Fatal("filler not queued")
+					}
+				}
+			},
+			saturated: 1,
+		},
+		{
+			name: "rejected claims are handed back",
+			prepare: func(t *testing.T, wp *WorkerPool) {
+				// A stopped pool still reports free room but refuses every submission
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				wp.Start(ctx)
+				for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+					wp.queue.mu.Lock()
+					closed := wp.queue.closed
+					wp.queue.mu.Unlock()
+					if closed {
+						break
+					}
+				}
+			},
+			requeued: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := NewRepository()
+			for _, id := range []string{"a", "b"} {
+				if err := repo.Add(ctx, &Monitor{ID: id, UserID: "u", Type: TypeHTTP, Interval: time.Minute, NextRunAt: now.Add(-time.Second)}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			wp := NewWorkerPool(1, repo, zap.NewNop(), nil)
+			tt.prepare(t, wp)
+
+			s := NewScheduler(repo, wp, zap.NewNop(), 1)
+			s.SetLease("node-a", time.Minute)
+			s.queueDueMonitors(ctx)
+
+			stats := s.Stats()
+			if stats.SaturatedTicks != tt.saturated || stats.Requeued != tt.requeued {
+				t.Fatalf("saturated ticks %d, requeued %d; want %d, %d", stats.SaturatedTicks, stats.Requeued, tt.saturated, tt.requeued)
+			}
+			// Either way the monitors are left unclaimed and due as before
+			for _, id := range []string{"a", "b"} {
+				m, err := repo.GetByID(ctx, id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if m.LeaseOwner != "" || !m.NextRunAt.Equal(now.Add(-time.Second)) {
+					t.Errorf("monitor %s: lease owner %q, next run %s; want unclaimed and due at %s", id, m.LeaseOwner, m.NextRunAt, now.Add(-time.Second))
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// remoteGrace covers the time an agent takes to pull a job and push its result on top of the
+// check itself
+const remoteGrace = 30 * time.Second
+
+// ErrUnknownJob is returned when an agent reports on a job it was not assigned, or whose round
+// already finished
+var ErrUnknownJob = errors.New("unknown or expired job")
+
+// LocationResult is the outcome of a check at one location. A location whose agent did not
+// report before the deadline is recorded as pending.
+type LocationResult struct {
+	Location      string        `json:"location"`
+	AgentID       string        `json:"agent_id,omitempty"`
+	Outcome       Health        `json:"outcome"`
+	StatusCode    int           `json:"status_code"`
+	ResponseTime  time.Duration `json:"response_time"`
+	ErrorClass    string        `json:"error_class,omitempty"`
+	FailureReason string        `json:"failure_reason,omitempty"`
+}
+
+// RemoteJob is a check handed to a probe agent; the agent reports its result under ID
+type RemoteJob struct {
+	ID      string   `json:"id"`
+	Monitor *Monitor `json:"monitor"`
+}
+
+// remotePoll is how often open rounds are checked for results
+const remotePoll = 500 * time.Millisecond
+
+// RemoteDispatcher hands the checks of monitors with locations to probe agents and collects
+// their results. Rounds are kept in a RoundRepository, so agents may talk to any node while the
+// node that opened a round collects its results.
+type RemoteDispatcher struct {
+	rounds RoundRepository
+
+	mu   sync.Mutex
+	open map[string]*remoteRound // rounds this node opened, by ID
+}
+
+// remoteRound is a round this node opened and waits on. Its job keeps the monitor's lease until
+// the round's result is recorded.
+type remoteRound struct {
+	id       string
+	job      Job
+	mode     MaintenanceMode
+	deadline time.Time
+}
+
+// finishedRound is a round that every location reported on or whose deadline passed, with the
+// combined result of its locations
+type finishedRound struct {
+	*remoteRound
+	result *CheckResult
+}
+
+// NewRemoteDispatcher creates a dispatcher keeping its rounds in rounds
+func NewRemoteDispatcher(rounds RoundRepository) *RemoteDispatcher {
+	return &RemoteDispatcher{rounds: rounds, open: make(map[string]*remoteRound)}
+}
+
+// Assign hands an agent up to limit jobs waiting for its location, oldest first. Each location of
+// a round goes to a single agent, so agents sharing a location split the work.
+func (d *RemoteDispatcher) Assign(ctx context.Context, agentID, location string, limit int) ([]RemoteJob, error) {
+	assigned, err := d.rounds.Assign(ctx, agentID, location, time.Now(), limit)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]RemoteJob, 0, len(assigned))
+	for _, j := range assigned {
+		jobs = append(jobs, RemoteJob{ID: j.RoundID, Monitor: j.Monitor})
+	}
+	return jobs, nil
+}
+
+// Report records the result an agent obtained for a job it was assigned
+func (d *RemoteDispatcher) Report(ctx context.Context, agentID, location, jobID string, result *CheckResult) error {
+	return d.rounds.Report(ctx, jobID, location, agentID, result, time.Now())
+}
+
+// start queues a check for each location of the job's monitor and tracks the round until
+// collect finishes it; mode is the maintenance mode the result is to be recorded under
+func (d *RemoteDispatcher) start(ctx context.Context, job Job, mode MaintenanceMode) error {
+	m := job.Monitor
+	now := time.Now()
+	round := &remoteRound{id: generatePingToken(), job: job, mode: mode, deadline: now.Add(m.checkBudget() + remoteGrace)}
+	jobs := make([]*RoundJob, 0, len(m.Locations))
+	for _, location := range m.Locations {
+		jobs = append(jobs, &RoundJob{RoundID: round.id, Location: location, Monitor: m, CreatedAt: now, ExpiresAt: round.deadline})
+	}
+	if err := d.rounds.Open(ctx, jobs, now); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.open[round.id] = round
+	return nil
+}
+
+// collect finishes the open rounds that every location reported on or whose deadline passed at
+// now, combining what was reported, and removes them so that agents stop picking them up.
+// Rounds whose jobs cannot be read are looked at again on the next call.
+func (d *RemoteDispatcher) collect(ctx context.Context, now time.Time) []finishedRound {
+	d.mu.Lock()
+	rounds := make([]*remoteRound, 0, len(d.open))
+	for _, round := range d.open {
+		rounds = append(rounds, round)
+	}
+	d.mu.Unlock()
+
+	var finished []finishedRound
+	for _, round := range rounds {
+		jobs, err := d.rounds.Jobs(ctx, round.id)
+		if err != nil || (now.Before(round.deadline) && !allReported(jobs)) {
+			continue
+		}
+		_ = d.rounds.Close(ctx, round.id)
+
+		d.mu.Lock()
+		delete(d.open, round.id)
+		d.mu.Unlock()
+		finished = append(finished, finishedRound{remoteRound: round, result: combineLocations(round.job.Monitor, jobs, now)})
+	}
+	return finished
+}
+
+// pending returns how many rounds this node opened are waiting for agents
+func (d *RemoteDispatcher) pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.open)
+}
+
+func allReported(jobs []*RoundJob) bool {
+	for _, j := range jobs {
+		if j.Result == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// quorum returns how many locations must fail for the monitor to be down
+func (m *Monitor) quorum() int {
+	if m.Quorum > 0 {
+		return m.Quorum
+	}
+	return len(m.Locations)/2 + 1
+}
+
+// checkBudget bounds how long a check with all its retries may take
+func (m *Monitor) checkBudget() time.Duration {
+	attempt := m.Request.timeout()
+	if m.Type == TypeTransaction {
+		attempt = 0
+		for _, s := range m.Steps {
+			attempt += s.Request.timeout()
+		}
+	}
+	budget := attempt
+	for retry := 1; retry <= m.RetryCount; retry++ {
+		budget += m.retryDelay(retry) + attempt
+	}
+	return budget
+}
+
+// combineLocations turns the results reported per location into one. The monitor is down when at
+// least its quorum of locations failed, degraded when fewer did or a location was degraded, and
+// up otherwise; locations that did not report count towards neither. The status code, timings and
+// certificate are those of the first failing location when down, else of the first available one.
+func combineLocations(m *Monitor, jobs []*RoundJob, now time.Time) *CheckResult {
+	results := make(map[string]*CheckResult, len(jobs))
+	agents := make(map[string]string, len(jobs))
+	for _, j := range jobs {
+		agents[j.Location] = j.AgentID
+		if j.Result != nil {
+			results[j.Location] = j.Result
+		}
+	}
+
+	var locations []LocationResult
+	var failed, degraded []string
+	var firstAvailable, firstFailed *CheckResult
+	for _, location := range m.Locations {
+		lr := LocationResult{Location: location, AgentID: agents[location], Outcome: HealthPending}
+		r, ok := results[location]
+		if !ok {
+			lr.FailureReason = "no result before the deadline"
+			if lr.AgentID == "" {
+				lr.FailureReason = "no agent available"
+			}
+			locations = append(locations, lr)
+			continue
+		}
+
+		lr.Outcome = r.Outcome()
+		lr.StatusCode = r.StatusCode
+		lr.ResponseTime = r.ResponseTime
+		lr.ErrorClass = r.ErrorClass
+		lr.FailureReason = r.reason()
+		locations = append(locations, lr)
+
+		switch lr.Outcome {
+		case HealthDown:
+			failed = append(failed, describeLocation(lr))
+			if firstFailed == nil {
+				firstFailed = r
+			}
+			continue
+		case HealthDegraded:
+			degraded = append(degraded, describeLocation(lr))
+		}
+		if firstAvailable == nil {
+			firstAvailable = r
+		}
+	}
+
+	if firstAvailable == nil && firstFailed == nil {
+		return &CheckResult{
+			MonitorID:     m.ID,
+			CheckedAt:     now,
+			IsDegraded:    true,
+			Attempts:      1,
+			ErrorClass:    ErrorClassNoAgents,
+			FailureReason: "no probe agent reported from " + strings.Join(m.Locations, ", "),
+			Locations:     locations,
+		}
+	}
+
+	var result CheckResult
+	switch {
+	case len(failed) >= m.quorum():
+		result = *firstFailed
+		result.FailureReason = fmt.Sprintf("down from %d of %d locations: %s", len(failed), len(m.Locations), strings.Join(failed, "; "))
+	case len(failed) > 0:
+		if firstAvailable != nil {
+			result = *firstAvailable
+		} else {
+			result = *firstFailed
+		}
+		result.IsHealthy = false
+		result.IsDegraded = true
+		result.ErrorClass = ""
+		result.FailureReason = fmt.Sprintf("failing from %d of %d locations, below the quorum of %d: %s",
+			len(failed), len(m.Locations), m.quorum(), strings.Join(failed, "; "))
+	default:
+		result = *firstAvailable
+		if len(degraded) > 0 {
+			result.IsHealthy = false
+			result.IsDegraded = true
+			result.FailureReason = "degraded from " + strings.Join(degraded, "; ")
+		}
+	}
+
+	result.ID = 0
+	result.MonitorID = m.ID
+	result.CheckedAt = now
+	result.Locations = locations
+	for _, r := range results {
+		result.Attempts = max(result.Attempts, r.Attempts)
+	}
+	return &result
+}
+
+func describeLocation(lr LocationResult) string {
+	if lr.FailureReason == "" {
+		return lr.Location
+	}
+	return fmt.Sprintf("%s (%s)", lr.Location, lr.FailureReason)
+}
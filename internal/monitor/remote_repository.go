@@ -0,0 +1,138 @@
+package monitor
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RoundJob is the check of one location within a remote round
+type RoundJob struct {
+	RoundID   string
+	Location  string
+	Monitor   *Monitor
+	AgentID   string // empty until an agent takes the job
+	Result    *CheckResult
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// RoundRepository keeps remote check rounds where every node can reach them, so that agents may
+// pull jobs from and report results to any replica while the node that opened the round collects it
+type RoundRepository interface {
+	// Open stores one pending job per location of a round, dropping rounds that expired
+	Open(ctx context.Context, jobs []*RoundJob, now time.Time) error
+	// Assign gives agentID up to limit unexpired pending jobs of location, oldest first
+	Assign(ctx context.Context, agentID, location string, now time.Time, limit int) ([]*RoundJob, error)
+	// Report stores the result of a job assigned to agentID; it returns ErrUnknownJob when the
+	// job was not assigned to it, already reported or expired
+	Report(ctx context.Context, roundID, location, agentID string, result *CheckResult, now time.Time) error
+	// Jobs returns the jobs of a round
+	Jobs(ctx context.Context, roundID string) ([]*RoundJob, error)
+	// Close deletes a round
+	Close(ctx context.Context, roundID string) error
+}
+
+// sortRoundJobs orders jobs oldest first
+func sortRoundJobs(jobs []*RoundJob) {
+	sort.Slice(jobs, func(a, b int) bool { return jobs[a].CreatedAt.Before(jobs[b].CreatedAt) })
+}
+
+type roundKey struct {
+	round    string
+	location string
+}
+
+type inMemoryRoundRepository struct {
+	mu   sync.Mutex
+	jobs map[roundKey]*RoundJob
+}
+
+// NewRoundRepository creates an in-memory round repository for single-node deployments
+func NewRoundRepository() RoundRepository {
+	return &inMemoryRoundRepository{jobs: make(map[roundKey]*RoundJob)}
+}
+
+func cloneRoundJob(j *RoundJob) *RoundJob {
+	c := *j
+	c.Monitor = cloneMonitor(j.Monitor)
+	if j.Result != nil {
+		r := *j.Result
+		c.Result = &r
+	}
+	return &c
+}
+
+func (r *inMemoryRoundRepository) Open(ctx context.Context, jobs []*RoundJob, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, j := range r.jobs {
+		if !j.ExpiresAt.After(now) {
+			delete(r.jobs, key)
+		}
+	}
+	for _, j := range jobs {
+		r.jobs[roundKey{j.RoundID, j.Location}] = cloneRoundJob(j)
+	}
+	return nil
+}
+
+func (r *inMemoryRoundRepository) Assign(ctx context.Context, agentID, location string, now time.Time, limit int) ([]*RoundJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var pending []*RoundJob
+	for _, j := range r.jobs {
+		if j.Location == location && j.AgentID == "" && j.ExpiresAt.After(now) {
+			pending = append(pending, j)
+		}
+	}
+	sortRoundJobs(pending)
+
+	assigned := make([]*RoundJob, 0, min(limit, len(pending)))
+	for _, j := range pending[:min(limit, len(pending))] {
+		j.AgentID = agentID
+		assigned = append(assigned, cloneRoundJob(j))
+	}
+	return assigned, nil
+}
+
+func (r *inMemoryRoundRepository) Report(ctx context.Context, roundID, location, agentID string, result *CheckResult, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	j, ok := r.jobs[roundKey{roundID, location}]
+	if !ok || j.AgentID != agentID || j.Result != nil || !j.ExpiresAt.After(now) {
+		return ErrUnknownJob
+	}
+	stored := *result
+	j.Result = &stored
+	return nil
+}
+
+func (r *inMemoryRoundRepository) Jobs(ctx context.Context, roundID string) ([]*RoundJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var jobs []*RoundJob
+	for _, j := range r.jobs {
+		if j.RoundID == roundID {
+			jobs = append(jobs, cloneRoundJob(j))
+		}
+	}
+	return jobs, nil
+}
+
+func (r *inMemoryRoundRepository) Close(ctx context.Context, roundID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.jobs {
+		if key.round == roundID {
+			delete(r.jobs, key)
+		}
+	}
+	return nil
+}
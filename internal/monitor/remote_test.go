@@ -0,0 +1,290 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func locationJob(location, agentID string, result *CheckResult) *RoundJob {
+	return &RoundJob{Location: location, AgentID: agentID, Result: result}
+}
+
+func TestCombineLocationsQuorum(t *testing.T) {
+	up := &CheckResult{IsHealthy: true, StatusCode: 200}
+	down := &CheckResult{StatusCode: 503, ErrorClass: ErrorClassHTTPStatus, FailureReason: "unexpected status 503"}
+	degraded := &CheckResult{IsDegraded: true, StatusCode: 200, FailureReason: "slow"}
+
+	tests := []struct {
+		name       string
+		quorum     int
+		jobs       []*RoundJob
+		want       Health
+		errorClass string
+		pending    []string
+	}{
+		{
+			name: "all locations up",
+			jobs: []*RoundJob{locationJob("eu", "a", up), locationJob("us", "b", up), locationJob("ap", "c", up)},
+			want: HealthUp,
+		},
+		{
+			name:       "majority down by default",
+			jobs:       []*RoundJob{locationJob("eu", "a", down), locationJob("us", "b", down), locationJob("ap", "c", up)},
+			want:       HealthDown,
+			errorClass: ErrorClassHTTPStatus,
+		},
+		{
+			name: "failures below the quorum degrade",
+			jobs: []*RoundJob{locationJob("eu", "a", down), locationJob("us", "b", up), locationJob("ap", "c", up)},
+			want: HealthDegraded,
+		},
+		{
+			name:       "explicit quorum of one",
+			quorum:     1,
+			jobs:       []*RoundJob{locationJob("eu", "a", down), locationJob("us", "b", up), locationJob("ap", "c", up)},
+			want:       HealthDown,
+			errorClass: ErrorClassHTTPStatus,
+		},
+		{
+			name:   "explicit quorum of all",
+			quorum: 3,
+			jobs:   []*RoundJob{locationJob("eu", "a", down), locationJob("us", "b", down), locationJob("ap", "c", up)},
+			want:   HealthDegraded,
+		},
+		{
+			name: "degraded location",
+			jobs: []*RoundJob{locationJob("eu", "a", degraded), locationJob("us", "b", up), locationJob("ap", "c", up)},
+			want: HealthDegraded,
+		},
+		{
+			name:    "missing location counts towards neither",
+			jobs:    []*RoundJob{locationJob("eu", "a", down), locationJob("us", "b", up), locationJob("ap", "", nil)},
+			want:    HealthDegraded,
+			pending: []string{"ap"},
+		},
+		{
+			name:       "no agent reported",
+			jobs:       []*RoundJob{locationJob("eu", "", nil), locationJob("us", "b", nil), locationJob("ap", "", nil)},
+			want:       HealthDegraded,
+			errorClass: ErrorClassNoAgents,
+			pending:    []string{"eu", "us", "ap"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Monitor{ID: "m", Locations: []string{"eu", "us", "ap"}, Quorum: tt.quorum}
+			result := combineLocations(m, tt.jobs, time.Now())
+
+			if got := result.Outcome(); got != tt.want {
+				t.Fatalf("outcome = %s, want %s (reason %q)", got, tt.want, result.FailureReason)
+			}
+			if result.ErrorClass != tt.errorClass {
+				t.Errorf("error class = %q, want %q", result.ErrorClass, tt.errorClass)
+			}
+			if len(result.Locations) != len(m.Locations) {
+				t.Fatalf("locations = %d, want %d", len(result.Locations), len(m.Locations))
+			}
+			var pending []string
+			for _, lr := range result.Locations {
+				if lr.Outcome == HealthPending {
+					pending = append(pending, lr.Location)
+				}
+			}
+			if len(pending) != len(tt.pending) {
+				t.Errorf("pending locations = %v, want %v", pending, tt.pending)
+			}
+		})
+	}
+}
+
+// fakeAgent pulls jobs for its location through a dispatcher and reports a fixed outcome
+type fakeAgent struct {
+	id       string
+	location string
+	healthy  bool
+}
+
+func (a fakeAgent) work(ctx context.Context, d *RemoteDispatcher) error {
+	for ctx.Err() == nil {
+		jobs, err := d.Assign(ctx, a.id, a.location, 10)
+		if err != nil {
+			return err
+		}
+		for _, job := range jobs {
+			result := &CheckResult{MonitorID: job.Monitor.ID, CheckedAt: time.Now(), IsHealthy: a.healthy, StatusCode: 200, Attempts: 1}
+			if !a.healthy {
+				result.StatusCode = 503
+				result.ErrorClass = ErrorClassHTTPStatus
+			}
+			if err := d.Report(ctx, a.id, a.location, job.ID, result); err != nil {
+				return err
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return nil
+}
+
+func TestRemoteDispatcherMultiAgentQuorum(t *testing.T) {
+	tests := []struct {
+		name   string
+		agents []fakeAgent
+		want   Health
+	}{
+		{
+			name:   "two of three locations down",
+			agents: []fakeAgent{{"eu-1", "eu", false}, {"us-1", "us", false}, {"ap-1", "ap", true}},
+			want:   HealthDown,
+		},
+		{
+			name:   "one of three locations down",
+			agents: []fakeAgent{{"eu-1", "eu", false}, {"us-1", "us", true}, {"ap-1", "ap", true}},
+			want:   HealthDegraded,
+		},
+		{
+			name:   "agents sharing a location split the work",
+			agents: []fakeAgent{{"eu-1", "eu", true}, {"eu-2", "eu", true}, {"us-1", "us", true}, {"ap-1", "ap", true}},
+			want:   HealthUp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The checking node and the node agents talk to share only the round repository,
+			// as replicas share the database
+			rounds := NewRoundRepository()
+			leader := NewRemoteDispatcher(rounds)
+			replica := NewRemoteDispatcher(rounds)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			errs := make(chan error, len(tt.agents))
+			for _, a := range tt.agents {
+				go func() { errs <- a.work(ctx, replica) }()
+			}
+
+			m := &Monitor{ID: "m", Type: TypeHTTP, Locations: []string{"eu", "us", "ap"}, Quorum: 2, Request: HTTPRequest{Timeout: time.Second}}
+			if err := leader.start(ctx, Job{Monitor: m}, MaintenanceNone); err != nil {
+				t.Fatalf("start round: %v", err)
+			}
+			var result *CheckResult
+			for deadline := time.Now().Add(5 * time.Second); result == nil && time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+				for _, round := range leader.collect(ctx, time.Now()) {
+					result = round.result
+				}
+			}
+			cancel()
+			for range tt.agents {
+				if err := <-errs; err != nil && !errors.Is(err, context.Canceled) {
+					t.Errorf("agent: %v", err)
+				}
+			}
+
+			if result == nil {
+				t.Fatal("round returned no result")
+			}
+			if got := result.Outcome(); got != tt.want {
+				t.Fatalf("outcome = %s, want %s (reason %q)", got, tt.want, result.FailureReason)
+			}
+			for _, lr := range result.Locations {
+				if lr.Outcome == HealthPending {
+					t.Errorf("location %s did not report", lr.Location)
+				}
+			}
+
+			// Locations fully reported leave nothing for a late agent
+			if late, err := rounds.Assign(context.Background(), "late", "eu", time.Now(), 10); err != nil || len(late) != 0 {
+				t.Errorf("jobs left after the round: %v, %v", late, err)
+			}
+		})
+	}
+}
+
+func TestRoundRepositoryRejectsForeignReports(t *testing.T) {
+	rounds := NewRoundRepository()
+	ctx := context.Background()
+	now := time.Now()
+	m := &Monitor{ID: "m", Locations: []string{"eu"}}
+	if err := rounds.Open(ctx, []*RoundJob{{RoundID: "r", Location: "eu", Monitor: m, CreatedAt: now, ExpiresAt: now.Add(time.Minute)}}, now); err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if _, err := rounds.Assign(ctx, "eu-1", "eu", now, 10); err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+
+	ok := &CheckResult{IsHealthy: true}
+	if err := rounds.Report(ctx, "r", "eu", "eu-2", ok, now); !errors.Is(err, ErrUnknownJob) {
+		t.Errorf("report by another agent: err = %v, want ErrUnknownJob", err)
+	}
+	if err := rounds.Report(ctx, "r", "eu", "eu-1", ok, now.Add(2*time.Minute)); !errors.Is(err, ErrUnknownJob) {
+		t.Errorf("report after expiry: err = %v, want ErrUnknownJob", err)
+	}
+	if err := rounds.Report(ctx, "r", "eu", "eu-1", ok, now); err != nil {
+		t.Errorf("report by the assigned agent: %v", err)
+	}
+	if err := rounds.Report(ctx, "r", "eu", "eu-1", ok, now); !errors.Is(err, ErrUnknownJob) {
+		t.Errorf("second report: err = %v, want ErrUnknownJob", err)
+	}
+}
+
+func TestWorkerDoesNotWaitForRemoteRound(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	repo := NewRepository()
+	now := time.Now()
+	m := &Monitor{ID: "m", UserID: "u", Type: TypeHTTP, Interval: time.Minute, Locations: []string{"eu"}, NextRunAt: now.Add(-time.Second)}
+	if err := repo.Add(ctx, m); err != nil {
+		t.Fatal(err)
+	}
+	claimed, err := repo.ClaimDue(ctx, "node-a", now, now.Add(time.Minute), 1)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("claim: %v, %v", claimed, err)
+	}
+
+	rounds := NewRoundRepository()
+	d := NewRemoteDispatcher(rounds)
+	wp := NewWorkerPool(1, repo, zap.NewNop(), nil)
+	wp.SetRemote(d)
+	wp.Start(ctx)
+	if !wp.Submit(Job{Monitor: claimed[0]}) {
+		t.Fatal("job not queued")
+	}
+
+	// The agent picks the job up only once the worker is free again
+	var jobs []RemoteJob
+	for deadline := time.Now().Add(2 * time.Second); len(jobs) == 0 && time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if jobs, err = d.Assign(ctx, "eu-1", "eu", 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(jobs) != 1 {
+		t.Fatal("round was not opened")
+	}
+	if stats := wp.Stats(); stats.Busy != 0 || stats.RemoteRounds != 1 {
+		t.Fatalf("busy = %d, remote rounds = %d while the agent checks; want a free worker and one round", stats.Busy, stats.RemoteRounds)
+	}
+	if got, _ := repo.GetByID(ctx, "m"); got.LeaseOwner != "node-a" {
+		t.Fatalf("lease owner = %q while the round is open, want node-a", got.LeaseOwner)
+	}
+
+	if err := d.Report(ctx, "eu-1", "eu", jobs[0].ID, &CheckResult{MonitorID: "m", IsHealthy: true, StatusCode: 200, CheckedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	var got *Monitor
+	for deadline := time.Now().Add(3 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+		if got, _ = repo.GetByID(ctx, "m"); got.LeaseOwner == "" {
+			break
+		}
+	}
+	if got.LeaseOwner != "" || got.Health != HealthUp || got.StatusCode != 200 {
+		t.Errorf("after the report: lease owner %q, health %s, status %d; want the result recorded and the lease released", got.LeaseOwner, got.Health, got.StatusCode)
+	}
+	if n := wp.Stats().RemoteRounds; n != 0 {
+		t.Errorf("remote rounds = %d after collection, want 0", n)
+	}
+}
@@ -12,19 +12,59 @@ type Repository interface {
 	Add(ctx context.Context, m *Monitor) error
 	List(ctx context.Context, userID string) ([]*Monitor, error)
 	GetAll(ctx context.Context) ([]*Monitor, error)
-	UpdateStatus(ctx context.Context, id string, lastChecked time.Time, statusCode int, responseTime time.Duration, isHealthy bool, aiExplanation string) error
-	SetRunning(ctx context.Context, id string, isRunning bool) error
+	GetByID(ctx context.Context, id string) (*Monitor, error)
+	// GetByPingToken returns the heartbeat monitor owning a ping token
+	GetByPingToken(ctx context.Context, token string) (*Monitor, error)
+	Update(ctx context.Context, m *Monitor) error
+	Delete(ctx context.Context, id string) error
+	UpdateStatus(ctx context.Context, id string, update StatusUpdate) error
+	// ClaimDue leases up to limit unpaused monitors whose next run is due at now to owner until
+	// expiresAt and returns them, earliest first. Monitors under an unexpired lease are skipped.
+	ClaimDue(ctx context.Context, owner string, now, expiresAt time.Time, limit int) ([]*Monitor, error)
+	// Claim leases a single monitor to owner until expiresAt whether or not it is due, as a ping
+	// does, and returns it. It returns ErrLeaseHeld while another lease is unexpired at now.
+	Claim(ctx context.Context, id, owner string, now, expiresAt time.Time) (*Monitor, error)
+	// Release ends the lease owner took on a monitor until expiresAt and schedules its next run; a
+	// zero time leaves it unscheduled. It returns ErrLeaseLost when that lease is no longer held,
+	// including when the monitor was force-released and claimed again since.
+	Release(ctx context.Context, id, owner string, expiresAt, nextRunAt time.Time) error
+	// ReclaimLeases ends leases that expired at now, and any held by owner when it is not empty,
+	// returning the affected monitor IDs
+	ReclaimLeases(ctx context.Context, now time.Time, owner string) ([]string, error)
+	// ListLeased returns the monitors currently under a lease, expired or not
+	ListLeased(ctx context.Context) ([]*Monitor, error)
+	// ForceRelease ends any lease on a monitor, leaving its next run unchanged
+	ForceRelease(ctx context.Context, id string) error
+	// SetStarted records when a heartbeat job reported its start and the deadline that follows
+	SetStarted(ctx context.Context, id string, at, nextRunAt time.Time) error
+	AddResult(ctx context.Context, result *CheckResult) error
+	// ListResults returns matching results newest first together with the total number of matches
+	ListResults(ctx context.Context, monitorID string, q ResultQuery) ([]*CheckResult, int, error)
+	// ResultStats summarizes the results checked between from and to
+	ResultStats(ctx context.Context, monitorID string, from, to time.Time) (Stats, error)
+	// PruneResults deletes up to limit results checked before the given time, across monitors,
+	// and returns how many it deleted
+	PruneResults(ctx context.Context, before time.Time, limit int) (int, error)
 }
 
+var ErrMonitorNotFound = errors.New("monitor not found")
+
 type inMemoryRepository struct {
 	mu       sync.RWMutex
 	monitors map[string]*Monitor
+	results  map[string][]*CheckResult
+	resultID int64
+	queue    *runQueue
+	leased   map[string]bool
 }
 
 // NewRepository creates a new in-memory monitor repository
 func NewRepository() Repository {
 	return &inMemoryRepository{
 		monitors: make(map[string]*Monitor),
+		results:  make(map[string][]*CheckResult),
+		queue:    newRunQueue(),
+		leased:   make(map[string]bool),
 	}
 }
 
@@ -33,6 +73,16 @@ func cloneMonitor(m *Monitor) *Monitor {
 		return nil
 	}
 	clone := *m
+	clone.Tags = append([]string(nil), m.Tags...)
+	clone.Assertions = append([]Assertion(nil), m.Assertions...)
+	clone.DNS.Expected = append([]string(nil), m.DNS.Expected...)
+	clone.Answers = append([]string(nil), m.Answers...)
+	clone.Steps = append([]Step(nil), m.Steps...)
+	clone.Locations = append([]string(nil), m.Locations...)
+	clone.Request.Headers = make(map[string]string, len(m.Request.Headers))
+	for k, v := range m.Request.Headers {
+		clone.Request.Headers[k] = v
+	}
 	return &clone
 }
 
@@ -44,10 +94,22 @@ func (r *inMemoryRepository) Add(ctx context.Context, m *Monitor) error {
 		return errors.New("monitor already exists")
 	}
 
-	r.monitors[m.ID] = cloneMonitor(m)
+	stored := cloneMonitor(m)
+	r.monitors[m.ID] = stored
+	r.reschedule(stored)
 	return nil
 }
 
+// reschedule keeps the run queue in line with a monitor; paused and leased monitors are left
+// out until resumed or released
+func (r *inMemoryRepository) reschedule(m *Monitor) {
+	if m.IsPaused || m.LeaseOwner != "" {
+		r.queue.remove(m.ID)
+		return
+	}
+	r.queue.schedule(m.ID, m.NextRunAt)
+}
+
 func (r *inMemoryRepository) List(ctx context.Context, userID string) ([]*Monitor, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -72,33 +134,278 @@ func (r *inMemoryRepository) GetAll(ctx context.Context) ([]*Monitor, error) {
 	return result, nil
 }
 
-func (r *inMemoryRepository) UpdateStatus(ctx context.Context, id string, lastChecked time.Time, statusCode int, responseTime time.Duration, isHealthy bool, aiExplanation string) error {
+func (r *inMemoryRepository) GetByID(ctx context.Context, id string) (*Monitor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	m, exists := r.monitors[id]
+	if !exists {
+		return nil, ErrMonitorNotFound
+	}
+	return cloneMonitor(m), nil
+}
+
+func (r *inMemoryRepository) GetByPingToken(ctx context.Context, token string) (*Monitor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, m := range r.monitors {
+		if token != "" && m.PingToken == token {
+			return cloneMonitor(m), nil
+		}
+	}
+	return nil, ErrMonitorNotFound
+}
+
+// Update persists the user-editable configuration of a monitor, leaving check state untouched
+func (r *inMemoryRepository) Update(ctx context.Context, m *Monitor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, exists := r.monitors[m.ID]
+	if !exists {
+		return ErrMonitorNotFound
+	}
+
+	existing.URL = m.URL
+	existing.Interval = m.Interval
+	existing.IsPaused = m.IsPaused
+	existing.Tags = append([]string(nil), m.Tags...)
+	existing.Request = cloneMonitor(m).Request
+	existing.Assertions = append([]Assertion(nil), m.Assertions...)
+	existing.TLS = m.TLS
+	existing.TCP = m.TCP
+	existing.DNS = cloneMonitor(m).DNS
+	existing.Heartbeat = m.Heartbeat
+	existing.Steps = append([]Step(nil), m.Steps...)
+	existing.GRPC = m.GRPC
+	existing.WebSocket = m.WebSocket
+	existing.Locations = append([]string(nil), m.Locations...)
+	existing.Quorum = m.Quorum
+	existing.FailureThreshold = m.FailureThreshold
+	existing.RecoveryThreshold = m.RecoveryThreshold
+	existing.RetryCount = m.RetryCount
+	existing.RetryBackoff = m.RetryBackoff
+	existing.NextRunAt = m.NextRunAt
+	r.reschedule(existing)
+	return nil
+}
+
+func (r *inMemoryRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.monitors[id]; !exists {
+		return ErrMonitorNotFound
+	}
+
+	delete(r.monitors, id)
+	delete(r.results, id)
+	delete(r.leased, id)
+	r.queue.remove(id)
+	return nil
+}
+
+func (r *inMemoryRepository) UpdateStatus(ctx context.Context, id string, update StatusUpdate) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	m, exists := r.monitors[id]
 	if !exists {
-		return errors.New("monitor not found")
+		return ErrMonitorNotFound
 	}
 
-	m.LastChecked = lastChecked
-	m.StatusCode = statusCode
-	m.ResponseTime = responseTime
-	m.IsHealthy = isHealthy
-	m.AIExplanation = aiExplanation
+	m.applyStatus(update)
+	r.reschedule(m)
 
 	return nil
 }
 
-func (r *inMemoryRepository) SetRunning(ctx context.Context, id string, isRunning bool) error {
+// ClaimDue pops due monitors off the run queue. Leased monitors are kept out of the queue, so
+// expired leases are picked up through ReclaimLeases instead.
+func (r *inMemoryRepository) ClaimDue(ctx context.Context, owner string, now, expiresAt time.Time, limit int) ([]*Monitor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var claimed []*Monitor
+	for len(claimed) < limit {
+		entry, ok := r.queue.popDue(now)
+		if !ok {
+			break
+		}
+		m := r.monitors[entry.id]
+		m.LeaseOwner = owner
+		m.LeaseExpiresAt = expiresAt
+		r.leased[m.ID] = true
+		claimed = append(claimed, cloneMonitor(m))
+	}
+	return claimed, nil
+}
+
+func (r *inMemoryRepository) Claim(ctx context.Context, id, owner string, now, expiresAt time.Time) (*Monitor, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	m, exists := r.monitors[id]
 	if !exists {
-		return errors.New("monitor not found")
+		return nil, ErrMonitorNotFound
+	}
+	if m.Leased(now) {
+		return nil, ErrLeaseHeld
 	}
 
-	m.IsRunning = isRunning
+	m.LeaseOwner = owner
+	m.LeaseExpiresAt = expiresAt
+	r.leased[m.ID] = true
+	r.reschedule(m)
+	return cloneMonitor(m), nil
+}
+
+func (r *inMemoryRepository) Release(ctx context.Context, id, owner string, expiresAt, nextRunAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, exists := r.monitors[id]
+	if !exists {
+		return ErrMonitorNotFound
+	}
+	if m.LeaseOwner != owner || !m.LeaseExpiresAt.Equal(expiresAt) {
+		return ErrLeaseLost
+	}
+
+	m.NextRunAt = nextRunAt
+	r.endLease(m)
 	return nil
 }
+
+func (r *inMemoryRepository) ReclaimLeases(ctx context.Context, now time.Time, owner string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []string
+	for id := range r.leased {
+		m := r.monitors[id]
+		if !m.Leased(now) || (owner != "" && m.LeaseOwner == owner) {
+			r.endLease(m)
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (r *inMemoryRepository) ListLeased(ctx context.Context) ([]*Monitor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*Monitor
+	for id := range r.leased {
+		result = append(result, cloneMonitor(r.monitors[id]))
+	}
+	return result, nil
+}
+
+func (r *inMemoryRepository) ForceRelease(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, exists := r.monitors[id]
+	if !exists {
+		return ErrMonitorNotFound
+	}
+
+	r.endLease(m)
+	return nil
+}
+
+// endLease clears a monitor's lease and puts it back on the run queue
+func (r *inMemoryRepository) endLease(m *Monitor) {
+	m.LeaseOwner = ""
+	m.LeaseExpiresAt = time.Time{}
+	delete(r.leased, m.ID)
+	r.reschedule(m)
+}
+
+func (r *inMemoryRepository) SetStarted(ctx context.Context, id string, at, nextRunAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, exists := r.monitors[id]
+	if !exists {
+		return ErrMonitorNotFound
+	}
+
+	m.StartedAt = at
+	m.NextRunAt = nextRunAt
+	r.reschedule(m)
+	return nil
+}
+
+func (r *inMemoryRepository) AddResult(ctx context.Context, result *CheckResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.monitors[result.MonitorID]; !exists {
+		return ErrMonitorNotFound
+	}
+
+	r.resultID++
+	result.ID = r.resultID
+	clone := *result
+	r.results[result.MonitorID] = append(r.results[result.MonitorID], &clone)
+	return nil
+}
+
+func (r *inMemoryRepository) ListResults(ctx context.Context, monitorID string, q ResultQuery) ([]*CheckResult, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Results are appended in check order, so walking backwards yields newest first
+	history := r.results[monitorID]
+	var page []*CheckResult
+	total := 0
+	for i := len(history) - 1; i >= 0; i-- {
+		if !q.matches(history[i]) {
+			continue
+		}
+		if total >= q.Offset && (q.Limit <= 0 || len(page) < q.Limit) {
+			clone := *history[i]
+			page = append(page, &clone)
+		}
+		total++
+	}
+	return page, total, nil
+}
+
+func (r *inMemoryRepository) ResultStats(ctx context.Context, monitorID string, from, to time.Time) (Stats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	q := ResultQuery{From: from, To: to}
+	var results []*CheckResult
+	for _, res := range r.results[monitorID] {
+		if q.matches(res) {
+			results = append(results, res)
+		}
+	}
+	return computeStats(results), nil
+}
+
+func (r *inMemoryRepository) PruneResults(ctx context.Context, before time.Time, limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for monitorID, history := range r.results {
+		kept := history[:0]
+		for _, res := range history {
+			if deleted < limit && res.CheckedAt.Before(before) {
+				deleted++
+				continue
+			}
+			kept = append(kept, res)
+		}
+		clear(history[len(kept):])
+		r.results[monitorID] = kept
+	}
+	return deleted, nil
+}
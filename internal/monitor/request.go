@@ -0,0 +1,101 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults applied to HTTP checks that do not configure a request spec
+const (
+	defaultMethod         = http.MethodGet
+	defaultTimeout        = 10 * time.Second
+	maxCheckTimeout       = 60 * time.Second
+	defaultExpectedStatus = "200-399"
+)
+
+// ErrInvalidCheck is returned when a monitor's check definition cannot be used; the wrapped
+// message names the offending field
+var ErrInvalidCheck = errors.New("invalid check definition")
+
+// HTTPRequest describes the request sent by an HTTP check and which status codes count as healthy
+type HTTPRequest struct {
+	Method          string            `json:"method"`
+	Headers         map[string]string `json:"headers,omitempty"`
+	Body            string            `json:"body,omitempty"`
+	Timeout         time.Duration     `json:"timeout"`
+	FollowRedirects bool              `json:"follow_redirects"`
+	ExpectedStatus  string            `json:"expected_status"`
+}
+
+// defaultHTTPRequest is the spec of monitors created without one: a bare GET following redirects
+func defaultHTTPRequest() HTTPRequest {
+	return HTTPRequest{
+		Method:          defaultMethod,
+		Timeout:         defaultTimeout,
+		FollowRedirects: true,
+		ExpectedStatus:  defaultExpectedStatus,
+	}
+}
+
+func (r HTTPRequest) method() string {
+	if r.Method == "" {
+		return defaultMethod
+	}
+	return r.Method
+}
+
+func (r HTTPRequest) timeout() time.Duration {
+	if r.Timeout <= 0 {
+		return defaultTimeout
+	}
+	return r.Timeout
+}
+
+// expected returns the parsed healthy status set, falling back to the default range for
+// specs persisted before it was configurable
+func (r HTTPRequest) expected() statusSet {
+	set, err := parseStatusSet(r.ExpectedStatus)
+	if err != nil {
+		set, _ = parseStatusSet(defaultExpectedStatus)
+	}
+	return set
+}
+
+// statusSet is a union of inclusive status code ranges, written as e.g. "200-299,301,404"
+type statusSet [][2]int
+
+func parseStatusSet(s string) (statusSet, error) {
+	var set statusSet
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		first, last, isRange := strings.Cut(part, "-")
+		lo, err := strconv.Atoi(strings.TrimSpace(first))
+		if err != nil {
+			return nil, fmt.Errorf("%w: expected_status %q is not a list of codes or ranges", ErrInvalidCheck, s)
+		}
+		hi := lo
+		if isRange {
+			if hi, err = strconv.Atoi(strings.TrimSpace(last)); err != nil {
+				return nil, fmt.Errorf("%w: expected_status %q is not a list of codes or ranges", ErrInvalidCheck, s)
+			}
+		}
+		if lo < 100 || hi > 599 || lo > hi {
+			return nil, fmt.Errorf("%w: expected_status %q must use codes between 100 and 599", ErrInvalidCheck, s)
+		}
+		set = append(set, [2]int{lo, hi})
+	}
+	return set, nil
+}
+
+func (s statusSet) contains(code int) bool {
+	for _, r := range s {
+		if code >= r[0] && code <= r[1] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package monitor
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// pruneInterval is how often results older than the retention period are deleted
+const pruneInterval = time.Hour
+
+// pruneBatch caps the results deleted per statement
+const pruneBatch = 10000
+
+// Pruner periodically deletes check results older than the retention period, so that history
+// stays bounded
+type Pruner struct {
+	repo      Repository
+	logger    *zap.Logger
+	retention time.Duration
+	leader    LeaderChecker
+}
+
+// NewPruner creates a pruner keeping results for retention
+func NewPruner(repo Repository, logger *zap.Logger, retention time.Duration) *Pruner {
+	return &Pruner{repo: repo, logger: logger, retention: retention}
+}
+
+// SetLeadership makes the pruner run only while this node leads the cluster; call before Start
+func (p *Pruner) SetLeadership(leader LeaderChecker) {
+	p.leader = leader
+}
+
+// Start prunes once and then every pruneInterval
+func (p *Pruner) Start(ctx context.Context) {
+	p.logger.Info("Starting result pruner", zap.Duration("retention", p.retention))
+	ticker := time.NewTicker(pruneInterval)
+
+	go func() {
+		defer ticker.Stop()
+		defer func() {
+			if r := recover(); r != nil {
+				p.logger.Error("Result pruner panic recovered", zap.Any("panic", r))
+			}
+		}()
+		for {
+			if p.leader == nil || p.leader.IsLeader() {
+				p.prune(ctx, time.Now())
+			}
+			select {
+			case <-ctx.Done():
+				p.logger.Info("Stopping result pruner")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// prune deletes results checked before now minus the retention period, batch by batch
+func (p *Pruner) prune(ctx context.Context, now time.Time) {
+	before := now.Add(-p.retention)
+	total := 0
+	for ctx.Err() == nil {
+		deleted, err := p.repo.PruneResults(ctx, before, pruneBatch)
+		if err != nil {
+			p.logger.Error("Failed to prune check results", zap.Error(err))
+			return
+		}
+		total += deleted
+		if deleted < pruneBatch {
+			break
+		}
+	}
+	if total > 0 {
+		p.logger.Info("Pruned check results", zap.Int("deleted", total), zap.Time("before", before))
+	}
+}
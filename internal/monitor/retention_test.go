@@ -0,0 +1,72 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestPrunerDeletesExpiredResults(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository()
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	ages := map[string][]time.Duration{
+		"a": {40 * 24 * time.Hour, 31 * 24 * time.Hour, 29 * 24 * time.Hour, time.Hour},
+		"b": {60 * 24 * time.Hour, 10 * time.Minute},
+	}
+	for id, checks := range ages {
+		if err := repo.Add(ctx, &Monitor{ID: id, UserID: "u", Interval: time.Minute}); err != nil {
+			t.Fatalf("add %s: %v", id, err)
+		}
+		for _, age := range checks {
+			if err := repo.AddResult(ctx, &CheckResult{MonitorID: id, CheckedAt: now.Add(-age), IsHealthy: true}); err != nil {
+				t.Fatalf("add result: %v", err)
+			}
+		}
+	}
+
+	p := NewPruner(repo, zap.NewNop(), 30*24*time.Hour)
+	p.prune(ctx, now)
+
+	for id, want := range map[string]int{"a": 2, "b": 1} {
+		results, total, err := repo.ListResults(ctx, id, ResultQuery{})
+		if err != nil {
+			t.Fatalf("list %s: %v", id, err)
+		}
+		if total != want {
+			t.Errorf("monitor %s kept %d results, want %d", id, total, want)
+		}
+		for _, r := range results {
+			if r.CheckedAt.Before(now.Add(-30 * 24 * time.Hour)) {
+				t.Errorf("monitor %s kept a result from %s", id, r.CheckedAt)
+			}
+		}
+	}
+}
+
+func TestPruneResultsLimit(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository()
+	now := time.Now()
+	if err := repo.Add(ctx, &Monitor{ID: "m", UserID: "u", Interval: time.Minute}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := repo.AddResult(ctx, &CheckResult{MonitorID: "m", CheckedAt: now.Add(-time.Duration(10-i) * time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, want := range []int{2, 2, 1, 0} {
+		deleted, err := repo.PruneResults(ctx, now, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deleted != want {
+			t.Fatalf("deleted = %d, want %d", deleted, want)
+		}
+	}
+}
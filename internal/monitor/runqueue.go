@@ -0,0 +1,75 @@
+package monitor
+
+import (
+	"container/heap"
+	"time"
+)
+
+// runQueue is a min-heap of monitor IDs ordered by their next run, used by the in-memory
+// repository to claim due monitors without scanning all of them. It is not safe for concurrent
+// use; the repository guards it with its own lock.
+type runQueue struct {
+	entries []runEntry
+	index   map[string]int
+}
+
+type runEntry struct {
+	id string
+	at time.Time
+}
+
+func newRunQueue() *runQueue {
+	return &runQueue{index: make(map[string]int)}
+}
+
+// schedule sets when a monitor is next due, adding it if needed; a zero time removes it
+func (q *runQueue) schedule(id string, at time.Time) {
+	if at.IsZero() {
+		q.remove(id)
+		return
+	}
+	if i, ok := q.index[id]; ok {
+		q.entries[i].at = at
+		heap.Fix(q, i)
+		return
+	}
+	heap.Push(q, runEntry{id: id, at: at})
+}
+
+func (q *runQueue) remove(id string) {
+	if i, ok := q.index[id]; ok {
+		heap.Remove(q, i)
+	}
+}
+
+// popDue removes and returns the earliest entry if it is due at now
+func (q *runQueue) popDue(now time.Time) (runEntry, bool) {
+	if len(q.entries) == 0 || q.entries[0].at.After(now) {
+		return runEntry{}, false
+	}
+	return heap.Pop(q).(runEntry), true
+}
+
+// heap.Interface; use the methods above instead
+
+func (q *runQueue) Len() int           { return len(q.entries) }
+func (q *runQueue) Less(i, j int) bool { return q.entries[i].at.Before(q.entries[j].at) }
+
+func (q *runQueue) Swap(i, j int) {
+	q.entries[i], q.entries[j] = q.entries[j], q.entries[i]
+	q.index[q.entries[i].id] = i
+	q.index[q.entries[j].id] = j
+}
+
+func (q *runQueue) Push(x interface{}) {
+	e := x.(runEntry)
+	q.index[e.id] = len(q.entries)
+	q.entries = append(q.entries, e)
+}
+
+func (q *runQueue) Pop() interface{} {
+	last := q.entries[len(q.entries)-1]
+	q.entries = q.entries[:len(q.entries)-1]
+	delete(q.index, last.id)
+	return last
+}
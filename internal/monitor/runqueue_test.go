@@ -0,0 +1,80 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRunQueue(t *testing.T) {
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	type entry struct {
+		id     string
+		offset time.Duration
+		remove bool
+	}
+
+	tests := []struct {
+		name     string
+		schedule []entry
+		now      time.Duration
+		want     []string
+	}{
+		{
+			name:     "due in next-run order",
+			schedule: []entry{{id: "c", offset: 3}, {id: "a", offset: 1}, {id: "b", offset: 2}},
+			now:      3,
+			want:     []string{"a", "b", "c"},
+		},
+		{
+			name:     "not yet due stays queued",
+			schedule: []entry{{id: "a", offset: 1}, {id: "later", offset: 10}},
+			now:      5,
+			want:     []string{"a"},
+		},
+		{
+			name:     "rescheduling moves an entry",
+			schedule: []entry{{id: "a", offset: 1}, {id: "b", offset: 2}, {id: "a", offset: 3}},
+			now:      3,
+			want:     []string{"b", "a"},
+		},
+		{
+			name:     "zero time removes an entry",
+			schedule: []entry{{id: "a", offset: 1}, {id: "b", offset: 2}, {id: "a", remove: true}},
+			now:      3,
+			want:     []string{"b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newRunQueue()
+			for _, e := range tt.schedule {
+				var at time.Time
+				if !e.remove {
+					at = base.Add(e.offset * time.Second)
+				}
+				q.schedule(e.id, at)
+			}
+
+			var got []string
+			for {
+				e, ok := q.popDue(base.Add(tt.now * time.Second))
+				if !ok {
+					break
+				}
+				got = append(got, e.id)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("popped %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("popped %v, want %v", got, tt.want)
+				}
+			}
+			if len(q.index) != q.Len() {
+				t.Errorf("index has %d entries for %d queued", len(q.index), q.Len())
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package monitor
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
@@ -13,8 +14,21 @@ type Scheduler struct {
 	workerPool *WorkerPool
 	logger     *zap.Logger
 	interval   int
+	maint      MaintenanceChecker
+	leader     LeaderChecker
+	owner      string
+	leaseTTL   time.Duration
+	stats      schedulerStats
 }
 
+// LeaderChecker reports whether this node leads the cluster; only the leader schedules checks
+type LeaderChecker interface {
+	IsLeader() bool
+}
+
+// claimLimit bounds the monitors claimed per tick; the rest stay due for the next tick
+const claimLimit = 500
+
 // NewScheduler creates a new monitor scheduler
 func NewScheduler(repo Repository, workerPool *WorkerPool, logger *zap.Logger, interval int) *Scheduler {
 	return &Scheduler{
@@ -22,12 +36,34 @@ func NewScheduler(repo Repository, workerPool *WorkerPool, logger *zap.Logger, i
 		workerPool: workerPool,
 		logger:     logger,
 		interval:   interval,
+		leaseTTL:   DefaultLeaseTTL,
 	}
 }
 
-// Start begins ticking processing routines that loop over monitors to queue due jobs
+// SetLeadership makes the scheduler claim monitors only while this node leads the cluster; call
+// before Start. Without it every node schedules, which leases keep from checking a monitor twice.
+func (s *Scheduler) SetLeadership(leader LeaderChecker) {
+	s.leader = leader
+}
+
+// SetLease configures the owner recorded on claimed monitors and how long a claim lasts before
+// other nodes may take the monitor over; call before Start
+func (s *Scheduler) SetLease(owner string, ttl time.Duration) {
+	s.owner = owner
+	s.leaseTTL = ttl
+}
+
+// SetMaintenance configures the checker used to skip monitors inside a maintenance window;
+// call before Start
+func (s *Scheduler) SetMaintenance(checker MaintenanceChecker) {
+	s.maint = checker
+}
+
+// Start begins ticking processing routines that claim due monitors and queue their jobs. Leases
+// this node still holds from before a restart are released first, since their checks are gone.
 func (s *Scheduler) Start(ctx context.Context) {
-	s.logger.Info("Starting monitor scheduler", zap.Int("interval_seconds", s.interval))
+	s.logger.Info("Starting monitor scheduler", zap.Int("interval_seconds", s.interval), zap.String("owner", s.owner))
+	s.reclaimLeases(ctx, s.owner)
 	ticker := time.NewTicker(time.Duration(s.interval) * time.Second)
 
 	go func() {
@@ -43,25 +79,192 @@ func (s *Scheduler) Start(ctx context.Context) {
 				s.logger.Info("Stopping monitor scheduler")
 				return
 			case <-ticker.C:
+				if s.leader != nil && !s.leader.IsLeader() {
+					continue
+				}
+				s.reclaimLeases(ctx, "")
 				s.queueDueMonitors(ctx)
 			}
 		}
 	}()
 }
 
+// queueDueMonitors claims as many due monitors as the worker pool has room for, most overdue
+// first, and submits a job for each. Monitors inside a skipping maintenance window are pushed back
+// by their interval so that they do not keep the head of the due order; those the pool could not
+// take after all are released as they were and reconsidered on the next tick.
 func (s *Scheduler) queueDueMonitors(ctx context.Context) {
-	monitors, err := s.repo.GetAll(ctx)
+	now := time.Now()
+	limit := min(claimLimit, s.workerPool.Free())
+	if limit == 0 {
+		s.stats.saturated(now)
+		return
+	}
+	monitors, err := s.repo.ClaimDue(ctx, s.owner, now, now.Add(s.leaseTTL), limit)
 	if err != nil {
-		s.logger.Error("Failed to get monitors for scheduling", zap.Error(err))
+		s.logger.Error("Failed to claim due monitors", zap.Error(err))
 		return
 	}
 
-	now := time.Now()
+	due := make([]*Monitor, 0, len(monitors))
 	for _, m := range monitors {
-		if !m.IsRunning && now.Sub(m.LastChecked) >= m.Interval {
-			if err := s.repo.SetRunning(ctx, m.ID, true); err == nil {
-				s.workerPool.Submit(Job{Monitor: m})
+		if maintenanceMode(ctx, s.maint, m, now) == MaintenanceSkip {
+			s.release(ctx, m, now.Add(m.Interval))
+			continue
+		}
+		due = append(due, m)
+	}
+	s.stats.observe(now, due)
+
+	for i, m := range due {
+		if !s.workerPool.Submit(Job{Monitor: m}) {
+			// Pings took the room in the meantime; the rest would not fit either
+			for _, rest := range due[i:] {
+				s.release(ctx, rest, rest.NextRunAt)
 			}
+			s.stats.requeued(len(due) - i)
+			s.logger.Warn("Worker pool is saturated, requeued claimed monitors", zap.Int("count", len(due)-i))
+			return
 		}
 	}
 }
+
+// release gives up a claim without checking the monitor, making it due again at nextRunAt
+func (s *Scheduler) release(ctx context.Context, m *Monitor, nextRunAt time.Time) {
+	if err := s.repo.Release(ctx, m.ID, s.owner, m.LeaseExpiresAt, nextRunAt); err != nil {
+		s.logger.Warn("Failed to release monitor", zap.Error(err), zap.String("monitor_id", m.ID))
+	}
+}
+
+// reclaimLeases releases expired leases, and those held by owner when it is not empty, so the
+// monitors of a crashed or hung node become due again
+func (s *Scheduler) reclaimLeases(ctx context.Context, owner string) {
+	ids, err := s.repo.ReclaimLeases(ctx, time.Now(), owner)
+	if err != nil {
+		s.logger.Error("Failed to reclaim monitor leases", zap.Error(err))
+		return
+	}
+	if len(ids) > 0 {
+		s.logger.Warn("Reclaimed stale monitor leases", zap.Int("count", len(ids)), zap.Strings("monitor_ids", ids))
+		s.stats.reclaimed(len(ids))
+	}
+}
+
+// ReclaimLeasesOf releases the leases held by the given nodes, e.g. ones that left the cluster
+// while checks were in flight, so that a new leader need not wait for them to expire
+func (s *Scheduler) ReclaimLeasesOf(ctx context.Context, owners []string) {
+	for _, owner := range owners {
+		s.reclaimLeases(ctx, owner)
+	}
+}
+
+// ReleaseLeases hands this node's claims back so that another node can check the monitors right
+// away; call on shutdown once ctx passed to Start is done
+func (s *Scheduler) ReleaseLeases() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.reclaimLeases(ctx, s.owner)
+}
+
+// Stats returns the scheduler's claim and drift metrics
+func (s *Scheduler) Stats() SchedulerStats {
+	return s.stats.snapshot()
+}
+
+// Leases returns the monitors currently claimed by any node
+func (s *Scheduler) Leases(ctx context.Context) ([]*Monitor, error) {
+	return s.repo.ListLeased(ctx)
+}
+
+// ForceRelease ends a monitor's lease so it is claimed again once due, e.g. when a check is stuck
+// on a node that is still alive. A check still running for it records its result as usual.
+func (s *Scheduler) ForceRelease(ctx context.Context, id string) error {
+	if err := s.repo.ForceRelease(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Warn("Monitor lease force-released", zap.String("monitor_id", id))
+	return nil
+}
+
+// SchedulerStats measures how many monitors each tick claims and how late they are claimed.
+// Drift is the delay between a monitor's next run and the tick that claimed it. Monitors skipped
+// for maintenance are not counted, and monitors never checked before are left out of drift.
+type SchedulerStats struct {
+	Ticks        int64         `json:"ticks"`
+	LastTickAt   time.Time     `json:"last_tick_at"`
+	LastClaimed  int           `json:"last_claimed"`
+	MaxClaimed   int           `json:"max_claimed"`
+	TotalClaimed int64         `json:"total_claimed"`
+	LastMaxDrift time.Duration `json:"last_max_drift"`
+	MaxDrift     time.Duration `json:"max_drift"`
+	AvgDrift     time.Duration `json:"avg_drift"`
+	// ReclaimedLeases counts leases released because they expired or were left by a restart
+	ReclaimedLeases int64 `json:"reclaimed_leases"`
+	// SaturatedTicks counts ticks that claimed nothing because the worker queue was full, and
+	// Requeued the monitors claimed but handed back when the pool could not take them
+	SaturatedTicks  int64     `json:"saturated_ticks"`
+	LastSaturatedAt time.Time `json:"last_saturated_at"`
+	Requeued        int64     `json:"requeued"`
+}
+
+type schedulerStats struct {
+	mu         sync.Mutex
+	stats      SchedulerStats
+	drifts     int64
+	driftTotal time.Duration
+}
+
+func (s *schedulerStats) observe(now time.Time, claimed []*Monitor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats.Ticks++
+	s.stats.LastTickAt = now
+	s.stats.LastClaimed = len(claimed)
+	s.stats.MaxClaimed = max(s.stats.MaxClaimed, len(claimed))
+	s.stats.TotalClaimed += int64(len(claimed))
+	s.stats.LastMaxDrift = 0
+	for _, m := range claimed {
+		if m.LastChecked.IsZero() {
+			continue
+		}
+		drift := max(now.Sub(m.NextRunAt), 0)
+		s.stats.LastMaxDrift = max(s.stats.LastMaxDrift, drift)
+		s.stats.MaxDrift = max(s.stats.MaxDrift, drift)
+		s.drifts++
+		s.driftTotal += drift
+	}
+}
+
+func (s *schedulerStats) reclaimed(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats.ReclaimedLeases += int64(n)
+}
+
+func (s *schedulerStats) saturated(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats.SaturatedTicks++
+	s.stats.LastSaturatedAt = now
+}
+
+func (s *schedulerStats) requeued(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats.Requeued += int64(n)
+}
+
+func (s *schedulerStats) snapshot() SchedulerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.stats
+	if s.drifts > 0 {
+		stats.AvgDrift = s.driftTotal / time.Duration(s.drifts)
+	}
+	return stats
+}
@@ -2,23 +2,164 @@ package monitor
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
 	"time"
 )
 
+// ErrForbidden is returned when a user acts on a monitor they do not own
+var ErrForbidden = errors.New("monitor belongs to another user")
+
 // AddReq defines the payload for adding a new monitor
 type AddReq struct {
-	URL      string `json:"url" binding:"required,url"`
-	Interval int    `json:"interval" binding:"required,min=10"` // in seconds
+	Type              MonitorType    `json:"type" binding:"omitempty,oneof=http tls tcp dns heartbeat transaction grpc websocket"`
+	URL               string         `json:"url" binding:"max=2048"`             // a URL, host:port or name by type; an optional label for heartbeat and transaction
+	Interval          int            `json:"interval" binding:"required,min=10"` // in seconds
+	FailureThreshold  int            `json:"failure_threshold" binding:"omitempty,min=1,max=10"`
+	RecoveryThreshold int            `json:"recovery_threshold" binding:"omitempty,min=1,max=10"`
+	RetryCount        int            `json:"retry_count" binding:"omitempty,min=0,max=5"`
+	RetryBackoff      int            `json:"retry_backoff" binding:"omitempty,min=0,max=30000"` // in milliseconds
+	Tags              []string       `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
+	Request           *RequestReq    `json:"request"`
+	Assertions        []AssertionReq `json:"assertions" binding:"omitempty,max=20,dive"`
+	TLS               *TLSReq        `json:"tls"`
+	TCP               *TCPReq        `json:"tcp"`
+	DNS               *DNSReq        `json:"dns"`
+	Heartbeat         *HeartbeatReq  `json:"heartbeat"`
+	Steps             []StepReq      `json:"steps" binding:"omitempty,max=10,dive"`
+	GRPC              *GRPCReq       `json:"grpc"`
+	WebSocket         *WebSocketReq  `json:"websocket"`
+
+	Locations []string `json:"locations" binding:"omitempty,max=10,dive,min=1,max=50"` // probe agent locations; empty checks locally
+	Quorum    int      `json:"quorum" binding:"omitempty,min=1,max=10"`                // failing locations needed for down, a majority by default
+}
+
+// UpdateReq defines the payload for partially updating a monitor; omitted fields are left unchanged
+type UpdateReq struct {
+	URL               *string         `json:"url" binding:"omitempty,max=2048"`
+	Interval          *int            `json:"interval" binding:"omitempty,min=10"` // in seconds
+	FailureThreshold  *int            `json:"failure_threshold" binding:"omitempty,min=1,max=10"`
+	RecoveryThreshold *int            `json:"recovery_threshold" binding:"omitempty,min=1,max=10"`
+	RetryCount        *int            `json:"retry_count" binding:"omitempty,min=0,max=5"`
+	RetryBackoff      *int            `json:"retry_backoff" binding:"omitempty,min=0,max=30000"` // in milliseconds
+	Tags              *[]string       `json:"tags" binding:"omitempty,max=20,dive,min=1,max=50"`
+	Request           *RequestReq     `json:"request"`                                    // replaces the whole request spec; masked values keep the stored ones
+	Assertions        *[]AssertionReq `json:"assertions" binding:"omitempty,max=20,dive"` // replaces all assertions
+	TLS               *TLSReq         `json:"tls"`                                        // replaces the TLS settings
+	TCP               *TCPReq         `json:"tcp"`                                        // replaces the TCP settings
+	DNS               *DNSReq         `json:"dns"`                                        // replaces the DNS settings
+	Heartbeat         *HeartbeatReq   `json:"heartbeat"`                                  // replaces the heartbeat settings
+	Steps             *[]StepReq      `json:"steps" binding:"omitempty,max=10,dive"`      // replaces all transaction steps, masked values as for Request
+	GRPC              *GRPCReq        `json:"grpc"`                                       // replaces the gRPC settings
+	WebSocket         *WebSocketReq   `json:"websocket"`                                  // replaces the WebSocket exchange
+
+	Locations *[]string `json:"locations" binding:"omitempty,max=10,dive,min=1,max=50"` // replaces the locations; empty checks locally
+	Quorum    *int      `json:"quorum" binding:"omitempty,min=0,max=10"`                // 0 restores the majority default
+}
+
+// RequestReq defines the HTTP request sent by a check; omitted fields take the defaults of a
+// bare GET with a 10s timeout that follows redirects and accepts 200-399
+type RequestReq struct {
+	Method          string            `json:"method" binding:"omitempty,oneof=GET HEAD POST PUT PATCH DELETE OPTIONS"`
+	Headers         map[string]string `json:"headers" binding:"omitempty,max=50"`
+	Body            string            `json:"body" binding:"omitempty,max=65536"`
+	Timeout         int               `json:"timeout" binding:"omitempty,min=100,max=60000"` // in milliseconds
+	FollowRedirects *bool             `json:"follow_redirects"`
+	ExpectedStatus  string            `json:"expected_status" binding:"omitempty,max=200"`
+}
+
+// TLSReq defines certificate inspection settings; omitted fields take the defaults of a 14 day
+// expiry warning and the target's own host name
+type TLSReq struct {
+	ExpiryWarnDays *int   `json:"expiry_warn_days" binding:"omitempty,min=0,max=365"` // 0 disables the warning
+	ServerName     string `json:"server_name" binding:"omitempty,max=253"`
+}
+
+// TCPReq defines the optional exchange of a tcp monitor; control characters such as \r\n are
+// written as JSON string escapes
+type TCPReq struct {
+	Send   string `json:"send" binding:"omitempty,max=4096"`
+	Expect string `json:"expect" binding:"omitempty,max=1024"`
+}
+
+// DNSReq defines what a dns monitor resolves and expects; the record type defaults to A and the
+// resolver to the system one
+type DNSReq struct {
+	RecordType string   `json:"record_type" binding:"omitempty,oneof=A AAAA CNAME MX TXT"`
+	Resolver   string   `json:"resolver" binding:"omitempty,max=255"` // host[:port], port 53 by default
+	Expected   []string `json:"expected" binding:"omitempty,max=20,dive,min=1,max=255"`
+	Exact      bool     `json:"exact"`
+}
+
+// GRPCReq defines the health service a grpc monitor asks about; an empty service checks the
+// server as a whole, and TLS is verified using the tls settings
+type GRPCReq struct {
+	Service string `json:"service" binding:"omitempty,max=200"`
+	TLS     bool   `json:"tls"`
+}
+
+// WebSocketReq defines the optional exchange of a websocket monitor; Expect is a regular
+// expression matched against each message received until the timeout
+type WebSocketReq struct {
+	Send   string `json:"send" binding:"omitempty,max=4096"`
+	Expect string `json:"expect" binding:"omitempty,max=1024"`
+}
+
+// HeartbeatReq defines how late a heartbeat ping may be; the expected period is the interval
+type HeartbeatReq struct {
+	Grace int `json:"grace" binding:"omitempty,min=1,max=604800"` // in seconds, 5 minutes by default
+}
+
+// StepReq defines one request of a transaction; its URL, header values and body may reference
+// variables captured by earlier steps as {{name}}
+type StepReq struct {
+	Name       string         `json:"name" binding:"omitempty,max=100"`
+	URL        string         `json:"url" binding:"required,max=2048"`
+	Request    *RequestReq    `json:"request"`
+	Assertions []AssertionReq `json:"assertions" binding:"omitempty,max=20,dive"`
+	Captures   []CaptureReq   `json:"captures" binding:"omitempty,max=10,dive"`
+}
+
+// CaptureReq defines a variable extracted from a step's response; see Capture for Expr
+type CaptureReq struct {
+	Name   string `json:"name" binding:"required,max=50"`
+	Source string `json:"source" binding:"required,oneof=json_path regex header"`
+	Expr   string `json:"expr" binding:"required,max=500"`
+}
+
+// AssertionReq defines a condition the response must satisfy; see Assertion for field usage
+type AssertionReq struct {
+	Type     string          `json:"type" binding:"required,oneof=contains not_contains regex json_path json_schema header latency"`
+	Severity string          `json:"severity" binding:"omitempty,oneof=fail degrade"`
+	Name     string          `json:"name" binding:"omitempty,max=100"`
+	Path     string          `json:"path" binding:"omitempty,max=200"`
+	Op       string          `json:"op"`
+	Value    string          `json:"value" binding:"omitempty,max=1000"`
+	Schema   json.RawMessage `json:"schema"`
 }
 
 // Service defines business logic for monitors
 type Service interface {
 	Add(ctx context.Context, userID string, req AddReq) (*Monitor, error)
 	List(ctx context.Context, userID string) ([]*Monitor, error)
+	Get(ctx context.Context, userID, id string) (*Monitor, error)
+	Update(ctx context.Context, userID, id string, req UpdateReq) (*Monitor, error)
+	Delete(ctx context.Context, userID, id string) error
+	SetPaused(ctx context.Context, userID, id string, paused bool) (*Monitor, error)
+	Results(ctx context.Context, userID, id string, q ResultQuery) ([]*CheckResult, int, error)
+	Stats(ctx context.Context, userID, id string, q StatsQuery) (*Stats, error)
+	// Ping records a check-in from a job, identified by its heartbeat monitor's secret token
+	Ping(ctx context.Context, token string, ping Ping) error
+	// SetPingQueue configures where pings are queued to be recorded; call before serving
+	SetPingQueue(q PingQueue)
 }
 
 type serviceImpl struct {
-	repo Repository
+	repo  Repository
+	pings PingQueue
 }
 
 // NewService creates a new monitor service
@@ -27,13 +168,75 @@ func NewService(repo Repository) Service {
 }
 
 func (s *serviceImpl) Add(ctx context.Context, userID string, req AddReq) (*Monitor, error) {
+	typ := req.Type
+	if typ == "" {
+		typ = TypeHTTP
+	}
+	target, err := normalizeTarget(typ, req.URL)
+	if err != nil {
+		return nil, err
+	}
+	request, err := buildRequest(req.Request)
+	if err != nil {
+		return nil, err
+	}
+	assertions, err := buildAssertions(req.Assertions)
+	if err != nil {
+		return nil, err
+	}
+	dns, err := buildDNS(req.DNS)
+	if err != nil {
+		return nil, err
+	}
+	websocket, err := buildWebSocket(req.WebSocket)
+	if err != nil {
+		return nil, err
+	}
+	var steps []Step
+	if typ == TypeTransaction {
+		if steps, err = buildSteps(req.Steps); err != nil {
+			return nil, err
+		}
+	}
+
 	m := &Monitor{
-		ID:        generateID(),
-		UserID:    userID,
-		URL:       req.URL,
-		Interval:  time.Duration(req.Interval) * time.Second,
-		IsHealthy: false,
+		ID:                generateID(),
+		UserID:            userID,
+		Type:              typ,
+		URL:               target,
+		Interval:          time.Duration(req.Interval) * time.Second,
+		IsHealthy:         false,
+		Health:            HealthPending,
+		FailureThreshold:  atLeastOne(req.FailureThreshold),
+		RecoveryThreshold: atLeastOne(req.RecoveryThreshold),
+		RetryCount:        req.RetryCount,
+		RetryBackoff:      time.Duration(req.RetryBackoff) * time.Millisecond,
+		Tags:              req.Tags,
+		Request:           request,
+		Assertions:        assertions,
+		TLS:               buildTLS(req.TLS),
+		TCP:               buildTCP(req.TCP),
+		DNS:               dns,
+		Heartbeat:         buildHeartbeat(req.Heartbeat),
+		Steps:             steps,
+		GRPC:              buildGRPC(req.GRPC),
+		WebSocket:         websocket,
+		Locations:         req.Locations,
+		Quorum:            req.Quorum,
 	}
+	if err := validateLocations(m); err != nil {
+		return nil, err
+	}
+	if typ == TypeHeartbeat {
+		m.PingToken = generatePingToken()
+		if m.URL == "" {
+			m.URL = "heartbeat:" + m.ID
+		}
+	}
+	if typ == TypeTransaction && m.URL == "" {
+		m.URL = steps[0].URL
+	}
+	m.NextRunAt = m.nextRunAt()
 
 	if err := s.repo.Add(ctx, m); err != nil {
 		return nil, err
@@ -45,6 +248,361 @@ func (s *serviceImpl) List(ctx context.Context, userID string) ([]*Monitor, erro
 	return s.repo.List(ctx, userID)
 }
 
+func (s *serviceImpl) Get(ctx context.Context, userID, id string) (*Monitor, error) {
+	m, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if m.UserID != userID {
+		return nil, ErrForbidden
+	}
+	return m, nil
+}
+
+func (s *serviceImpl) Update(ctx context.Context, userID, id string, req UpdateReq) (*Monitor, error) {
+	m, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		if m.URL, err = normalizeTarget(m.Type, *req.URL); err != nil {
+			return nil, err
+		}
+	}
+	if req.Interval != nil {
+		m.Interval = time.Duration(*req.Interval) * time.Second
+	}
+	if req.FailureThreshold != nil {
+		m.FailureThreshold = *req.FailureThreshold
+	}
+	if req.RecoveryThreshold != nil {
+		m.RecoveryThreshold = *req.RecoveryThreshold
+	}
+	if req.RetryCount != nil {
+		m.RetryCount = *req.RetryCount
+	}
+	if req.RetryBackoff != nil {
+		m.RetryBackoff = time.Duration(*req.RetryBackoff) * time.Millisecond
+	}
+	if req.Tags != nil {
+		m.Tags = *req.Tags
+	}
+	if req.Request != nil {
+		request, err := buildRequest(req.Request)
+		if err != nil {
+			return nil, err
+		}
+		if err := restoreMasked(&request, m.Request); err != nil {
+			return nil, err
+		}
+		m.Request = request
+	}
+	if req.Assertions != nil {
+		if m.Assertions, err = buildAssertions(*req.Assertions); err != nil {
+			return nil, err
+		}
+	}
+	if req.TLS != nil {
+		m.TLS = buildTLS(req.TLS)
+	}
+	if req.TCP != nil {
+		m.TCP = buildTCP(req.TCP)
+	}
+	if req.Steps != nil && m.Type == TypeTransaction {
+		steps, err := buildSteps(*req.Steps)
+		if err != nil {
+			return nil, err
+		}
+		// Steps are replaced as a whole; masked values are taken from the step at the same position
+		for i := range steps {
+			var stored HTTPRequest
+			if i < len(m.Steps) {
+				stored = m.Steps[i].Request
+			}
+			if err := restoreMasked(&steps[i].Request, stored); err != nil {
+				return nil, fmt.Errorf("step %d: %w", i+1, err)
+			}
+		}
+		m.Steps = steps
+	}
+	if req.GRPC != nil {
+		m.GRPC = buildGRPC(req.GRPC)
+	}
+	if req.WebSocket != nil {
+		if m.WebSocket, err = buildWebSocket(req.WebSocket); err != nil {
+			return nil, err
+		}
+	}
+	if req.Heartbeat != nil {
+		m.Heartbeat = buildHeartbeat(req.Heartbeat)
+	}
+	if req.DNS != nil {
+		if m.DNS, err = buildDNS(req.DNS); err != nil {
+			return nil, err
+		}
+	}
+	if req.Locations != nil {
+		m.Locations = *req.Locations
+		if len(m.Locations) == 0 {
+			m.Quorum = 0
+		}
+	}
+	if req.Quorum != nil {
+		m.Quorum = *req.Quorum
+	}
+	if err := validateLocations(m); err != nil {
+		return nil, err
+	}
+	m.NextRunAt = m.nextRunAt()
+
+	if err := s.repo.Update(ctx, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (s *serviceImpl) Delete(ctx context.Context, userID, id string) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *serviceImpl) SetPaused(ctx context.Context, userID, id string, paused bool) (*Monitor, error) {
+	m, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	m.IsPaused = paused
+	m.NextRunAt = m.nextRunAt()
+	if err := s.repo.Update(ctx, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (s *serviceImpl) Results(ctx context.Context, userID, id string, q ResultQuery) ([]*CheckResult, int, error) {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.ListResults(ctx, id, q)
+}
+
+func (s *serviceImpl) Stats(ctx context.Context, userID, id string, q StatsQuery) (*Stats, error) {
+	from, to, err := q.resolve(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.repo.ResultStats(ctx, id, from, to)
+	if err != nil {
+		return nil, err
+	}
+	stats.Window = q.Window
+	stats.From = from
+	stats.To = to
+	return &stats, nil
+}
+
+// buildRequest turns a request payload into a spec, applying defaults for omitted fields
+func buildRequest(req *RequestReq) (HTTPRequest, error) {
+	spec := defaultHTTPRequest()
+	if req == nil {
+		return spec, nil
+	}
+
+	if req.Method != "" {
+		spec.Method = req.Method
+	}
+	if len(req.Headers) > 0 {
+		spec.Headers = make(map[string]string, len(req.Headers))
+		for k, v := range req.Headers {
+			spec.Headers[http.CanonicalHeaderKey(k)] = v
+		}
+	}
+	spec.Body = req.Body
+	if req.Timeout > 0 {
+		spec.Timeout = time.Duration(req.Timeout) * time.Millisecond
+	}
+	if req.FollowRedirects != nil {
+		spec.FollowRedirects = *req.FollowRedirects
+	}
+	if req.ExpectedStatus != "" {
+		if _, err := parseStatusSet(req.ExpectedStatus); err != nil {
+			return HTTPRequest{}, err
+		}
+		spec.ExpectedStatus = req.ExpectedStatus
+	}
+	return spec, nil
+}
+
+// restoreMasked puts the stored body and header values back where an update sent the masked
+// placeholder returned by reads, so that editing a monitor does not overwrite its secrets
+func restoreMasked(spec *HTTPRequest, stored HTTPRequest) error {
+	if spec.Body == maskedValue {
+		if stored.Body == "" {
+			return fmt.Errorf("%w: body is masked but none is stored", ErrInvalidCheck)
+		}
+		spec.Body = stored.Body
+	}
+	for k, v := range spec.Headers {
+		if v != maskedValue {
+			continue
+		}
+		value, ok := stored.Headers[k]
+		if !ok {
+			return fmt.Errorf("%w: header %s is masked but none is stored", ErrInvalidCheck, k)
+		}
+		spec.Headers[k] = value
+	}
+	return nil
+}
+
+// buildTLS turns TLS settings into a check, applying defaults for omitted fields
+func buildTLS(req *TLSReq) TLSCheck {
+	check := TLSCheck{ExpiryWarnDays: defaultExpiryWarnDays}
+	if req == nil {
+		return check
+	}
+	if req.ExpiryWarnDays != nil {
+		check.ExpiryWarnDays = *req.ExpiryWarnDays
+	}
+	check.ServerName = req.ServerName
+	return check
+}
+
+// buildTCP turns TCP exchange settings into a check
+func buildTCP(req *TCPReq) TCPCheck {
+	if req == nil {
+		return TCPCheck{}
+	}
+	return TCPCheck{Send: req.Send, Expect: req.Expect}
+}
+
+// buildDNS turns DNS settings into a check, resolving the resolver address to host:port
+func buildDNS(req *DNSReq) (DNSCheck, error) {
+	if req == nil {
+		return DNSCheck{}, nil
+	}
+	check := DNSCheck{RecordType: req.RecordType, Expected: req.Expected, Exact: req.Exact}
+	if req.Resolver != "" {
+		resolver, err := hostPort(req.Resolver, "53")
+		if err != nil {
+			return DNSCheck{}, err
+		}
+		check.Resolver = resolver
+	}
+	return check, nil
+}
+
+// buildGRPC turns gRPC health settings into a check
+func buildGRPC(req *GRPCReq) GRPCCheck {
+	if req == nil {
+		return GRPCCheck{}
+	}
+	return GRPCCheck{Service: req.Service, TLS: req.TLS}
+}
+
+// buildWebSocket turns WebSocket exchange settings into a check, compiling the expected pattern
+func buildWebSocket(req *WebSocketReq) (WebSocketCheck, error) {
+	if req == nil {
+		return WebSocketCheck{}, nil
+	}
+	if _, err := regexp.Compile(req.Expect); err != nil {
+		return WebSocketCheck{}, fmt.Errorf("%w: expect %q: %v", ErrInvalidCheck, req.Expect, err)
+	}
+	return WebSocketCheck{Send: req.Send, Expect: req.Expect}, nil
+}
+
+// buildHeartbeat turns heartbeat settings into a check
+func buildHeartbeat(req *HeartbeatReq) HeartbeatCheck {
+	check := HeartbeatCheck{Grace: defaultHeartbeatGrace}
+	if req != nil && req.Grace > 0 {
+		check.Grace = time.Duration(req.Grace) * time.Second
+	}
+	return check
+}
+
+// buildSteps converts and validates transaction steps
+func buildSteps(reqs []StepReq) ([]Step, error) {
+	steps := make([]Step, 0, len(reqs))
+	for i, req := range reqs {
+		request, err := buildRequest(req.Request)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		assertions, err := buildAssertions(req.Assertions)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+		captures := make([]Capture, 0, len(req.Captures))
+		for _, c := range req.Captures {
+			captures = append(captures, Capture{Name: c.Name, Source: c.Source, Expr: c.Expr})
+		}
+		steps = append(steps, Step{
+			Name:       req.Name,
+			URL:        req.URL,
+			Request:    request,
+			Assertions: assertions,
+			Captures:   captures,
+		})
+	}
+	if err := validateSteps(steps); err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
+
+// validateLocations checks that locations are distinct and the quorum can be reached; heartbeats
+// are pinged rather than checked, so they cannot run from locations
+func validateLocations(m *Monitor) error {
+	if len(m.Locations) == 0 {
+		if m.Quorum > 0 {
+			return fmt.Errorf("%w: quorum requires locations", ErrInvalidCheck)
+		}
+		return nil
+	}
+	if m.Type == TypeHeartbeat {
+		return fmt.Errorf("%w: heartbeat monitors cannot run from locations", ErrInvalidCheck)
+	}
+	seen := make(map[string]bool, len(m.Locations))
+	for _, location := range m.Locations {
+		if seen[location] {
+			return fmt.Errorf("%w: location %q is listed twice", ErrInvalidCheck, location)
+		}
+		seen[location] = true
+	}
+	if m.Quorum > len(m.Locations) {
+		return fmt.Errorf("%w: quorum %d exceeds the %d locations", ErrInvalidCheck, m.Quorum, len(m.Locations))
+	}
+	return nil
+}
+
+// buildAssertions converts and validates assertion payloads
+func buildAssertions(reqs []AssertionReq) ([]Assertion, error) {
+	assertions := make([]Assertion, 0, len(reqs))
+	for _, req := range reqs {
+		assertions = append(assertions, Assertion{
+			Type:     req.Type,
+			Severity: req.Severity,
+			Name:     req.Name,
+			Path:     req.Path,
+			Op:       req.Op,
+			Value:    req.Value,
+			Schema:   req.Schema,
+		})
+	}
+	if err := validateAssertions(assertions); err != nil {
+		return nil, err
+	}
+	return assertions, nil
+}
+
 func generateID() string {
 	return time.Now().Format("20060102150405000") // simple mock ID generator
 }
@@ -0,0 +1,81 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUpdateKeepsMaskedSecrets(t *testing.T) {
+	stored := HTTPRequest{
+		Method:  "POST",
+		Headers: map[string]string{"Authorization": "Bearer s3cret", "Accept": "application/json"},
+		Body:    `{"password":"hunter2"}`,
+	}
+
+	tests := []struct {
+		name    string
+		request RequestReq
+		want    HTTPRequest
+		wantErr error
+	}{
+		{
+			name:    "masked values sent back unchanged",
+			request: RequestReq{Method: "POST", Headers: map[string]string{"authorization": maskedValue, "Accept": "text/plain"}, Body: maskedValue},
+			want:    HTTPRequest{Headers: map[string]string{"Authorization": "Bearer s3cret", "Accept": "text/plain"}, Body: `{"password":"hunter2"}`},
+		},
+		{
+			name:    "new values replace the stored ones",
+			request: RequestReq{Method: "POST", Headers: map[string]string{"Authorization": "Bearer rotated"}, Body: "{}"},
+			want:    HTTPRequest{Headers: map[string]string{"Authorization": "Bearer rotated"}, Body: "{}"},
+		},
+		{
+			name:    "masked header with nothing stored",
+			request: RequestReq{Headers: map[string]string{"X-Api-Key": maskedValue}},
+			wantErr: ErrInvalidCheck,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := NewRepository()
+			step := Step{URL: "https://example.test/login", Request: stored}
+			m := &Monitor{ID: "m", UserID: "u", Type: TypeTransaction, URL: "https://example.test", Interval: time.Minute, Request: stored, Steps: []Step{step}}
+			if err := repo.Add(ctx, m); err != nil {
+				t.Fatal(err)
+			}
+
+			// What a client reads back masks the secrets
+			res := mapToResponse(m)
+			if res.Request.Headers["Authorization"] != maskedValue || res.Request.Body != maskedValue || res.Steps[0].Request.Body != maskedValue {
+				t.Fatalf("response request = %+v, want the body and credentials masked", res.Request)
+			}
+
+			request := tt.request
+			steps := []StepReq{{URL: step.URL, Request: &request}}
+			updated, err := NewService(repo).Update(ctx, "u", "m", UpdateReq{Request: &request, Steps: &steps})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for name, got := range map[string]HTTPRequest{"request": updated.Request, "step": updated.Steps[0].Request} {
+				if got.Body != tt.want.Body || len(got.Headers) != len(tt.want.Headers) {
+					t.Errorf("%s = %+v, want body %q and headers %v", name, got, tt.want.Body, tt.want.Headers)
+				}
+				for k, v := range tt.want.Headers {
+					if got.Headers[k] != v {
+						t.Errorf("%s header %s = %q, want %q", name, k, got.Headers[k], v)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// Defaults applied to monitors that do not configure confirmation settings
+const (
+	defaultFailureThreshold  = 1
+	defaultRecoveryThreshold = 1
+	defaultRetryBackoff      = time.Second
+)
+
+// applyStatus copies the outcome of a check onto the monitor
+func (m *Monitor) applyStatus(u StatusUpdate) {
+	m.LastChecked = u.LastChecked
+	m.StatusCode = u.StatusCode
+	m.ResponseTime = u.ResponseTime
+	m.Health = u.Health
+	m.HealthReason = u.HealthReason
+	m.IsHealthy = u.Health == HealthUp
+	m.ConsecutiveFailures = u.ConsecutiveFailures
+	m.ConsecutiveSuccesses = u.ConsecutiveSuccesses
+	m.IsFlapping = u.IsFlapping
+	m.AIExplanation = u.AIExplanation
+	m.Answers = u.Answers
+	m.Timing = u.Timing
+	m.NextRunAt = u.NextRunAt
+}
+
+// nextRunAt returns when the monitor is next due given its current state: an interval after its
+// last check, or for a heartbeat the deadline of its next ping
+func (m *Monitor) nextRunAt() time.Time {
+	if m.Type == TypeHeartbeat {
+		return m.heartbeatDeadline()
+	}
+	return m.LastChecked.Add(m.Interval)
+}
+
+// nextRunAfter returns when the monitor is next due once an update is applied
+func (m *Monitor) nextRunAfter(u StatusUpdate) time.Time {
+	next := *m
+	next.applyStatus(u)
+	return next.nextRunAt()
+}
+
+// answersAfter returns the answers to remember after a result: those it resolved, or the
+// previous ones when it resolved nothing
+func (m *Monitor) answersAfter(result *CheckResult) []string {
+	if result.Answers != nil {
+		return result.Answers
+	}
+	return m.Answers
+}
+
+// Outcome classifies a single raw result as up, degraded or down
+func (r *CheckResult) Outcome() Health {
+	switch {
+	case r.IsHealthy:
+		return HealthUp
+	case r.IsDegraded:
+		return HealthDegraded
+	default:
+		return HealthDown
+	}
+}
+
+// reason describes why a result was not fully healthy
+func (r *CheckResult) reason() string {
+	switch {
+	case r.FailureReason != "":
+		return r.FailureReason
+	case r.ErrorClass == ErrorClassHTTPStatus:
+		return fmt.Sprintf("unexpected status %d", r.StatusCode)
+	case r.ErrorClass != "":
+		return r.ErrorClass + " error"
+	}
+	return ""
+}
+
+// evaluate feeds a raw check outcome into the monitor's consecutive counters and returns the
+// resulting confirmed health. Degraded results count as successes: leaving down or pending
+// needs RecoveryThreshold of them, while moving between up and degraded takes effect at once.
+// transition reports whether the confirmed health changed.
+func (m *Monitor) evaluate(outcome Health) (next Health, transition bool) {
+	available := outcome.Available()
+	if available {
+		m.ConsecutiveSuccesses++
+		m.ConsecutiveFailures = 0
+	} else {
+		m.ConsecutiveFailures++
+		m.ConsecutiveSuccesses = 0
+	}
+
+	switch {
+	case available && m.Health != outcome && (m.Health.Available() || m.ConsecutiveSuccesses >= atLeastOne(m.RecoveryThreshold)):
+		return outcome, true
+	case !available && m.Health != HealthDown && m.ConsecutiveFailures >= atLeastOne(m.FailureThreshold):
+		return HealthDown, true
+	}
+
+	if m.Health == "" {
+		return HealthPending, false
+	}
+	return m.Health, false
+}
+
+// healthReason explains the confirmed health after a check: empty while up or pending, taken
+// from the result when it matches the confirmed state, and otherwise carried over while a
+// contrary result awaits confirmation
+func (m *Monitor) healthReason(next Health, result *CheckResult) string {
+	switch {
+	case next == HealthUp || next == HealthPending:
+		return ""
+	case result.Outcome() == next:
+		return result.reason()
+	default:
+		return m.HealthReason
+	}
+}
+
+// retryDelay returns the exponential backoff before the given retry attempt (1-based)
+func (m *Monitor) retryDelay(attempt int) time.Duration {
+	base := m.RetryBackoff
+	if base <= 0 {
+		base = defaultRetryBackoff
+	}
+	return base << (attempt - 1)
+}
+
+func atLeastOne(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
@@ -0,0 +1,121 @@
+package monitor
+
+import "testing"
+
+func TestEvaluate(t *testing.T) {
+	const (
+		up       = HealthUp
+		down     = HealthDown
+		degraded = HealthDegraded
+		pending  = HealthPending
+	)
+	type step struct {
+		outcome    Health
+		next       Health
+		transition bool
+	}
+
+	tests := []struct {
+		name     string
+		start    Health
+		failures int
+		recovery int
+		steps    []step
+	}{
+		{
+			name:  "new monitor comes up on its first success",
+			start: "",
+			steps: []step{{outcome: up, next: up, transition: true}},
+		},
+		{
+			name:     "new monitor waits for the recovery threshold",
+			start:    "",
+			failures: 2, recovery: 2,
+			steps: []step{
+				{outcome: up, next: pending},
+				{outcome: up, next: up, transition: true},
+			},
+		},
+		{
+			name:     "pending monitor goes down after the failure threshold",
+			start:    pending,
+			failures: 2,
+			steps: []step{
+				{outcome: down, next: pending},
+				{outcome: down, next: down, transition: true},
+			},
+		},
+		{
+			name:     "three failures before down",
+			start:    up,
+			failures: 3,
+			steps: []step{
+				{outcome: down, next: up},
+				{outcome: down, next: up},
+				{outcome: down, next: down, transition: true},
+				{outcome: down, next: down},
+			},
+		},
+		{
+			name:     "a success resets the failure count",
+			start:    up,
+			failures: 2,
+			steps: []step{
+				{outcome: down, next: up},
+				{outcome: up, next: up},
+				{outcome: down, next: up},
+				{outcome: down, next: down, transition: true},
+			},
+		},
+		{
+			name:     "two successes before up",
+			start:    down,
+			recovery: 2,
+			steps: []step{
+				{outcome: up, next: down},
+				{outcome: down, next: down},
+				{outcome: up, next: down},
+				{outcome: up, next: up, transition: true},
+			},
+		},
+		{
+			name:     "degraded counts towards recovery",
+			start:    down,
+			recovery: 2,
+			steps: []step{
+				{outcome: degraded, next: down},
+				{outcome: up, next: up, transition: true},
+			},
+		},
+		{
+			name:     "up and degraded switch at once",
+			start:    up,
+			failures: 3, recovery: 3,
+			steps: []step{
+				{outcome: degraded, next: degraded, transition: true},
+				{outcome: up, next: up, transition: true},
+			},
+		},
+		{
+			name:  "unset thresholds act on the first result",
+			start: up,
+			steps: []step{
+				{outcome: down, next: down, transition: true},
+				{outcome: up, next: up, transition: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Monitor{Health: tt.start, FailureThreshold: tt.failures, RecoveryThreshold: tt.recovery}
+			for i, s := range tt.steps {
+				next, transition := m.evaluate(s.outcome)
+				if next != s.next || transition != s.transition {
+					t.Fatalf("check %d (%s): got %s, transition %v; want %s, transition %v", i+1, s.outcome, next, transition, s.next, s.transition)
+				}
+				m.Health = next
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// Predefined reporting windows accepted by the stats endpoint
+var statsWindows = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// ErrInvalidWindow is returned when a stats window is neither predefined nor a valid custom range
+var ErrInvalidWindow = errors.New("window must be one of 24h, 7d, 30d or custom with from/to")
+
+// Stats summarizes availability and latency of a monitor over a reporting window
+type Stats struct {
+	Window          string
+	From            time.Time
+	To              time.Time
+	TotalChecks     int
+	HealthyChecks   int
+	DegradedChecks  int
+	UptimePercent   float64
+	DegradedPercent float64
+	P50             time.Duration
+	P95             time.Duration
+	P99             time.Duration
+	MTTR            time.Duration
+	IncidentCount   int
+}
+
+// StatsQuery selects the reporting window; From/To are only used for the custom window
+type StatsQuery struct {
+	Window string
+	From   time.Time
+	To     time.Time
+}
+
+// resolve turns the query into a concrete time range ending at now for predefined windows
+func (q StatsQuery) resolve(now time.Time) (time.Time, time.Time, error) {
+	if q.Window == "custom" {
+		if q.From.IsZero() || !q.From.Before(q.To) {
+			return time.Time{}, time.Time{}, ErrInvalidWindow
+		}
+		return q.From, q.To, nil
+	}
+
+	length, ok := statsWindows[q.Window]
+	if !ok {
+		return time.Time{}, time.Time{}, ErrInvalidWindow
+	}
+	return now.Add(-length), now, nil
+}
+
+// computeStats derives SLA figures from results ordered oldest first. Degraded checks count as
+// available for uptime but are reported separately. An incident is a run of consecutive down
+// checks; it is resolved by the first available check that follows it.
+func computeStats(results []*CheckResult) Stats {
+	var stats Stats
+	var latencies []time.Duration
+	var downSince time.Time
+	var repairTotal time.Duration
+	repaired := 0
+
+	for _, r := range results {
+		// Checks taken during maintenance do not count against availability
+		if r.InMaintenance {
+			continue
+		}
+		stats.TotalChecks++
+		if r.ResponseTime > 0 {
+			latencies = append(latencies, r.ResponseTime)
+		}
+
+		if r.Outcome().Available() {
+			if r.IsHealthy {
+				stats.HealthyChecks++
+			} else {
+				stats.DegradedChecks++
+			}
+			if !downSince.IsZero() {
+				repairTotal += r.CheckedAt.Sub(downSince)
+				repaired++
+				downSince = time.Time{}
+			}
+			continue
+		}
+
+		if downSince.IsZero() {
+			downSince = r.CheckedAt
+			stats.IncidentCount++
+		}
+	}
+
+	if stats.TotalChecks > 0 {
+		stats.UptimePercent = float64(stats.HealthyChecks+stats.DegradedChecks) / float64(stats.TotalChecks) * 100
+		stats.DegradedPercent = float64(stats.DegradedChecks) / float64(stats.TotalChecks) * 100
+	}
+	if repaired > 0 {
+		stats.MTTR = repairTotal / time.Duration(repaired)
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	stats.P50 = percentile(latencies, 50)
+	stats.P95 = percentile(latencies, 95)
+	stats.P99 = percentile(latencies, 99)
+
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
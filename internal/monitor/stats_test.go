@@ -0,0 +1,62 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestResultStats(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRepository()
+	if err := repo.Add(ctx, &Monitor{ID: "m", UserID: "u", Interval: time.Minute}); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	up := func(ms int) *CheckResult {
+		return &CheckResult{IsHealthy: true, ResponseTime: time.Duration(ms) * time.Millisecond}
+	}
+	down := &CheckResult{}
+	checks := []*CheckResult{
+		up(500), // before the window
+		up(100),
+		down,
+		down,
+		up(200), // repairs the first incident after 2 minutes
+		{IsDegraded: true, ResponseTime: 900 * time.Millisecond},
+		{InMaintenance: true},
+		down,
+		up(300), // repairs the second incident after 1 minute
+		down,    // still open at the end of the window
+		up(400), // after the window
+	}
+	for i, c := range checks {
+		c.MonitorID = "m"
+		c.CheckedAt = start.Add(time.Duration(i) * time.Minute)
+		if err := repo.AddResult(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := repo.ResultStats(ctx, "m", start.Add(time.Minute), start.Add(9*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.TotalChecks != 8 || stats.HealthyChecks != 3 || stats.DegradedChecks != 1 {
+		t.Errorf("checks = %d total, %d healthy, %d degraded, want 8, 3, 1", stats.TotalChecks, stats.HealthyChecks, stats.DegradedChecks)
+	}
+	if stats.UptimePercent != 50 || stats.DegradedPercent != 12.5 {
+		t.Errorf("uptime = %v%%, degraded = %v%%, want 50%%, 12.5%%", stats.UptimePercent, stats.DegradedPercent)
+	}
+	if stats.IncidentCount != 3 {
+		t.Errorf("incidents = %d, want 3", stats.IncidentCount)
+	}
+	if want := 90 * time.Second; stats.MTTR != want {
+		t.Errorf("MTTR = %s, want %s", stats.MTTR, want)
+	}
+	if stats.P50 != 200*time.Millisecond || stats.P95 != 900*time.Millisecond || stats.P99 != 900*time.Millisecond {
+		t.Errorf("percentiles = %s, %s, %s, want 200ms, 900ms, 900ms", stats.P50, stats.P95, stats.P99)
+	}
+}
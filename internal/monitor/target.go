@@ -0,0 +1,89 @@
+package monitor
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Monitor types select how a monitor's target is interpreted and checked
+type MonitorType string
+
+const (
+	// TypeHTTP requests an http(s) URL
+	TypeHTTP MonitorType = "http"
+	// TypeTLS handshakes with a host:port and inspects its certificate
+	TypeTLS MonitorType = "tls"
+	// TypeTCP connects to a host:port, optionally exchanging a payload
+	TypeTCP MonitorType = "tcp"
+	// TypeDNS resolves a domain name
+	TypeDNS MonitorType = "dns"
+	// TypeHeartbeat waits for pings from a job instead of checking a target
+	TypeHeartbeat MonitorType = "heartbeat"
+	// TypeTransaction runs a sequence of HTTP steps
+	TypeTransaction MonitorType = "transaction"
+	// TypeGRPC calls the standard gRPC health service of a host:port
+	TypeGRPC MonitorType = "grpc"
+	// TypeWebSocket completes a ws(s) upgrade, optionally exchanging a message
+	TypeWebSocket MonitorType = "websocket"
+)
+
+// normalizeTarget validates a monitor target for its type and returns the form stored in
+// Monitor.URL: an absolute http(s) URL, host:port for socket-level types, a domain name, or a
+// free-form label for heartbeats and transactions
+func normalizeTarget(t MonitorType, target string) (string, error) {
+	switch t {
+	case TypeHTTP:
+		u, err := url.ParseRequestURI(target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidCheck)
+		}
+		return target, nil
+	case TypeWebSocket:
+		u, err := url.ParseRequestURI(target)
+		if err != nil || (u.Scheme != "ws" && u.Scheme != "wss") || u.Host == "" {
+			return "", fmt.Errorf("%w: url must be an absolute ws or wss URL", ErrInvalidCheck)
+		}
+		return target, nil
+	case TypeTLS:
+		return hostPort(target, "443")
+	case TypeTCP, TypeGRPC:
+		return hostPort(target, "")
+	case TypeDNS:
+		name := strings.TrimSuffix(target, ".")
+		if name == "" || len(name) > 253 || strings.ContainsAny(name, " /:?#@") {
+			return "", fmt.Errorf("%w: target %q must be a domain name", ErrInvalidCheck, target)
+		}
+		return name, nil
+	case TypeHeartbeat, TypeTransaction:
+		return strings.TrimSpace(target), nil
+	default:
+		return "", fmt.Errorf("%w: unknown monitor type %q", ErrInvalidCheck, t)
+	}
+}
+
+// hostPort accepts host, host:port or a URL with a host and returns host:port, filling in
+// defaultPort when none is given; without a default the port is required
+func hostPort(target, defaultPort string) (string, error) {
+	invalid := fmt.Errorf("%w: target %q must be host or host:port", ErrInvalidCheck, target)
+	if defaultPort == "" {
+		invalid = fmt.Errorf("%w: target %q must be host:port", ErrInvalidCheck, target)
+	}
+
+	if u, err := url.Parse(target); err == nil && u.Scheme != "" && u.Host != "" {
+		target = u.Host
+	}
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		host, port = strings.Trim(target, "[]"), defaultPort
+	}
+	if host == "" || strings.ContainsAny(host, " /?#") {
+		return "", invalid
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", invalid
+	}
+	return net.JoinHostPort(host, port), nil
+}
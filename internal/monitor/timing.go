@@ -0,0 +1,101 @@
+package monitor
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timing breaks an HTTP check down into the phases of its request. Phases of redirected
+// requests are added up; a phase that was not reached, e.g. TLS for plain HTTP, is zero.
+type Timing struct {
+	DNS     time.Duration `json:"dns"`
+	Connect time.Duration `json:"connect"`
+	TLS     time.Duration `json:"tls"`
+	// FirstByte is the time from the request being written to the first response byte, which is
+	// mostly server processing
+	FirstByte time.Duration `json:"first_byte"`
+	// Transfer is the time from the first response byte to the end of the body
+	Transfer time.Duration `json:"transfer"`
+}
+
+// add accumulates the phases of another request, e.g. a transaction step
+func (t *Timing) add(o *Timing) {
+	if o == nil {
+		return
+	}
+	t.DNS += o.DNS
+	t.Connect += o.Connect
+	t.TLS += o.TLS
+	t.FirstByte += o.FirstByte
+	t.Transfer += o.Transfer
+}
+
+// requestTracer collects a Timing through httptrace hooks, which may fire on other goroutines
+type requestTracer struct {
+	mu                                                   sync.Mutex
+	timing                                               Timing
+	dnsStart, connectStart, tlsStart, written, firstByte time.Time
+}
+
+func (t *requestTracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { t.since(&t.dnsStart, &t.timing.DNS) },
+		ConnectStart: func(string, string) {
+			// Dialing several addresses in parallel counts once, from the first attempt
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.connectStart.IsZero() {
+				t.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.since(&t.connectStart, &t.timing.Connect)
+			}
+		},
+		TLSHandshakeStart:    func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.since(&t.tlsStart, &t.timing.TLS) },
+		WroteRequest:         func(httptrace.WroteRequestInfo) { t.mark(&t.written) },
+		GotFirstResponseByte: t.gotFirstByte,
+	}
+}
+
+func (t *requestTracer) mark(at *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	*at = time.Now()
+}
+
+// since adds the time elapsed from start to phase and clears start for the next request
+func (t *requestTracer) since(start *time.Time, phase *time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !start.IsZero() {
+		*phase += time.Since(*start)
+		*start = time.Time{}
+	}
+}
+
+func (t *requestTracer) gotFirstByte() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.firstByte = time.Now()
+	if !t.written.IsZero() {
+		t.timing.FirstByte += t.firstByte.Sub(t.written)
+		t.written = time.Time{}
+	}
+}
+
+// finish returns the collected phases once the response body has been read
+func (t *requestTracer) finish() *Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	timing := t.timing
+	if !t.firstByte.IsZero() {
+		timing.Transfer = time.Since(t.firstByte)
+	}
+	return &timing
+}
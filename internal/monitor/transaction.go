@@ -0,0 +1,272 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// maxSteps bounds the number of requests in a transaction
+const maxSteps = 10
+
+// Capture sources
+const (
+	CaptureJSONPath = "json_path"
+	CaptureRegex    = "regex"
+	CaptureHeader   = "header"
+)
+
+var (
+	// variablePattern matches a {{name}} reference to a captured variable
+	variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+	variableName    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// Step is one request of a transaction monitor. Its URL, header values and body may reference
+// variables captured by earlier steps as {{name}}.
+type Step struct {
+	Name       string      `json:"name,omitempty"`
+	URL        string      `json:"url"`
+	Request    HTTPRequest `json:"request"`
+	Assertions []Assertion `json:"assertions,omitempty"`
+	Captures   []Capture   `json:"captures,omitempty"`
+}
+
+// Capture extracts a variable from a step's response. Expr is a JSONPath for json_path, a
+// pattern for regex (its first group, or the whole match without groups) and a header name for
+// header.
+type Capture struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Expr   string `json:"expr"`
+}
+
+// StepResult records the outcome of one step of a transaction check
+type StepResult struct {
+	Name          string        `json:"name"`
+	URL           string        `json:"url"`
+	StatusCode    int           `json:"status_code"`
+	ResponseTime  time.Duration `json:"response_time"`
+	IsHealthy     bool          `json:"is_healthy"`
+	IsDegraded    bool          `json:"is_degraded"`
+	ErrorClass    string        `json:"error_class,omitempty"`
+	FailureReason string        `json:"failure_reason,omitempty"`
+}
+
+func (s Step) label(i int) string {
+	if s.Name != "" {
+		return fmt.Sprintf("step %d (%s)", i+1, s.Name)
+	}
+	return fmt.Sprintf("step %d", i+1)
+}
+
+// validateSteps checks a transaction's steps, including that every variable a step references
+// is captured by an earlier step
+func validateSteps(steps []Step) error {
+	if len(steps) == 0 || len(steps) > maxSteps {
+		return fmt.Errorf("%w: a transaction needs 1 to %d steps", ErrInvalidCheck, maxSteps)
+	}
+
+	captured := map[string]bool{}
+	for i, step := range steps {
+		invalid := func(format string, args ...interface{}) error {
+			return fmt.Errorf("%w: %s: %s", ErrInvalidCheck, step.label(i), fmt.Sprintf(format, args...))
+		}
+
+		// Placeholders are checked for being defined, then stand in as a plain value for URL parsing
+		templates := []string{step.URL, step.Request.Body}
+		for _, v := range step.Request.Headers {
+			templates = append(templates, v)
+		}
+		for _, t := range templates {
+			for _, ref := range variablePattern.FindAllStringSubmatch(t, -1) {
+				if !captured[ref[1]] {
+					return invalid("variable %q is not captured by an earlier step", ref[1])
+				}
+			}
+		}
+		if _, err := normalizeTarget(TypeHTTP, variablePattern.ReplaceAllString(step.URL, "x")); err != nil {
+			return invalid("url must be an absolute http or https URL")
+		}
+		if err := validateAssertions(step.Assertions); err != nil {
+			return fmt.Errorf("%s: %w", step.label(i), err)
+		}
+
+		for _, c := range step.Captures {
+			if !variableName.MatchString(c.Name) {
+				return invalid("capture name %q must be a letter or underscore followed by letters, digits or underscores", c.Name)
+			}
+			switch c.Source {
+			case CaptureJSONPath:
+				if _, err := parseJSONPath(c.Expr); err != nil {
+					return invalid("capture %s: %v", c.Name, err)
+				}
+			case CaptureRegex:
+				if _, err := regexp.Compile(c.Expr); err != nil {
+					return invalid("capture %s: %v", c.Name, err)
+				}
+			case CaptureHeader:
+				if c.Expr == "" {
+					return invalid("capture %s: header name is required", c.Name)
+				}
+			default:
+				return invalid("capture %s: unknown source %q", c.Name, c.Source)
+			}
+			captured[c.Name] = true
+		}
+	}
+	return nil
+}
+
+// extract returns the captured value from a response
+func (c Capture) extract(res *response) (string, error) {
+	switch c.Source {
+	case CaptureJSONPath:
+		var doc interface{}
+		if err := json.Unmarshal(res.body, &doc); err != nil {
+			return "", fmt.Errorf("body is not valid JSON")
+		}
+		path, _ := parseJSONPath(c.Expr)
+		v, ok := path.lookup(doc)
+		if !ok {
+			return "", fmt.Errorf("%s not found", c.Expr)
+		}
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return formatJSONValue(v), nil
+	case CaptureRegex:
+		match := regexp.MustCompile(c.Expr).FindSubmatch(res.body)
+		if match == nil {
+			return "", fmt.Errorf("body does not match %q", c.Expr)
+		}
+		if len(match) > 1 {
+			return string(match[1]), nil
+		}
+		return string(match[0]), nil
+	case CaptureHeader:
+		if values := res.header.Values(c.Expr); len(values) > 0 {
+			return values[0], nil
+		}
+		return "", fmt.Errorf("header %s not present", http.CanonicalHeaderKey(c.Expr))
+	default:
+		return "", fmt.Errorf("unknown source %q", c.Source)
+	}
+}
+
+// substitute replaces {{name}} references with captured values
+func substitute(s string, vars map[string]string) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	return variablePattern.ReplaceAllStringFunc(s, func(ref string) string {
+		return vars[variablePattern.FindStringSubmatch(ref)[1]]
+	})
+}
+
+// resolve returns the step's request with captured values substituted
+func (s Step) resolve(vars map[string]string) (string, HTTPRequest) {
+	spec := s.Request
+	spec.Body = substitute(spec.Body, vars)
+	if len(spec.Headers) > 0 {
+		spec.Headers = make(map[string]string, len(s.Request.Headers))
+		for k, v := range s.Request.Headers {
+			spec.Headers[k] = substitute(v, vars)
+		}
+	}
+	return substitute(s.URL, vars), spec
+}
+
+// checkTransaction runs a transaction's steps in order, stopping at the first step that fails.
+// The overall result is down when a step fails, degraded when any step is degraded, and its
+// response time and timing are the sums of the steps'.
+func (wp *WorkerPool) checkTransaction(ctx context.Context, clients *httpClients, m *Monitor) *CheckResult {
+	result := &CheckResult{MonitorID: m.ID, IsHealthy: true}
+	vars := map[string]string{}
+
+	for i, step := range m.Steps {
+		target, spec := step.resolve(vars)
+		stepResult, res := wp.sendHTTP(ctx, clients, m, target, spec, step.Assertions, len(step.Captures) > 0)
+
+		for _, c := range step.Captures {
+			if stepResult.Outcome() == HealthDown {
+				break
+			}
+			value, err := c.extract(res)
+			if err != nil {
+				stepResult.IsHealthy = false
+				stepResult.IsDegraded = false
+				stepResult.ErrorClass = ErrorClassAssertion
+				stepResult.FailureReason = fmt.Sprintf("capture %s: %v", c.Name, err)
+				break
+			}
+			vars[c.Name] = value
+		}
+
+		// The template URL is recorded so captured secrets do not end up in history
+		result.Steps = append(result.Steps, StepResult{
+			Name:          step.Name,
+			URL:           step.URL,
+			StatusCode:    stepResult.StatusCode,
+			ResponseTime:  stepResult.ResponseTime,
+			IsHealthy:     stepResult.IsHealthy,
+			IsDegraded:    stepResult.IsDegraded,
+			ErrorClass:    stepResult.ErrorClass,
+			FailureReason: stepResult.FailureReason,
+		})
+		result.CheckedAt = stepResult.CheckedAt
+		result.StatusCode = stepResult.StatusCode
+		result.ResponseTime += stepResult.ResponseTime
+		if stepResult.Timing != nil {
+			if result.Timing == nil {
+				result.Timing = &Timing{}
+			}
+			result.Timing.add(stepResult.Timing)
+		}
+		if result.Certificate == nil {
+			result.Certificate = stepResult.Certificate
+		}
+
+		switch stepResult.Outcome() {
+		case HealthDown:
+			result.IsHealthy = false
+			result.IsDegraded = false
+			result.ErrorClass = stepResult.ErrorClass
+			result.FailureReason = step.label(i) + " failed: " + describeStep(stepResult)
+			return result
+		case HealthDegraded:
+			if result.IsHealthy {
+				result.IsHealthy = false
+				result.IsDegraded = true
+				result.FailureReason = step.label(i) + " degraded: " + stepResult.FailureReason
+			}
+		}
+	}
+	return result
+}
+
+// describeStep summarizes why a step failed
+func describeStep(r *CheckResult) string {
+	switch {
+	case r.FailureReason != "":
+		return r.FailureReason
+	case r.StatusCode > 0:
+		return fmt.Sprintf("HTTP %d", r.StatusCode)
+	default:
+		return r.ErrorClass + " error"
+	}
+}
+
+// failedStep names the step a transaction failed at, or "" when no step failed
+func (r *CheckResult) failedStep() string {
+	for i, s := range r.Steps {
+		if !s.IsHealthy && !s.IsDegraded {
+			return Step{Name: s.Name}.label(i)
+		}
+	}
+	return ""
+}
@@ -2,7 +2,12 @@ package monitor
 
 import (
 	"context"
-	"net/http"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
 	"time"
 
 	"github.com/ranjithkumar/sentinelai/internal/llm"
@@ -12,121 +17,518 @@ import (
 // Job represents a single health check execution
 type Job struct {
 	Monitor *Monitor
+	// Ping is set for jobs queued by a heartbeat ping rather than by the scheduler
+	Ping *Ping
 }
 
-// WorkerPool manages concurrent health checks
+// WorkerPool manages concurrent health checks. Jobs wait in a bounded queue that hands the most
+// overdue job to the next free worker; the number of workers can be changed while running.
 type WorkerPool struct {
-	numWorkers int
-	jobChan    chan Job
+	queue *jobQueue
+	stats poolStats
 
-	repo   Repository
-	logger *zap.Logger
-	llm    llm.Provider
+	mu      sync.Mutex
+	ctx     context.Context // set by Start, used to spawn workers on Resize
+	target  int
+	running int
+	busy    int
+
+	repo      Repository
+	owner     string
+	leaseTTL  time.Duration
+	logger    *zap.Logger
+	llm       llm.Provider
+	observers []Observer
+	flaps     *flapDetector
+	maint     MaintenanceChecker
+	roots     *x509.CertPool // nil uses the system pool
+	remote    *RemoteDispatcher
 }
 
 // NewWorkerPool creates a new monitor worker pool
 func NewWorkerPool(numWorkers int, repo Repository, logger *zap.Logger, llmProvider llm.Provider) *WorkerPool {
 	return &WorkerPool{
-		numWorkers: numWorkers,
-		jobChan:    make(chan Job, 1000), // Buffer jobs
-		repo:       repo,
-		logger:     logger,
-		llm:        llmProvider,
+		queue:    newJobQueue(defaultQueueSize),
+		target:   numWorkers,
+		repo:     repo,
+		leaseTTL: DefaultLeaseTTL,
+		logger:   logger,
+		llm:      llmProvider,
+		flaps:    newFlapDetector(defaultFlapWindow, defaultFlapThreshold),
 	}
 }
 
-// Start spawns the configured number of workers
+// SetLease configures the owner and duration of the leases taken to record pings, which the
+// scheduler's claims do not cover; call before Start
+func (wp *WorkerPool) SetLease(owner string, ttl time.Duration) {
+	wp.owner = owner
+	wp.leaseTTL = ttl
+}
+
+// SetFlapPolicy configures flapping detection: a monitor whose confirmed health changes at least
+// threshold times within window is marked flapping; call before Start
+func (wp *WorkerPool) SetFlapPolicy(window time.Duration, threshold int) {
+	wp.flaps = newFlapDetector(window, threshold)
+}
+
+// SetMaintenance configures the checker consulted before each job runs; call before Start
+func (wp *WorkerPool) SetMaintenance(checker MaintenanceChecker) {
+	wp.maint = checker
+}
+
+// SetRootCAs configures the trust roots used to verify target certificates, e.g. for targets
+// behind a private CA; call before Start
+func (wp *WorkerPool) SetRootCAs(roots *x509.CertPool) {
+	wp.roots = roots
+}
+
+// SetRemote configures the dispatcher through which monitors with locations are checked by probe
+// agents; without one they are checked locally. Call before Start.
+func (wp *WorkerPool) SetRemote(d *RemoteDispatcher) {
+	wp.remote = d
+}
+
+// AddObserver registers an observer notified after every recorded check; call before Start
+func (wp *WorkerPool) AddObserver(o Observer) {
+	wp.observers = append(wp.observers, o)
+}
+
+// Start spawns the configured number of workers. Once ctx is done, submissions fail and queued
+// jobs are abandoned; their leases are released on shutdown.
 func (wp *WorkerPool) Start(ctx context.Context) {
-	wp.logger.Info("Starting monitor worker pool", zap.Int("workers", wp.numWorkers))
-	for i := 0; i < wp.numWorkers; i++ {
-		go wp.worker(ctx)
+	wp.mu.Lock()
+	wp.ctx = ctx
+	wp.logger.Info("Starting monitor worker pool", zap.Int("workers", wp.target), zap.Int("queue_size", wp.queue.capacity))
+	wp.spawnLocked()
+	wp.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		wp.queue.close()
+	}()
+	if wp.remote != nil {
+		go wp.collectRounds(ctx)
 	}
 }
 
-// Submit queues a health check job
-func (wp *WorkerPool) Submit(job Job) {
-	select {
-	case wp.jobChan <- job:
-	default:
-		wp.logger.Warn("Worker pool job channel is full, dropping health check job", zap.String("monitor_id", job.Monitor.ID))
+// Resize changes the number of workers. Extra workers start right away; surplus ones exit once
+// their current job is done.
+func (wp *WorkerPool) Resize(workers int) error {
+	if workers < 1 || workers > maxWorkers {
+		return fmt.Errorf("%w: workers must be between 1 and %d", ErrInvalidPoolSize, maxWorkers)
+	}
+
+	wp.mu.Lock()
+	previous := wp.target
+	wp.target = workers
+	if wp.ctx != nil {
+		wp.spawnLocked()
+	}
+	wp.mu.Unlock()
+	wp.queue.wake()
+
+	wp.logger.Info("Worker pool resized", zap.Int("from", previous), zap.Int("to", workers))
+	return nil
+}
+
+// spawnLocked starts workers until the target is reached
+func (wp *WorkerPool) spawnLocked() {
+	for ; wp.running < wp.target; wp.running++ {
+		go wp.worker(wp.ctx)
+	}
+}
+
+// retire reports whether a worker should exit, because the pool was shrunk or is stopping
+func (wp *WorkerPool) retire(ctx context.Context) bool {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	if ctx.Err() != nil || wp.running > wp.target {
+		wp.running--
+		return true
+	}
+	return false
+}
+
+// Free returns how many jobs can be submitted without waiting; the scheduler claims no more
+// monitors than this
+func (wp *WorkerPool) Free() int {
+	return wp.queue.free()
+}
+
+// Submit queues a health check job, waiting a bounded time for room when the queue is full. It
+// reports whether the job was queued; a scheduled job that was not still holds its lease, which
+// the caller must release.
+func (wp *WorkerPool) Submit(job Job) bool {
+	ctx := context.Background()
+	wp.mu.Lock()
+	if wp.ctx != nil {
+		ctx = wp.ctx
+	}
+	wp.mu.Unlock()
+
+	queued, waited := wp.queue.push(ctx, job, submitWait)
+	wp.stats.submitted(queued, waited)
+	if !queued {
+		wp.logger.Warn("Worker pool queue is full, health check job not queued", zap.String("monitor_id", job.Monitor.ID))
 	}
+	return queued
 }
 
 func (wp *WorkerPool) worker(ctx context.Context) {
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := newHTTPClients(wp.roots)
+	for !wp.retire(ctx) {
+		job, ok := wp.queue.pop(ctx)
+		if !ok {
+			continue
+		}
+		wp.setBusy(1)
+		wp.safeProcessJob(ctx, client, job)
+		wp.setBusy(-1)
+	}
+}
+
+func (wp *WorkerPool) setBusy(delta int) {
+	wp.mu.Lock()
+	defer wp.mu.Unlock()
+	wp.busy += delta
+}
+
+// Stats returns the pool's size, queue depth and submission counters
+func (wp *WorkerPool) Stats() PoolStats {
+	stats := wp.stats.snapshot()
+	wp.mu.Lock()
+	stats.Workers = wp.running
+	stats.TargetWorkers = wp.target
+	stats.Busy = wp.busy
+	wp.mu.Unlock()
+	stats.QueueDepth, stats.OldestQueued = wp.queue.depth(time.Now())
+	stats.QueueCapacity = wp.queue.capacity
+	if wp.remote != nil {
+		stats.RemoteRounds = wp.remote.pending()
+	}
+	return stats
+}
+
+func (wp *WorkerPool) safeProcessJob(ctx context.Context, client *httpClients, job Job) {
+	// A ping carries the monitor as it was when the ping arrived; it is recorded under a lease of
+	// its own on a fresh copy, so that it cannot interleave with a missed-ping check or another ping
+	if job.Ping != nil {
+		m, ok := wp.claimForPing(ctx, job.Monitor.ID)
+		if !ok {
+			return
+		}
+		job.Monitor = m
+	}
+
+	handedOff := false
+	defer func() {
+		if r := recover(); r != nil {
+			wp.logger.Error("Job panic recovered", zap.Any("panic", r), zap.String("monitor_id", job.Monitor.ID))
+		}
+		// A remote round keeps the lease until its result is recorded
+		if !handedOff {
+			wp.release(ctx, job.Monitor)
+		}
+	}()
+	handedOff = wp.processJob(ctx, client, job)
+}
+
+// release ends the lease a job held on a monitor. Recording a result has already moved the next
+// run; otherwise the monitor stays due and is claimed again on the next tick.
+func (wp *WorkerPool) release(ctx context.Context, m *Monitor) {
+	if err := wp.repo.Release(ctx, m.ID, m.LeaseOwner, m.LeaseExpiresAt, m.nextRunAt()); errors.Is(err, ErrLeaseLost) {
+		wp.logger.Warn("Monitor lease was lost during its check", zap.String("monitor_id", m.ID))
+	}
+}
+
+// claimForPing leases a monitor to record a ping, waiting up to pingClaimWait for a check that
+// holds it to finish. The ping is dropped when the monitor is gone or stays leased, which only
+// happens when a node hung mid-check.
+func (wp *WorkerPool) claimForPing(ctx context.Context, id string) (*Monitor, bool) {
+	deadline := time.Now().Add(pingClaimWait)
+	for {
+		now := time.Now()
+		m, err := wp.repo.Claim(ctx, id, wp.owner, now, now.Add(wp.leaseTTL))
+		if err == nil {
+			return m, true
+		}
+		if !errors.Is(err, ErrLeaseHeld) || now.After(deadline) {
+			wp.logger.Warn("Failed to claim monitor for ping, ping dropped", zap.Error(err), zap.String("monitor_id", id))
+			return nil, false
+		}
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-time.After(pingClaimRetry):
+		}
+	}
+}
+
+// processJob runs a job's check and records its result. It reports whether the job was handed to
+// probe agents instead, in which case its result is recorded once the round is collected.
+func (wp *WorkerPool) processJob(ctx context.Context, client *httpClients, job Job) bool {
+	m := job.Monitor
+
+	// A window may have opened between scheduling and pickup
+	mode := maintenanceMode(ctx, wp.maint, m, time.Now())
+	if mode == MaintenanceSkip {
+		return false
+	}
+
+	var result *CheckResult
+	if m.Type == TypeHeartbeat {
+		if result = checkHeartbeat(m, job.Ping, time.Now()); result == nil {
+			return false
+		}
+		result.Attempts = 1
+	} else if len(m.Locations) > 0 && wp.remote != nil {
+		// Agents retry on their own; the worker moves on while they run the check
+		if err := wp.remote.start(ctx, job, mode); err != nil {
+			wp.logger.Warn("Failed to open remote round", zap.Error(err), zap.String("monitor_id", m.ID))
+			return false
+		}
+		return true
+	} else if result = wp.checkWithRetries(ctx, client, m); result == nil {
+		return false
+	}
+
+	wp.finish(ctx, m, result, mode)
+	return false
+}
+
+// finish records a check's result, leaving the monitor's health alone under a suppressing
+// maintenance window
+func (wp *WorkerPool) finish(ctx context.Context, m *Monitor, result *CheckResult, mode MaintenanceMode) {
+	if mode == MaintenanceSuppress {
+		wp.recordMaintenance(ctx, m, result)
+	} else {
+		wp.record(ctx, m, result)
+	}
+
+	wp.logger.Info("Health check executed",
+		zap.String("monitor_id", m.ID),
+		zap.String("url", m.URL),
+		zap.Int("status", result.StatusCode),
+		zap.Duration("latency", result.ResponseTime),
+		zap.String("outcome", string(result.Outcome())),
+		zap.Int("attempts", result.Attempts),
+		zap.String("health", string(m.Health)),
+		zap.Bool("in_maintenance", result.InMaintenance),
+	)
+}
+
+// collectRounds records the results of remote rounds as they complete, until ctx is cancelled.
+// Rounds still open then are abandoned; their leases are released on shutdown.
+func (wp *WorkerPool) collectRounds(ctx context.Context) {
+	ticker := time.NewTicker(remotePoll)
+	defer ticker.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case job, ok := <-wp.jobChan:
-			if !ok {
-				return
-			}
-			wp.safeProcessJob(ctx, client, job)
+		case <-ticker.C:
+		}
+		for _, round := range wp.remote.collect(ctx, time.Now()) {
+			go wp.finishRound(ctx, round)
 		}
 	}
 }
 
-func (wp *WorkerPool) safeProcessJob(ctx context.Context, client *http.Client, job Job) {
+// finishRound records a completed remote round and ends the lease its job held
+func (wp *WorkerPool) finishRound(ctx context.Context, round finishedRound) {
+	m := round.job.Monitor
 	defer func() {
 		if r := recover(); r != nil {
-			wp.logger.Error("Job panic recovered", zap.Any("panic", r), zap.String("monitor_id", job.Monitor.ID))
+			wp.logger.Error("Remote round panic recovered", zap.Any("panic", r), zap.String("monitor_id", m.ID))
 		}
-		_ = wp.repo.SetRunning(ctx, job.Monitor.ID, false)
+		wp.release(ctx, m)
 	}()
-	wp.processJob(ctx, client, job)
+	wp.finish(ctx, m, round.result, round.mode)
 }
 
-func (wp *WorkerPool) processJob(ctx context.Context, client *http.Client, job Job) {
-	start := time.Now()
+// checkWithRetries runs a check, retrying a down outcome up to the monitor's retry count; it
+// returns nil when ctx is cancelled while waiting to retry
+func (wp *WorkerPool) checkWithRetries(ctx context.Context, client *httpClients, m *Monitor) *CheckResult {
+	result := wp.check(ctx, client, m)
+	result.Attempts = 1
+	for retry := 1; retry <= m.RetryCount && result.Outcome() == HealthDown && result.ErrorClass != ErrorClassInvalidRequest; retry++ {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(m.retryDelay(retry)):
+		}
+		result = wp.check(ctx, client, m)
+		result.Attempts = retry + 1
+	}
+	return result
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, job.Monitor.URL, nil)
-	if err != nil {
-		_ = wp.repo.UpdateStatus(ctx, job.Monitor.ID, time.Now(), 0, 0, false, "")
-		wp.logger.Error("Failed to create request", zap.Error(err), zap.String("url", job.Monitor.URL))
-		return
+// check dispatches to the checker for the monitor's type
+func (wp *WorkerPool) check(ctx context.Context, clients *httpClients, m *Monitor) *CheckResult {
+	switch m.Type {
+	case TypeTLS:
+		return wp.checkTLS(ctx, m)
+	case TypeTCP:
+		return wp.checkTCP(ctx, m)
+	case TypeDNS:
+		return wp.checkDNS(ctx, m)
+	case TypeTransaction:
+		return wp.checkTransaction(ctx, clients, m)
+	case TypeGRPC:
+		return wp.checkGRPC(ctx, m)
+	case TypeWebSocket:
+		return wp.checkWebSocket(ctx, m)
+	default:
+		return wp.checkHTTP(ctx, clients, m)
 	}
+}
 
-	res, err := client.Do(req)
-	duration := time.Since(start)
+// record applies the confirmation thresholds and flapping policy to a raw result, asks the LLM
+// for an explanation on a confirmed down transition, persists state and history, and notifies
+// observers. While a monitor is flapping its transitions are suppressed.
+func (wp *WorkerPool) record(ctx context.Context, m *Monitor, result *CheckResult) {
+	previous := m.Health
+	if previous == "" {
+		previous = HealthPending
+	}
+	next, transition := m.evaluate(result.Outcome())
 
-	now := time.Now()
+	// Only changes in availability count towards flapping, not moves between up and degraded
+	count := wp.flaps.observe(m.ID, result.CheckedAt, transition && previous.Available() != next.Available())
+	flap, baseline := wp.flaps.update(m, count, previous)
+	switch flap {
+	case FlapStarted:
+		m.IsFlapping = true
+		transition = false
+		wp.logger.Warn("Monitor started flapping", zap.String("monitor_id", m.ID), zap.Int("transitions", count))
+	case FlapStopped:
+		// Report the settled state as a single transition relative to where flapping began
+		m.IsFlapping = false
+		previous = baseline
+		transition = next != baseline
+		wp.logger.Info("Monitor stopped flapping", zap.String("monitor_id", m.ID), zap.String("health", string(next)))
+	default:
+		if m.IsFlapping {
+			transition = false
+		}
+	}
 
-	if err != nil {
-		explanation := wp.getAIExplanation(ctx, job.Monitor.URL, 0, duration, now)
-		_ = wp.repo.UpdateStatus(ctx, job.Monitor.ID, now, 0, duration, false, explanation)
-		wp.logger.Warn("Health check unreachable", zap.Error(err), zap.String("url", job.Monitor.URL))
-		return
+	explanation := m.AIExplanation
+	switch {
+	case transition && next == HealthDown:
+		explanation = wp.getAIExplanation(ctx, m.URL, m.Type, result)
+		result.AIExplanation = explanation
+	case next != HealthDown:
+		explanation = ""
 	}
-	defer res.Body.Close()
 
-	isHealthy := res.StatusCode >= 200 && res.StatusCode < 400
-	explanation := ""
-	if !isHealthy {
-		explanation = wp.getAIExplanation(ctx, job.Monitor.URL, res.StatusCode, duration, now)
+	update := StatusUpdate{
+		LastChecked:          result.CheckedAt,
+		StatusCode:           result.StatusCode,
+		ResponseTime:         result.ResponseTime,
+		Health:               next,
+		HealthReason:         m.healthReason(next, result),
+		ConsecutiveFailures:  m.ConsecutiveFailures,
+		ConsecutiveSuccesses: m.ConsecutiveSuccesses,
+		IsFlapping:           m.IsFlapping,
+		AIExplanation:        explanation,
+		Answers:              m.answersAfter(result),
+		Timing:               result.Timing,
+	}
+	update.NextRunAt = m.nextRunAfter(update)
+	if err := wp.repo.UpdateStatus(ctx, m.ID, update); err != nil {
+		wp.logger.Warn("Failed to update monitor status", zap.Error(err), zap.String("monitor_id", m.ID))
 	}
+	if err := wp.repo.AddResult(ctx, result); err != nil {
+		wp.logger.Warn("Failed to store check result", zap.Error(err), zap.String("monitor_id", m.ID))
+	}
+	m.applyStatus(update)
 
-	_ = wp.repo.UpdateStatus(ctx, job.Monitor.ID, now, res.StatusCode, duration, isHealthy, explanation)
+	event := CheckEvent{Monitor: m, Result: result, Previous: previous, Transition: transition, Flap: flap}
+	for _, o := range wp.observers {
+		if err := o.OnCheck(ctx, event); err != nil {
+			wp.logger.Warn("Check observer failed", zap.Error(err), zap.String("monitor_id", m.ID))
+		}
+	}
+}
 
-	wp.logger.Info("Health check executed",
-		zap.String("monitor_id", job.Monitor.ID),
-		zap.String("url", job.Monitor.URL),
-		zap.Int("status", res.StatusCode),
-		zap.Duration("latency", duration),
-		zap.Bool("healthy", isHealthy),
-	)
+// recordMaintenance stores a result taken during a suppressing maintenance window. Health,
+// confirmation counters and the flapping state are left untouched so that the monitor resumes
+// from its pre-maintenance state, and neither the LLM nor observers are involved.
+func (wp *WorkerPool) recordMaintenance(ctx context.Context, m *Monitor, result *CheckResult) {
+	result.InMaintenance = true
+
+	update := StatusUpdate{
+		LastChecked:          result.CheckedAt,
+		StatusCode:           result.StatusCode,
+		ResponseTime:         result.ResponseTime,
+		Health:               m.Health,
+		HealthReason:         m.HealthReason,
+		ConsecutiveFailures:  m.ConsecutiveFailures,
+		ConsecutiveSuccesses: m.ConsecutiveSuccesses,
+		IsFlapping:           m.IsFlapping,
+		AIExplanation:        m.AIExplanation,
+		Answers:              m.answersAfter(result),
+		Timing:               result.Timing,
+	}
+	if update.Health == "" {
+		update.Health = HealthPending
+	}
+	update.NextRunAt = m.nextRunAfter(update)
+	if err := wp.repo.UpdateStatus(ctx, m.ID, update); err != nil {
+		wp.logger.Warn("Failed to update monitor status", zap.Error(err), zap.String("monitor_id", m.ID))
+	}
+	if err := wp.repo.AddResult(ctx, result); err != nil {
+		wp.logger.Warn("Failed to store check result", zap.Error(err), zap.String("monitor_id", m.ID))
+	}
+	m.applyStatus(update)
+}
+
+// classifyError maps a transport error onto one of the ErrorClass constants
+func classifyError(err error) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var certErr *tls.CertificateVerificationError
+	var recordErr tls.RecordHeaderError
+
+	switch {
+	case errors.As(err, &dnsErr):
+		return ErrorClassDNS
+	case errors.As(err, &certErr), errors.As(err, &recordErr):
+		return ErrorClassTLS
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorClassTimeout
+	default:
+		return ErrorClassConnection
+	}
+}
+
+// llmTiming converts a timing breakdown for the failure analysis
+func llmTiming(t *Timing) *llm.Timing {
+	if t == nil {
+		return nil
+	}
+	return &llm.Timing{DNS: t.DNS, Connect: t.Connect, TLS: t.TLS, FirstByte: t.FirstByte, Transfer: t.Transfer}
 }
 
-func (wp *WorkerPool) getAIExplanation(ctx context.Context, url string, statusCode int, duration time.Duration, timestamp time.Time) string {
+func (wp *WorkerPool) getAIExplanation(ctx context.Context, url string, checkType MonitorType, result *CheckResult) string {
 	if wp.llm == nil {
 		return ""
 	}
 
 	input := llm.FailureInput{
-		URL:          url,
-		StatusCode:   statusCode,
-		ResponseTime: duration,
-		Timestamp:    timestamp,
+		URL:           url,
+		CheckType:     string(checkType),
+		ErrorClass:    result.ErrorClass,
+		ExitCode:      result.ExitCode,
+		Output:        result.Output,
+		FailedStep:    result.failedStep(),
+		GRPCCode:      result.GRPCCode,
+		Timing:        llmTiming(result.Timing),
+		StatusCode:    result.StatusCode,
+		ResponseTime:  result.ResponseTime,
+		Timestamp:     result.CheckedAt,
+		FailureReason: result.FailureReason,
 	}
 
 	// Internal timeout specifically for LLM analysis so it doesn't block worker
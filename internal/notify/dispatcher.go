@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultDispatchQueue bounds the deliveries waiting for a dispatcher worker
+	defaultDispatchQueue = 1000
+	// defaultDispatchWorkers is how many deliveries are attempted at once
+	defaultDispatchWorkers = 4
+	// maxDeliveryAttempts bounds how often a failing delivery is tried
+	maxDeliveryAttempts = 4
+	// deliveryBackoff is the wait before the first retry; it doubles on each further one
+	deliveryBackoff = 2 * time.Second
+)
+
+// ErrDispatchQueueFull is returned when a notification is dropped because deliveries are backed up
+var ErrDispatchQueueFull = errors.New("notification queue is full")
+
+// delivery is a message bound for a single channel, so that channels are retried independently
+type delivery struct {
+	channel *Channel
+	msg     Message
+}
+
+// Dispatcher delivers check notifications in the background, so that a slow or failing channel
+// holds up neither the worker that recorded the check nor the other channels. Failed deliveries
+// are retried with exponential backoff; deliveries still queued when ctx ends are dropped.
+type Dispatcher struct {
+	sender  Service
+	logger  *zap.Logger
+	queue   chan delivery
+	workers int
+	backoff time.Duration
+}
+
+// NewDispatcher creates a dispatcher delivering through sender
+func NewDispatcher(sender Service, logger *zap.Logger) *Dispatcher {
+	return &Dispatcher{
+		sender:  sender,
+		logger:  logger,
+		queue:   make(chan delivery, defaultDispatchQueue),
+		workers: defaultDispatchWorkers,
+		backoff: deliveryBackoff,
+	}
+}
+
+// Start spawns the delivery workers, which run until ctx is cancelled
+func (d *Dispatcher) Start(ctx context.Context) {
+	d.logger.Info("Starting notification dispatcher", zap.Int("workers", d.workers), zap.Int("queue_size", cap(d.queue)))
+	for i := 0; i < d.workers; i++ {
+		go d.worker(ctx)
+	}
+}
+
+// Dispatch queues a message for each channel without waiting. It returns ErrDispatchQueueFull,
+// having queued what fitted, when the queue has no room left.
+func (d *Dispatcher) Dispatch(channels []*Channel, msg Message) error {
+	for _, ch := range channels {
+		select {
+		case d.queue <- delivery{channel: ch, msg: msg}:
+		default:
+			d.logger.Warn("Notification queue is full, notification dropped", zap.String("channel_id", ch.ID), zap.String("monitor_id", msg.MonitorID))
+			return ErrDispatchQueueFull
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) worker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case job := <-d.queue:
+			d.deliver(ctx, job)
+		}
+	}
+}
+
+// deliver sends a message to its channel, retrying failures until maxDeliveryAttempts
+func (d *Dispatcher) deliver(ctx context.Context, job delivery) {
+	defer func() {
+		if r := recover(); r != nil {
+			d.logger.Error("Notification delivery panic recovered", zap.Any("panic", r), zap.String("channel_id", job.channel.ID))
+		}
+	}()
+
+	wait := d.backoff
+	for attempt := 1; ; attempt++ {
+		err := d.sender.Send(ctx, []*Channel{job.channel}, job.msg)
+		if err == nil {
+			return
+		}
+		if attempt == maxDeliveryAttempts {
+			d.logger.Error("Notification delivery failed, giving up", zap.Error(err),
+				zap.String("channel_id", job.channel.ID), zap.String("monitor_id", job.msg.MonitorID), zap.Int("attempts", attempt))
+			return
+		}
+		d.logger.Warn("Notification delivery failed, retrying", zap.Error(err),
+			zap.String("channel_id", job.channel.ID), zap.Duration("retry_in", wait))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
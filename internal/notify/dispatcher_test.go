@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+	"go.uber.org/zap"
+)
+
+// flakyServer fails the first failures requests with a 500 and accepts the rest, counting them all
+func flakyServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestDispatcherRetriesFailedDeliveries(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int32
+		attempts int32
+	}{
+		{name: "delivered at once", failures: 0, attempts: 1},
+		{name: "delivered after retries", failures: 2, attempts: 3},
+		{name: "given up after the last attempt", failures: 100, attempts: maxDeliveryAttempts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := flakyServer(t, tt.failures)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			d := NewDispatcher(NewService(NewRepository(), monitor.NewRepository()), zap.NewNop())
+			d.backoff = time.Millisecond
+			d.Start(ctx)
+
+			ch := &Channel{ID: "c", Type: ChannelWebhook, Config: ChannelConfig{URL: srv.URL}}
+			if err := d.Dispatch([]*Channel{ch}, testMessage()); err != nil {
+				t.Fatalf("dispatch: %v", err)
+			}
+
+			deadline := time.Now().Add(2 * time.Second)
+			for requests.Load() < tt.attempts && time.Now().Before(deadline) {
+				time.Sleep(5 * time.Millisecond)
+			}
+			// Leave time for any attempt beyond the expected ones
+			time.Sleep(50 * time.Millisecond)
+			if got := requests.Load(); got != tt.attempts {
+				t.Errorf("attempts = %d, want %d", got, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestDispatcherQueueBounded(t *testing.T) {
+	d := NewDispatcher(NewService(NewRepository(), monitor.NewRepository()), zap.NewNop())
+	d.queue = make(chan delivery, 1)
+
+	channels := []*Channel{{ID: "a"}, {ID: "b"}}
+	if err := d.Dispatch(channels, testMessage()); err != ErrDispatchQueueFull {
+		t.Errorf("dispatch past capacity: err = %v, want ErrDispatchQueueFull", err)
+	}
+	if len(d.queue) != 1 {
+		t.Errorf("queued %d deliveries, want 1", len(d.queue))
+	}
+}
+
+func TestOnCheckDoesNotWaitForDelivery(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	repo := NewRepository()
+	if err := repo.Create(ctx, &Channel{ID: "c", Type: ChannelWebhook, Config: ChannelConfig{URL: srv.URL}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Attach(ctx, "m", "c"); err != nil {
+		t.Fatal(err)
+	}
+	svc := NewService(repo, monitor.NewRepository())
+	d := NewDispatcher(svc, zap.NewNop())
+	d.Start(ctx)
+	svc.SetDispatcher(d)
+
+	event := monitor.CheckEvent{
+		Monitor:    &monitor.Monitor{ID: "m", Health: monitor.HealthDown},
+		Result:     &monitor.CheckResult{MonitorID: "m"},
+		Previous:   monitor.HealthUp,
+		Transition: true,
+	}
+	start := time.Now()
+	if err := svc.OnCheck(ctx, event); err != nil {
+		t.Fatalf("on check: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("OnCheck took %s while the channel hangs", elapsed)
+	}
+}
@@ -0,0 +1,35 @@
+package notify
+
+import "time"
+
+// ChannelType identifies the delivery mechanism of a channel
+type ChannelType string
+
+const (
+	ChannelWebhook ChannelType = "webhook"
+	ChannelSlack   ChannelType = "slack"
+	ChannelDiscord ChannelType = "discord"
+	ChannelEmail   ChannelType = "email"
+)
+
+// Channel is a notification destination registered by a user
+type Channel struct {
+	ID        string        `json:"id"`
+	UserID    string        `json:"user_id"`
+	Name      string        `json:"name"`
+	Type      ChannelType   `json:"type"`
+	Config    ChannelConfig `json:"config"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// ChannelConfig holds type specific settings; URL applies to webhook based channels, the SMTP
+// fields to email channels
+type ChannelConfig struct {
+	URL          string   `json:"url,omitempty"`
+	SMTPHost     string   `json:"smtp_host,omitempty"`
+	SMTPPort     int      `json:"smtp_port,omitempty"`
+	SMTPUsername string   `json:"smtp_username,omitempty"`
+	SMTPPassword string   `json:"smtp_password,omitempty"`
+	From         string   `json:"from,omitempty"`
+	To           []string `json:"to,omitempty"`
+}
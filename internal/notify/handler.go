@@ -0,0 +1,202 @@
+package notify
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+)
+
+// ChannelResponse is the DTO used to shape the API response; SMTP credentials are never returned
+type ChannelResponse struct {
+	ID        string      `json:"id"`
+	Name      string      `json:"name"`
+	Type      ChannelType `json:"type"`
+	URL       string      `json:"url,omitempty"`
+	SMTPHost  string      `json:"smtp_host,omitempty"`
+	SMTPPort  int         `json:"smtp_port,omitempty"`
+	From      string      `json:"from,omitempty"`
+	To        []string    `json:"to,omitempty"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+func mapToResponse(ch *Channel) ChannelResponse {
+	return ChannelResponse{
+		ID:        ch.ID,
+		Name:      ch.Name,
+		Type:      ch.Type,
+		URL:       ch.Config.URL,
+		SMTPHost:  ch.Config.SMTPHost,
+		SMTPPort:  ch.Config.SMTPPort,
+		From:      ch.Config.From,
+		To:        ch.Config.To,
+		CreatedAt: ch.CreatedAt,
+	}
+}
+
+func mapToResponses(channels []*Channel) []ChannelResponse {
+	responseData := make([]ChannelResponse, 0, len(channels))
+	for _, ch := range channels {
+		responseData = append(responseData, mapToResponse(ch))
+	}
+	return responseData
+}
+
+// Handler processes HTTP notification channel actions
+type Handler struct {
+	svc Service
+}
+
+// NewHandler generates a dependency-resolved Handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// Create handles registering a new notification channel for the caller
+func (h *Handler) Create(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	var req CreateChannelReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid request data", "data": nil})
+		return
+	}
+
+	ch, err := h.svc.Create(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		respondError(c, err, "failed to create channel")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true, "message": "channel created successfully", "data": mapToResponse(ch)})
+}
+
+// List handles returning the caller's notification channels
+func (h *Handler) List(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	channels, err := h.svc.List(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "failed to list channels", "data": nil})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "channels retrieved", "data": mapToResponses(channels)})
+}
+
+// Delete handles removing a notification channel and its monitor attachments
+func (h *Handler) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		respondError(c, err, "failed to delete channel")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "channel deleted successfully", "data": nil})
+}
+
+// Test handles sending a test notification through a channel
+func (h *Handler) Test(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	if err := h.svc.Test(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		if errors.Is(err, ErrChannelNotFound) || errors.Is(err, ErrForbidden) {
+			respondError(c, err, "")
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"success": false, "message": "test notification failed: " + err.Error(), "data": nil})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "test notification sent", "data": nil})
+}
+
+// ListForMonitor handles returning the channels attached to a monitor
+func (h *Handler) ListForMonitor(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	channels, err := h.svc.ListForMonitor(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to list monitor channels")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "monitor channels retrieved", "data": mapToResponses(channels)})
+}
+
+// Attach handles attaching one of the caller's channels to one of their monitors
+func (h *Handler) Attach(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	var req AttachReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid request data", "data": nil})
+		return
+	}
+
+	if err := h.svc.Attach(c.Request.Context(), userID.(string), c.Param("id"), req.ChannelID); err != nil {
+		respondError(c, err, "failed to attach channel")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "channel attached to monitor", "data": nil})
+}
+
+// Detach handles removing a channel from a monitor
+func (h *Handler) Detach(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "unauthorized", "data": nil})
+		return
+	}
+
+	if err := h.svc.Detach(c.Request.Context(), userID.(string), c.Param("id"), c.Param("channelId")); err != nil {
+		respondError(c, err, "failed to detach channel")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true, "message": "channel detached from monitor", "data": nil})
+}
+
+// respondError maps service errors onto HTTP status codes, falling back to a 500 with the given message
+func respondError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, ErrChannelNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "channel not found", "data": nil})
+	case errors.Is(err, monitor.ErrMonitorNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "monitor not found", "data": nil})
+	case errors.Is(err, ErrForbidden):
+		c.JSON(http.StatusForbidden, gin.H{"success": false, "message": "forbidden", "data": nil})
+	case errors.Is(err, ErrInvalidConfig):
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": err.Error(), "data": nil})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": fallback, "data": nil})
+	}
+}
@@ -0,0 +1,196 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Event identifies what a notification is about
+type Event string
+
+const (
+	EventDown      Event = "monitor.down"
+	EventRecovered Event = "monitor.recovered"
+	EventDegraded  Event = "monitor.degraded"
+	EventFlapping  Event = "monitor.flapping"
+	EventEscalated Event = "incident.escalated"
+	EventTest      Event = "test"
+)
+
+// Message is the channel-agnostic content of a notification
+type Message struct {
+	Event        Event     `json:"event"`
+	MonitorID    string    `json:"monitor_id"`
+	URL          string    `json:"url"`
+	Health       string    `json:"health"`
+	StatusCode   int       `json:"status_code"`
+	ErrorClass   string    `json:"error_class,omitempty"`
+	Reason       string    `json:"failure_reason,omitempty"`
+	ResponseTime int64     `json:"response_time"`
+	Explanation  string    `json:"ai_explanation,omitempty"`
+	Timestamp    time.Time `json:"timestamp"`
+
+	IncidentID     string `json:"incident_id,omitempty"`
+	EscalationStep int    `json:"escalation_step,omitempty"`
+}
+
+// Title returns a one-line summary suitable for subjects and chat headlines
+func (m Message) Title() string {
+	switch m.Event {
+	case EventDown:
+		return fmt.Sprintf("[SentinelAI] DOWN: %s", m.URL)
+	case EventRecovered:
+		return fmt.Sprintf("[SentinelAI] RECOVERED: %s", m.URL)
+	case EventDegraded:
+		return fmt.Sprintf("[SentinelAI] DEGRADED: %s", m.URL)
+	case EventFlapping:
+		return fmt.Sprintf("[SentinelAI] FLAPPING: %s", m.URL)
+	case EventEscalated:
+		return fmt.Sprintf("[SentinelAI] ESCALATION step %d, unacknowledged: %s", m.EscalationStep, m.URL)
+	default:
+		return "[SentinelAI] Test notification"
+	}
+}
+
+// Text renders the full plain-text body including the AI explanation when present
+func (m Message) Text() string {
+	var b strings.Builder
+	b.WriteString(m.Title())
+	fmt.Fprintf(&b, "\nMonitor: %s\nHealth: %s\nTime: %s", m.MonitorID, m.Health, m.Timestamp.Format(time.RFC3339))
+	if m.IncidentID != "" {
+		fmt.Fprintf(&b, "\nIncident: %s", m.IncidentID)
+	}
+	if m.StatusCode > 0 {
+		fmt.Fprintf(&b, "\nStatus code: %d", m.StatusCode)
+	}
+	if m.ErrorClass != "" {
+		fmt.Fprintf(&b, "\nError: %s", m.ErrorClass)
+	}
+	if m.Reason != "" {
+		fmt.Fprintf(&b, "\nReason: %s", m.Reason)
+	}
+	fmt.Fprintf(&b, "\nResponse time: %dms", m.ResponseTime)
+	if m.Explanation != "" {
+		fmt.Fprintf(&b, "\n\nAI analysis:\n%s", m.Explanation)
+	}
+	return b.String()
+}
+
+// Notifier delivers a message over a single outbound channel
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+type webhookNotifier struct {
+	client *http.Client
+	url    string
+	build  func(Message) interface{}
+}
+
+// NewWebhookNotifier posts the message as a generic JSON document
+func NewWebhookNotifier(client *http.Client, url string) Notifier {
+	return &webhookNotifier{client: client, url: url, build: func(m Message) interface{} { return m }}
+}
+
+// NewSlackNotifier posts to a Slack-compatible incoming webhook
+func NewSlackNotifier(client *http.Client, url string) Notifier {
+	return &webhookNotifier{client: client, url: url, build: func(m Message) interface{} {
+		return map[string]string{"text": m.Text()}
+	}}
+}
+
+// discordContentLimit is the maximum message length accepted by Discord webhooks, in characters
+const discordContentLimit = 2000
+
+// NewDiscordNotifier posts to a Discord webhook
+func NewDiscordNotifier(client *http.Client, url string) Notifier {
+	return &webhookNotifier{client: client, url: url, build: func(m Message) interface{} {
+		content := m.Text()
+		if utf8.RuneCountInString(content) > discordContentLimit {
+			content = string([]rune(content)[:discordContentLimit-3]) + "..."
+		}
+		return map[string]string{"content": content}
+	}}
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(n.build(msg))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status: %d", res.StatusCode)
+	}
+	return nil
+}
+
+// SMTPConfig holds the settings of an email channel
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+type emailNotifier struct {
+	cfg SMTPConfig
+}
+
+// NewEmailNotifier sends plain-text email through an SMTP relay
+func NewEmailNotifier(cfg SMTPConfig) Notifier {
+	return &emailNotifier{cfg: cfg}
+}
+
+func (n *emailNotifier) Notify(ctx context.Context, msg Message) error {
+	addr := net.JoinHostPort(n.cfg.Host, strconv.Itoa(n.cfg.Port))
+
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, n.cfg.Host)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(n.cfg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Title())
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Text(), "\n", "\r\n"))
+	b.WriteString("\r\n")
+
+	// net/smtp has no context support, so honour cancellation by abandoning the send
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, n.cfg.From, n.cfg.To, []byte(b.String()))
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
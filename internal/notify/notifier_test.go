@@ -0,0 +1,243 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func testMessage() Message {
+	return Message{
+		Event:        EventDown,
+		MonitorID:    "m-1",
+		URL:          "https://api.example.test/health",
+		Health:       "down",
+		StatusCode:   503,
+		ErrorClass:   "http_status",
+		Reason:       "unexpected status 503",
+		ResponseTime: 120,
+		Explanation:  "The upstream is overloaded.",
+		Timestamp:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+// captureServer records the body and content type of the last request it receives and answers
+// with status
+func captureServer(t *testing.T, status int) (*httptest.Server, *[]byte, *string) {
+	t.Helper()
+
+	var body []byte
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		contentType = r.Header.Get("Content-Type")
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &body, &contentType
+}
+
+func TestWebhookNotifierPostsMessage(t *testing.T) {
+	srv, body, contentType := captureServer(t, http.StatusOK)
+
+	msg := testMessage()
+	if err := NewWebhookNotifier(srv.Client(), srv.URL).Notify(context.Background(), msg); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if *contentType != "application/json" {
+		t.Errorf("content type = %q, want application/json", *contentType)
+	}
+
+	var got Message
+	if err := json.Unmarshal(*body, &got); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if got != msg {
+		t.Errorf("payload = %+v, want %+v", got, msg)
+	}
+}
+
+func TestWebhookNotifierRejectsErrorStatus(t *testing.T) {
+	srv, _, _ := captureServer(t, http.StatusInternalServerError)
+
+	err := NewWebhookNotifier(srv.Client(), srv.URL).Notify(context.Background(), testMessage())
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Fatalf("err = %v, want the status code reported", err)
+	}
+}
+
+func TestSlackNotifierPostsText(t *testing.T) {
+	srv, body, _ := captureServer(t, http.StatusOK)
+
+	msg := testMessage()
+	if err := NewSlackNotifier(srv.Client(), srv.URL).Notify(context.Background(), msg); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+
+	var got map[string]string
+	if err := json.Unmarshal(*body, &got); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if len(got) != 1 || got["text"] != msg.Text() {
+		t.Errorf("payload = %v, want only text %q", got, msg.Text())
+	}
+}
+
+func TestDiscordNotifierTruncatesContent(t *testing.T) {
+	tests := []struct {
+		name        string
+		explanation string
+		truncated   bool
+	}{
+		{name: "short message", explanation: "The upstream is overloaded."},
+		{name: "message over the limit", explanation: strings.Repeat("x", discordContentLimit), truncated: true},
+		{name: "multibyte characters over the limit", explanation: strings.Repeat("é世", discordContentLimit), truncated: true},
+		{name: "multibyte characters within the limit", explanation: strings.Repeat("é", discordContentLimit/2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, body, _ := captureServer(t, http.StatusNoContent)
+
+			msg := testMessage()
+			msg.Explanation = tt.explanation
+			if err := NewDiscordNotifier(srv.Client(), srv.URL).Notify(context.Background(), msg); err != nil {
+				t.Fatalf("notify: %v", err)
+			}
+
+			var got map[string]string
+			if err := json.Unmarshal(*body, &got); err != nil {
+				t.Fatalf("decode payload: %v", err)
+			}
+			content := got["content"]
+			if !tt.truncated {
+				if content != msg.Text() {
+					t.Errorf("content = %q, want %q", content, msg.Text())
+				}
+				return
+			}
+			if n := utf8.RuneCountInString(content); n != discordContentLimit || !strings.HasSuffix(content, "...") {
+				t.Errorf("content length = %d characters, want %d ending in ...", n, discordContentLimit)
+			}
+			if !utf8.ValidString(content) {
+				t.Error("content was cut inside a character")
+			}
+		})
+	}
+}
+
+// smtpSession is what a fake SMTP server received in one delivery
+type smtpSession struct {
+	from string
+	to   []string
+	data string
+}
+
+// startSMTPServer accepts a single unauthenticated delivery and sends it on the returned channel
+func startSMTPServer(t *testing.T) (string, int, <-chan smtpSession) {
+	t.Helper()
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { lis.Close() })
+
+	sessions := make(chan smtpSession, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+		var s smtpSession
+
+		reply("220 localhost ESMTP test")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			cmd := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				s.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+				reply("250 OK")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				s.to = append(s.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+				reply("250 OK")
+			case cmd == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				s.data = data.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				sessions <- s
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	addr := lis.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, sessions
+}
+
+func TestEmailNotifierSendsMail(t *testing.T) {
+	host, port, sessions := startSMTPServer(t)
+
+	cfg := SMTPConfig{Host: host, Port: port, From: "alerts@example.test", To: []string{"ops@example.test", "oncall@example.test"}}
+	msg := testMessage()
+	if err := NewEmailNotifier(cfg).Notify(context.Background(), msg); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+
+	var s smtpSession
+	select {
+	case s = <-sessions:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no delivery received")
+	}
+	if s.from != cfg.From {
+		t.Errorf("from = %q, want %q", s.from, cfg.From)
+	}
+	if strings.Join(s.to, ",") != strings.Join(cfg.To, ",") {
+		t.Errorf("recipients = %v, want %v", s.to, cfg.To)
+	}
+	for _, want := range []string{
+		"Subject: " + msg.Title() + "\r\n",
+		"To: ops@example.test, oncall@example.test\r\n",
+		"Reason: unexpected status 503\r\n",
+		"The upstream is overloaded.",
+	} {
+		if !strings.Contains(s.data, want) {
+			t.Errorf("message does not contain %q:\n%s", want, s.data)
+		}
+	}
+}
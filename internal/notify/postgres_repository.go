@@ -0,0 +1,139 @@
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+)
+
+type postgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository creates a postgres channel repository on an established connection pool
+func NewPostgresRepository(db *sql.DB) (Repository, error) {
+	if err := initSchema(db); err != nil {
+		return nil, err
+	}
+
+	return &postgresRepository{db: db}, nil
+}
+
+func initSchema(db *sql.DB) error {
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS notification_channels (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			name TEXT NOT NULL,
+			type TEXT NOT NULL,
+			config JSONB NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_notification_channels_user ON notification_channels (user_id)`,
+		`CREATE TABLE IF NOT EXISTS monitor_channels (
+			monitor_id TEXT NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+			channel_id TEXT NOT NULL REFERENCES notification_channels(id) ON DELETE CASCADE,
+			PRIMARY KEY (monitor_id, channel_id)
+		)`,
+	}
+
+	for _, stmt := range statements {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *postgresRepository) Create(ctx context.Context, ch *Channel) error {
+	config, err := json.Marshal(ch.Config)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO notification_channels (id, user_id, name, type, config, created_at) VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err = r.db.ExecContext(ctx, query, ch.ID, ch.UserID, ch.Name, ch.Type, config, ch.CreatedAt)
+	return err
+}
+
+func (r *postgresRepository) GetByID(ctx context.Context, id string) (*Channel, error) {
+	channels, err := r.queryChannels(ctx, `SELECT id, user_id, name, type, config, created_at FROM notification_channels WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(channels) == 0 {
+		return nil, ErrChannelNotFound
+	}
+	return channels[0], nil
+}
+
+func (r *postgresRepository) List(ctx context.Context, userID string) ([]*Channel, error) {
+	query := `SELECT id, user_id, name, type, config, created_at FROM notification_channels WHERE user_id = $1 ORDER BY created_at`
+	return r.queryChannels(ctx, query, userID)
+}
+
+func (r *postgresRepository) ListByMonitor(ctx context.Context, monitorID string) ([]*Channel, error) {
+	query := `
+	SELECT c.id, c.user_id, c.name, c.type, c.config, c.created_at
+	FROM notification_channels c
+	JOIN monitor_channels mc ON mc.channel_id = c.id
+	WHERE mc.monitor_id = $1
+	`
+	return r.queryChannels(ctx, query, monitorID)
+}
+
+func (r *postgresRepository) queryChannels(ctx context.Context, query string, args ...interface{}) ([]*Channel, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*Channel
+	for rows.Next() {
+		var ch Channel
+		var config []byte
+		if err := rows.Scan(&ch.ID, &ch.UserID, &ch.Name, &ch.Type, &config, &ch.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(config, &ch.Config); err != nil {
+			return nil, err
+		}
+		result = append(result, &ch)
+	}
+	return result, rows.Err()
+}
+
+func (r *postgresRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM notification_channels WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+func (r *postgresRepository) Attach(ctx context.Context, monitorID, channelID string) error {
+	query := `INSERT INTO monitor_channels (monitor_id, channel_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`
+	_, err := r.db.ExecContext(ctx, query, monitorID, channelID)
+	return err
+}
+
+func (r *postgresRepository) Detach(ctx context.Context, monitorID, channelID string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM monitor_channels WHERE monitor_id = $1 AND channel_id = $2`, monitorID, channelID)
+	if err != nil {
+		return err
+	}
+	return expectAffected(res)
+}
+
+// expectAffected maps a statement that touched no rows onto ErrChannelNotFound
+func expectAffected(res sql.Result) error {
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrChannelNotFound
+	}
+	return nil
+}
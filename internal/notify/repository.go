@@ -0,0 +1,127 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Repository defines data access for notification channels and their monitor attachments
+type Repository interface {
+	Create(ctx context.Context, ch *Channel) error
+	GetByID(ctx context.Context, id string) (*Channel, error)
+	List(ctx context.Context, userID string) ([]*Channel, error)
+	Delete(ctx context.Context, id string) error
+	Attach(ctx context.Context, monitorID, channelID string) error
+	Detach(ctx context.Context, monitorID, channelID string) error
+	ListByMonitor(ctx context.Context, monitorID string) ([]*Channel, error)
+}
+
+var ErrChannelNotFound = errors.New("channel not found")
+
+type inMemoryRepository struct {
+	mu          sync.RWMutex
+	channels    map[string]*Channel
+	attachments map[string]map[string]bool // monitor ID -> channel IDs
+}
+
+// NewRepository creates a new in-memory channel repository
+func NewRepository() Repository {
+	return &inMemoryRepository{
+		channels:    make(map[string]*Channel),
+		attachments: make(map[string]map[string]bool),
+	}
+}
+
+func cloneChannel(ch *Channel) *Channel {
+	clone := *ch
+	clone.Config.To = append([]string(nil), ch.Config.To...)
+	return &clone
+}
+
+func (r *inMemoryRepository) Create(ctx context.Context, ch *Channel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.channels[ch.ID]; exists {
+		return errors.New("channel already exists")
+	}
+	r.channels[ch.ID] = cloneChannel(ch)
+	return nil
+}
+
+func (r *inMemoryRepository) GetByID(ctx context.Context, id string) (*Channel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ch, exists := r.channels[id]
+	if !exists {
+		return nil, ErrChannelNotFound
+	}
+	return cloneChannel(ch), nil
+}
+
+func (r *inMemoryRepository) List(ctx context.Context, userID string) ([]*Channel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*Channel
+	for _, ch := range r.channels {
+		if ch.UserID == userID {
+			result = append(result, cloneChannel(ch))
+		}
+	}
+	return result, nil
+}
+
+func (r *inMemoryRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.channels[id]; !exists {
+		return ErrChannelNotFound
+	}
+	delete(r.channels, id)
+	for _, attached := range r.attachments {
+		delete(attached, id)
+	}
+	return nil
+}
+
+func (r *inMemoryRepository) Attach(ctx context.Context, monitorID, channelID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.channels[channelID]; !exists {
+		return ErrChannelNotFound
+	}
+	if r.attachments[monitorID] == nil {
+		r.attachments[monitorID] = make(map[string]bool)
+	}
+	r.attachments[monitorID][channelID] = true
+	return nil
+}
+
+func (r *inMemoryRepository) Detach(ctx context.Context, monitorID, channelID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.attachments[monitorID][channelID] {
+		return ErrChannelNotFound
+	}
+	delete(r.attachments[monitorID], channelID)
+	return nil
+}
+
+func (r *inMemoryRepository) ListByMonitor(ctx context.Context, monitorID string) ([]*Channel, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*Channel
+	for channelID := range r.attachments[monitorID] {
+		if ch, exists := r.channels[channelID]; exists {
+			result = append(result, cloneChannel(ch))
+		}
+	}
+	return result, nil
+}
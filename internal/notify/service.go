@@ -0,0 +1,270 @@
+package notify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ranjithkumar/sentinelai/internal/monitor"
+)
+
+var (
+	// ErrForbidden is returned when a user acts on a channel or monitor they do not own
+	ErrForbidden = errors.New("channel or monitor belongs to another user")
+	// ErrInvalidConfig is returned when a channel lacks the settings its type requires
+	ErrInvalidConfig = errors.New("channel configuration is incomplete for its type")
+)
+
+// sendTimeout bounds the delivery of a single notification so a slow channel cannot stall a worker
+const sendTimeout = 10 * time.Second
+
+// CreateChannelReq defines the payload for registering a notification channel
+type CreateChannelReq struct {
+	Name         string      `json:"name" binding:"required,max=100"`
+	Type         ChannelType `json:"type" binding:"required,oneof=webhook slack discord email"`
+	URL          string      `json:"url" binding:"omitempty,url"`
+	SMTPHost     string      `json:"smtp_host"`
+	SMTPPort     int         `json:"smtp_port" binding:"omitempty,min=1,max=65535"`
+	SMTPUsername string      `json:"smtp_username"`
+	SMTPPassword string      `json:"smtp_password"`
+	From         string      `json:"from" binding:"omitempty,email"`
+	To           []string    `json:"to" binding:"omitempty,dive,email"`
+}
+
+// AttachReq defines the payload for attaching a channel to a monitor
+type AttachReq struct {
+	ChannelID string `json:"channel_id" binding:"required"`
+}
+
+// Service manages notification channels and delivers monitor transitions to them
+type Service interface {
+	monitor.Observer
+	Create(ctx context.Context, userID string, req CreateChannelReq) (*Channel, error)
+	List(ctx context.Context, userID string) ([]*Channel, error)
+	Delete(ctx context.Context, userID, id string) error
+	Test(ctx context.Context, userID, id string) error
+	Attach(ctx context.Context, userID, monitorID, channelID string) error
+	Detach(ctx context.Context, userID, monitorID, channelID string) error
+	ListForMonitor(ctx context.Context, userID, monitorID string) ([]*Channel, error)
+	// Send delivers a message to the given channels, returning the joined delivery errors
+	Send(ctx context.Context, channels []*Channel, msg Message) error
+	// SetDispatcher hands check notifications to d instead of sending them from OnCheck; call
+	// before the worker pool starts
+	SetDispatcher(d *Dispatcher)
+}
+
+type serviceImpl struct {
+	repo     Repository
+	monitors monitor.Repository
+	client   *http.Client
+	dispatch *Dispatcher
+}
+
+// NewService creates a new notification service
+func NewService(repo Repository, monitors monitor.Repository) Service {
+	return &serviceImpl{
+		repo:     repo,
+		monitors: monitors,
+		client:   &http.Client{Timeout: sendTimeout},
+	}
+}
+
+// OnCheck notifies the monitor's channels about confirmed down, recovery and degraded
+// transitions and about a monitor starting to flap. With a dispatcher the messages are only
+// queued, so that delivery does not hold up the worker recording the check.
+func (s *serviceImpl) OnCheck(ctx context.Context, event monitor.CheckEvent) error {
+	var kind Event
+	switch {
+	case event.Flap == monitor.FlapStarted:
+		kind = EventFlapping
+	case !event.Transition:
+		return nil
+	case event.Monitor.Health == monitor.HealthDown:
+		kind = EventDown
+	case event.Monitor.Health.Available() && event.Previous == monitor.HealthDown:
+		kind = EventRecovered
+	case event.Monitor.Health == monitor.HealthDegraded:
+		kind = EventDegraded
+	default:
+		return nil
+	}
+
+	channels, err := s.repo.ListByMonitor(ctx, event.Monitor.ID)
+	if err != nil || len(channels) == 0 {
+		return err
+	}
+	if s.dispatch != nil {
+		return s.dispatch.Dispatch(channels, NewMessage(kind, event))
+	}
+	return s.Send(ctx, channels, NewMessage(kind, event))
+}
+
+func (s *serviceImpl) SetDispatcher(d *Dispatcher) {
+	s.dispatch = d
+}
+
+// NewMessage builds a notification message from a check event
+func NewMessage(kind Event, event monitor.CheckEvent) Message {
+	return Message{
+		Event:        kind,
+		MonitorID:    event.Monitor.ID,
+		URL:          event.Monitor.URL,
+		Health:       string(event.Monitor.Health),
+		StatusCode:   event.Result.StatusCode,
+		ErrorClass:   event.Result.ErrorClass,
+		Reason:       event.Result.FailureReason,
+		ResponseTime: event.Result.ResponseTime.Milliseconds(),
+		Explanation:  event.Monitor.AIExplanation,
+		Timestamp:    event.Result.CheckedAt,
+	}
+}
+
+func (s *serviceImpl) Send(ctx context.Context, channels []*Channel, msg Message) error {
+	var errs []error
+	for _, ch := range channels {
+		n, err := s.notifierFor(ch)
+		if err == nil {
+			sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+			err = n.Notify(sendCtx, msg)
+			cancel()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("channel %s (%s): %w", ch.ID, ch.Type, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *serviceImpl) notifierFor(ch *Channel) (Notifier, error) {
+	switch ch.Type {
+	case ChannelWebhook:
+		return NewWebhookNotifier(s.client, ch.Config.URL), nil
+	case ChannelSlack:
+		return NewSlackNotifier(s.client, ch.Config.URL), nil
+	case ChannelDiscord:
+		return NewDiscordNotifier(s.client, ch.Config.URL), nil
+	case ChannelEmail:
+		return NewEmailNotifier(SMTPConfig{
+			Host:     ch.Config.SMTPHost,
+			Port:     ch.Config.SMTPPort,
+			Username: ch.Config.SMTPUsername,
+			Password: ch.Config.SMTPPassword,
+			From:     ch.Config.From,
+			To:       ch.Config.To,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unsupported channel type %q", ch.Type)
+	}
+}
+
+func (s *serviceImpl) Create(ctx context.Context, userID string, req CreateChannelReq) (*Channel, error) {
+	cfg := ChannelConfig{URL: req.URL}
+	if req.Type == ChannelEmail {
+		if req.SMTPHost == "" || req.SMTPPort == 0 || req.From == "" || len(req.To) == 0 {
+			return nil, ErrInvalidConfig
+		}
+		cfg = ChannelConfig{
+			SMTPHost:     req.SMTPHost,
+			SMTPPort:     req.SMTPPort,
+			SMTPUsername: req.SMTPUsername,
+			SMTPPassword: req.SMTPPassword,
+			From:         req.From,
+			To:           req.To,
+		}
+	} else if req.URL == "" {
+		return nil, ErrInvalidConfig
+	}
+
+	ch := &Channel{
+		ID:        generateID(),
+		UserID:    userID,
+		Name:      req.Name,
+		Type:      req.Type,
+		Config:    cfg,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Create(ctx, ch); err != nil {
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (s *serviceImpl) List(ctx context.Context, userID string) ([]*Channel, error) {
+	return s.repo.List(ctx, userID)
+}
+
+func (s *serviceImpl) Delete(ctx context.Context, userID, id string) error {
+	if _, err := s.ownedChannel(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *serviceImpl) Test(ctx context.Context, userID, id string) error {
+	ch, err := s.ownedChannel(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	return s.Send(ctx, []*Channel{ch}, Message{
+		Event:     EventTest,
+		URL:       "https://example.com",
+		Health:    string(monitor.HealthUp),
+		Timestamp: time.Now(),
+	})
+}
+
+func (s *serviceImpl) Attach(ctx context.Context, userID, monitorID, channelID string) error {
+	if err := s.ownedMonitor(ctx, userID, monitorID); err != nil {
+		return err
+	}
+	if _, err := s.ownedChannel(ctx, userID, channelID); err != nil {
+		return err
+	}
+	return s.repo.Attach(ctx, monitorID, channelID)
+}
+
+func (s *serviceImpl) Detach(ctx context.Context, userID, monitorID, channelID string) error {
+	if err := s.ownedMonitor(ctx, userID, monitorID); err != nil {
+		return err
+	}
+	return s.repo.Detach(ctx, monitorID, channelID)
+}
+
+func (s *serviceImpl) ListForMonitor(ctx context.Context, userID, monitorID string) ([]*Channel, error) {
+	if err := s.ownedMonitor(ctx, userID, monitorID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByMonitor(ctx, monitorID)
+}
+
+func (s *serviceImpl) ownedChannel(ctx context.Context, userID, id string) (*Channel, error) {
+	ch, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ch.UserID != userID {
+		return nil, ErrForbidden
+	}
+	return ch, nil
+}
+
+func (s *serviceImpl) ownedMonitor(ctx context.Context, userID, monitorID string) error {
+	m, err := s.monitors.GetByID(ctx, monitorID)
+	if err != nil {
+		return err
+	}
+	if m.UserID != userID {
+		return ErrForbidden
+	}
+	return nil
+}
+
+func generateID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return time.Now().Format("20060102") + "-" + hex.EncodeToString(b)
+}
@@ -3,11 +3,18 @@ package server
 import (
 	"fmt"
 
+	"github.com/ranjithkumar/sentinelai/internal/agent"
 	"github.com/ranjithkumar/sentinelai/internal/auth"
+	"github.com/ranjithkumar/sentinelai/internal/cluster"
+	"github.com/ranjithkumar/sentinelai/internal/escalation"
+	"github.com/ranjithkumar/sentinelai/internal/incident"
+	"github.com/ranjithkumar/sentinelai/internal/maintenance"
 	"github.com/ranjithkumar/sentinelai/internal/monitor"
+	"github.com/ranjithkumar/sentinelai/internal/notify"
 	"github.com/ranjithkumar/sentinelai/internal/repository"
 	"github.com/ranjithkumar/sentinelai/internal/service"
 	"github.com/ranjithkumar/sentinelai/pkg/config"
+	"github.com/ranjithkumar/sentinelai/pkg/database"
 )
 
 // Container holds all application dependencies
@@ -18,6 +25,27 @@ type Container struct {
 	AuthSvc     auth.Service
 	MonitorRepo monitor.Repository
 	MonitorSvc  monitor.Service
+
+	IncidentRepo   incident.Repository
+	IncidentSvc    incident.Service
+	NotifyRepo     notify.Repository
+	NotifySvc      notify.Service
+	EscalationRepo escalation.Repository
+	EscalationSvc  escalation.Service
+
+	MaintenanceRepo maintenance.Repository
+	MaintenanceSvc  maintenance.Service
+
+	ClusterRepo cluster.Repository
+
+	AgentRepo        agent.Repository
+	AgentSvc         agent.Service
+	RemoteDispatcher *monitor.RemoteDispatcher
+
+	// WorkerPool, Scheduler and Elector are set once started, before the server is created
+	WorkerPool *monitor.WorkerPool
+	Scheduler  *monitor.Scheduler
+	Elector    *cluster.Elector
 }
 
 // NewContainer initializes and wires dependencies
@@ -25,22 +53,78 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 	repo := repository.New()
 	svc := service.New(repo)
 
-	authRepo := auth.NewRepository()
-	authSvc := auth.NewService(authRepo)
-
+	var authRepo auth.Repository
 	var monitorRepo monitor.Repository
-	var err error
+	var incidentRepo incident.Repository
+	var notifyRepo notify.Repository
+	var escalationRepo escalation.Repository
+	var maintenanceRepo maintenance.Repository
+	var clusterRepo cluster.Repository
+	var agentRepo agent.Repository
+	var roundRepo monitor.RoundRepository
 
 	if cfg.DBHost != "" {
 		dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", cfg.DBUser, cfg.DBPassword, cfg.DBHost, cfg.DBPort, cfg.DBName)
-		monitorRepo, err = monitor.NewPostgresRepository(dsn)
+		db, err := database.Connect(dsn)
+		if err != nil {
+			return nil, err
+		}
+		authRepo, err = auth.NewPostgresRepository(db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init auth repo: %w", err)
+		}
+		monitorRepo, err = monitor.NewPostgresRepository(db)
 		if err != nil {
 			return nil, fmt.Errorf("failed to init postgres repo: %w", err)
 		}
+		incidentRepo, err = incident.NewPostgresRepository(db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init incident repo: %w", err)
+		}
+		notifyRepo, err = notify.NewPostgresRepository(db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init notification repo: %w", err)
+		}
+		escalationRepo, err = escalation.NewPostgresRepository(db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init escalation repo: %w", err)
+		}
+		maintenanceRepo, err = maintenance.NewPostgresRepository(db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init maintenance repo: %w", err)
+		}
+		clusterRepo, err = cluster.NewPostgresRepository(db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init cluster repo: %w", err)
+		}
+		agentRepo, err = agent.NewPostgresRepository(db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init agent repo: %w", err)
+		}
+		roundRepo, err = monitor.NewPostgresRoundRepository(db)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init remote round repo: %w", err)
+		}
 	} else {
+		authRepo = auth.NewRepository()
 		monitorRepo = monitor.NewRepository()
+		incidentRepo = incident.NewRepository()
+		notifyRepo = notify.NewRepository()
+		escalationRepo = escalation.NewRepository()
+		maintenanceRepo = maintenance.NewRepository()
+		clusterRepo = cluster.NewRepository()
+		agentRepo = agent.NewRepository()
+		roundRepo = monitor.NewRoundRepository()
 	}
+	authSvc := auth.NewService(authRepo)
 	monitorSvc := monitor.NewService(monitorRepo)
+	incidentSvc := incident.NewService(incidentRepo)
+	notifySvc := notify.NewService(notifyRepo, monitorRepo)
+	escalationSvc := escalation.NewService(escalationRepo, monitorRepo, notifyRepo)
+	incidentSvc.AddListener(escalationSvc)
+	maintenanceSvc := maintenance.NewService(maintenanceRepo, monitorRepo)
+	dispatcher := monitor.NewRemoteDispatcher(roundRepo)
+	agentSvc := agent.NewService(agentRepo, dispatcher)
 
 	return &Container{
 		Repository:  repo,
@@ -49,5 +133,21 @@ func NewContainer(cfg *config.Config) (*Container, error) {
 		AuthSvc:     authSvc,
 		MonitorRepo: monitorRepo,
 		MonitorSvc:  monitorSvc,
+
+		IncidentRepo:   incidentRepo,
+		IncidentSvc:    incidentSvc,
+		NotifyRepo:     notifyRepo,
+		NotifySvc:      notifySvc,
+		EscalationRepo: escalationRepo,
+		EscalationSvc:  escalationSvc,
+
+		MaintenanceRepo: maintenanceRepo,
+		MaintenanceSvc:  maintenanceSvc,
+
+		ClusterRepo: clusterRepo,
+
+		AgentRepo:        agentRepo,
+		AgentSvc:         agentSvc,
+		RemoteDispatcher: dispatcher,
 	}, nil
 }
@@ -2,10 +2,16 @@ package server
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/ranjithkumar/sentinelai/internal/agent"
 	"github.com/ranjithkumar/sentinelai/internal/auth"
+	"github.com/ranjithkumar/sentinelai/internal/cluster"
+	"github.com/ranjithkumar/sentinelai/internal/escalation"
 	"github.com/ranjithkumar/sentinelai/internal/handler"
+	"github.com/ranjithkumar/sentinelai/internal/incident"
+	"github.com/ranjithkumar/sentinelai/internal/maintenance"
 	"github.com/ranjithkumar/sentinelai/internal/middleware"
 	"github.com/ranjithkumar/sentinelai/internal/monitor"
+	"github.com/ranjithkumar/sentinelai/internal/notify"
 	"github.com/ranjithkumar/sentinelai/pkg/config"
 	"go.uber.org/zap"
 )
@@ -21,15 +27,39 @@ func SetupRouter(cfg *config.Config, logger *zap.Logger, container *Container) *
 	healthHandler := handler.NewHealthHandler()
 	authHandler := auth.NewHandler(container.AuthSvc, cfg)
 	monitorHandler := monitor.NewHandler(container.MonitorSvc)
+	incidentHandler := incident.NewHandler(container.IncidentSvc)
+	notifyHandler := notify.NewHandler(container.NotifySvc)
+	escalationHandler := escalation.NewHandler(container.EscalationSvc)
+	maintenanceHandler := maintenance.NewHandler(container.MaintenanceSvc)
+	adminHandler := monitor.NewAdminHandler(container.Scheduler, container.WorkerPool)
+	clusterHandler := cluster.NewHandler(container.Elector)
+	agentHandler := agent.NewHandler(container.AgentSvc)
 
 	v1 := r.Group("/api/v1")
 	{
 		v1.GET("/health", healthHandler.Check)
 
+		// Heartbeat pings authenticate with the secret token in the path instead of a JWT
+		v1.GET("/ping/:token", monitorHandler.Ping)
+		v1.POST("/ping/:token", monitorHandler.Ping)
+		v1.GET("/ping/:token/:signal", monitorHandler.Ping)
+		v1.POST("/ping/:token/:signal", monitorHandler.Ping)
+
+		// Probe agents register with the shared agent token instead of a JWT and authenticate
+		// their later calls with the credential issued at registration
+		agentGroup := v1.Group("/agents")
+		{
+			agentGroup.POST("/register", agent.TokenMiddleware(cfg.AgentToken), agentHandler.Register)
+			agentGroup.POST("/:id/jobs", agentHandler.Jobs)
+			agentGroup.POST("/:id/results", agentHandler.Results)
+		}
+
 		authGroup := v1.Group("/auth")
 		{
 			authGroup.POST("/register", authHandler.Register)
 			authGroup.POST("/login", authHandler.Login)
+			// The operator's admin token appoints the first admin; later ones are granted by admins
+			authGroup.POST("/bootstrap", auth.BootstrapMiddleware(cfg.AdminToken), authHandler.Grant)
 		}
 
 		monitorGroup := v1.Group("/monitor")
@@ -38,6 +68,73 @@ func SetupRouter(cfg *config.Config, logger *zap.Logger, container *Container) *
 			monitorGroup.POST("/add", monitorHandler.Add)
 			monitorGroup.GET("/list", monitorHandler.List)
 		}
+
+		monitorsGroup := v1.Group("/monitors")
+		monitorsGroup.Use(auth.Middleware(cfg.JwtSecret))
+		{
+			monitorsGroup.GET("/:id", monitorHandler.Get)
+			monitorsGroup.PATCH("/:id", monitorHandler.Update)
+			monitorsGroup.DELETE("/:id", monitorHandler.Delete)
+			monitorsGroup.POST("/:id/pause", monitorHandler.Pause)
+			monitorsGroup.POST("/:id/resume", monitorHandler.Resume)
+			monitorsGroup.GET("/:id/results", monitorHandler.Results)
+			monitorsGroup.GET("/:id/stats", monitorHandler.Stats)
+			monitorsGroup.GET("/:id/channels", notifyHandler.ListForMonitor)
+			monitorsGroup.POST("/:id/channels", notifyHandler.Attach)
+			monitorsGroup.DELETE("/:id/channels/:channelId", notifyHandler.Detach)
+			monitorsGroup.PUT("/:id/escalation-policy", escalationHandler.SetForMonitor)
+			monitorsGroup.DELETE("/:id/escalation-policy", escalationHandler.ClearForMonitor)
+		}
+
+		incidentGroup := v1.Group("/incidents")
+		incidentGroup.Use(auth.Middleware(cfg.JwtSecret))
+		{
+			incidentGroup.GET("", incidentHandler.List)
+			incidentGroup.GET("/:id", incidentHandler.Get)
+			incidentGroup.POST("/:id/ack", incidentHandler.Acknowledge)
+			incidentGroup.POST("/:id/resolve", incidentHandler.Resolve)
+			incidentGroup.POST("/:id/comments", incidentHandler.Comment)
+		}
+
+		channelGroup := v1.Group("/channels")
+		channelGroup.Use(auth.Middleware(cfg.JwtSecret))
+		{
+			channelGroup.POST("", notifyHandler.Create)
+			channelGroup.GET("", notifyHandler.List)
+			channelGroup.DELETE("/:id", notifyHandler.Delete)
+			channelGroup.POST("/:id/test", notifyHandler.Test)
+		}
+
+		escalationGroup := v1.Group("/escalation-policies")
+		escalationGroup.Use(auth.Middleware(cfg.JwtSecret))
+		{
+			escalationGroup.POST("", escalationHandler.Create)
+			escalationGroup.GET("", escalationHandler.List)
+			escalationGroup.GET("/:id", escalationHandler.Get)
+			escalationGroup.DELETE("/:id", escalationHandler.Delete)
+		}
+
+		maintenanceGroup := v1.Group("/maintenance-windows")
+		maintenanceGroup.Use(auth.Middleware(cfg.JwtSecret))
+		{
+			maintenanceGroup.POST("", maintenanceHandler.Create)
+			maintenanceGroup.GET("", maintenanceHandler.List)
+			maintenanceGroup.GET("/:id", maintenanceHandler.Get)
+			maintenanceGroup.DELETE("/:id", maintenanceHandler.Delete)
+		}
+
+		adminGroup := v1.Group("/admin")
+		adminGroup.Use(auth.Middleware(cfg.JwtSecret), auth.RequireRole(container.AuthRepo, auth.RoleAdmin))
+		{
+			adminGroup.POST("/roles", authHandler.Grant)
+			adminGroup.GET("/scheduler", adminHandler.Scheduler)
+			adminGroup.GET("/workers", adminHandler.Workers)
+			adminGroup.PUT("/workers", adminHandler.ResizeWorkers)
+			adminGroup.GET("/leases", adminHandler.Leases)
+			adminGroup.POST("/leases/:id/release", adminHandler.ReleaseLease)
+			adminGroup.GET("/nodes", clusterHandler.Nodes)
+			adminGroup.GET("/agents", agentHandler.List)
+		}
 	}
 
 	return r
@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strconv"
+
+	"github.com/joho/godotenv"
+)
+
+// AgentConfig holds the configuration of a probe agent
+type AgentConfig struct {
+	Env          string
+	ServerURL    string
+	Token        string
+	Name         string
+	Location     string
+	Concurrency  int
+	PollInterval int
+	TLSCAFile    string
+}
+
+// LoadAgent reads the probe agent configuration from .env file and environment variables
+func LoadAgent() (*AgentConfig, error) {
+	_ = godotenv.Load()
+
+	env := os.Getenv("ENV")
+	if env == "" {
+		env = "development"
+	}
+
+	serverURL := os.Getenv("SERVER_URL")
+	if serverURL == "" {
+		serverURL = "http://localhost:8080"
+	}
+
+	token := os.Getenv("AGENT_TOKEN")
+	if token == "" {
+		return nil, errors.New("AGENT_TOKEN environment variable is required")
+	}
+
+	location := os.Getenv("AGENT_LOCATION")
+	if location == "" {
+		return nil, errors.New("AGENT_LOCATION environment variable is required")
+	}
+
+	name := os.Getenv("AGENT_NAME")
+	if name == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			return nil, errors.New("AGENT_NAME environment variable is required")
+		}
+		name = host
+	}
+
+	concurrency := 10
+	if cStr := os.Getenv("AGENT_CONCURRENCY"); cStr != "" {
+		if parsed, err := strconv.Atoi(cStr); err == nil && parsed > 0 {
+			concurrency = parsed
+		}
+	}
+
+	pollInterval := 2
+	if piStr := os.Getenv("AGENT_POLL_INTERVAL"); piStr != "" {
+		if parsed, err := strconv.Atoi(piStr); err == nil && parsed > 0 {
+			pollInterval = parsed
+		}
+	}
+
+	return &AgentConfig{
+		Env:          env,
+		ServerURL:    serverURL,
+		Token:        token,
+		Name:         name,
+		Location:     location,
+		Concurrency:  concurrency,
+		PollInterval: pollInterval,
+		TLSCAFile:    os.Getenv("TLS_CA_FILE"),
+	}, nil
+}
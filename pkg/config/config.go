@@ -15,6 +15,17 @@ type Config struct {
 	JwtSecret         string
 	JwtExpiration     int
 	SchedulerInterval int
+	WorkerPoolSize    int
+	NodeID            string
+	LeaseTTL          int
+	LeaderTTL         int
+	FlapWindow        int
+	FlapThreshold     int
+	EscalationTick    int
+	ResultRetention   int
+	TLSCAFile         string
+	AdminToken        string
+	AgentToken        string
 	OllamaURL         string
 	LLMModel          string
 	DBHost            string
@@ -63,6 +74,65 @@ func Load() (*Config, error) {
 		}
 	}
 
+	workerPoolSize := 10
+	if wpStr := os.Getenv("WORKER_POOL_SIZE"); wpStr != "" {
+		if parsed, err := strconv.Atoi(wpStr); err == nil && parsed > 0 {
+			workerPoolSize = parsed
+		}
+	}
+
+	// Leases taken by this node are recognised by its ID, so a node restarted under the same ID
+	// releases the monitors it held when it went down
+	nodeID := os.Getenv("NODE_ID")
+	if nodeID == "" {
+		if host, err := os.Hostname(); err == nil {
+			nodeID = host
+		}
+	}
+
+	leaseTTL := 900
+	if ltStr := os.Getenv("LEASE_TTL"); ltStr != "" {
+		if parsed, err := strconv.Atoi(ltStr); err == nil && parsed > 0 {
+			leaseTTL = parsed
+		}
+	}
+
+	leaderTTL := 15
+	if ltStr := os.Getenv("LEADER_TTL"); ltStr != "" {
+		if parsed, err := strconv.Atoi(ltStr); err == nil && parsed >= 3 {
+			leaderTTL = parsed
+		}
+	}
+
+	flapWindow := 30
+	if fwStr := os.Getenv("FLAP_WINDOW"); fwStr != "" {
+		if parsed, err := strconv.Atoi(fwStr); err == nil && parsed > 0 {
+			flapWindow = parsed
+		}
+	}
+
+	flapThreshold := 5
+	if ftStr := os.Getenv("FLAP_THRESHOLD"); ftStr != "" {
+		if parsed, err := strconv.Atoi(ftStr); err == nil && parsed > 1 {
+			flapThreshold = parsed
+		}
+	}
+
+	escalationTick := 15
+	if etStr := os.Getenv("ESCALATION_INTERVAL"); etStr != "" {
+		if parsed, err := strconv.Atoi(etStr); err == nil && parsed > 0 {
+			escalationTick = parsed
+		}
+	}
+
+	// Check results are kept for this many days; 0 keeps them forever
+	resultRetention := 90
+	if rrStr := os.Getenv("RESULT_RETENTION"); rrStr != "" {
+		if parsed, err := strconv.Atoi(rrStr); err == nil && parsed >= 0 {
+			resultRetention = parsed
+		}
+	}
+
 	ollamaURL := os.Getenv("OLLAMA_URL")
 	if ollamaURL == "" {
 		ollamaURL = "http://localhost:11434/api/generate"
@@ -79,6 +149,17 @@ func Load() (*Config, error) {
 		JwtSecret:         jwtSecret,
 		JwtExpiration:     jwtExp,
 		SchedulerInterval: schedulerInterval,
+		WorkerPoolSize:    workerPoolSize,
+		NodeID:            nodeID,
+		LeaseTTL:          leaseTTL,
+		LeaderTTL:         leaderTTL,
+		FlapWindow:        flapWindow,
+		FlapThreshold:     flapThreshold,
+		EscalationTick:    escalationTick,
+		ResultRetention:   resultRetention,
+		TLSCAFile:         os.Getenv("TLS_CA_FILE"),
+		AdminToken:        os.Getenv("ADMIN_TOKEN"),
+		AgentToken:        os.Getenv("AGENT_TOKEN"),
 		OllamaURL:         ollamaURL,
 		LLMModel:          llmModel,
 		DBHost:            os.Getenv("DB_HOST"),
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// Connect opens a postgres connection pool and waits for the database to accept connections
+func Connect(dsn string) (*sql.DB, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Backoff mechanism to ensure backend explicitly waits for dockerized database readiness
+	var pingErr error
+	for i := 0; i < 10; i++ {
+		pingErr = db.Ping()
+		if pingErr == nil {
+			break
+		}
+		time.Sleep(2 * time.Second)
+	}
+
+	if pingErr != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to reach database after retries: %w", pingErr)
+	}
+
+	return db, nil
+}